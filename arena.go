@@ -0,0 +1,101 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+// #include "value.h"
+import "C"
+import "unsafe"
+
+// Arena collects values so that they can be released together, in a single
+// call into V8, rather than one by one with Value.Release.
+//
+// Values are otherwise retained by the Context that created them until the
+// Context is closed; an Arena bounds their lifetime to a shorter unit of work,
+// such as handling one request in a long lived Context.
+type Arena struct {
+	ctx  *Context
+	vals []*Value
+}
+
+// Arena calls fn with a new Arena, and releases all the values tracked by the
+// Arena once fn returns. Values tracked by the Arena must not be used after fn
+// returns.
+func (c *Context) Arena(fn func(a *Arena)) {
+	a := &Arena{ctx: c}
+	defer a.release()
+	fn(a)
+}
+
+// NewValue creates a primitive value, like the package level NewValue, and
+// tracks it in the Arena.
+func (a *Arena) NewValue(val interface{}) (*Value, error) {
+	v, err := NewValue(a.ctx.iso, val)
+	if err != nil {
+		return nil, err
+	}
+	a.vals = append(a.vals, v)
+	return v, nil
+}
+
+// Track adds values obtained elsewhere, e.g. the result of RunScript, to the
+// Arena so they are released along with the values it created. The isolate's
+// shared Undefined and Null values are never released, so tracking them is
+// harmless.
+func (a *Arena) Track(vals ...*Value) {
+	a.vals = append(a.vals, vals...)
+}
+
+func (a *Arena) release() {
+	releaseValues(a.vals)
+	a.vals = nil
+}
+
+// Releasable is implemented by the types whose resources can be released
+// before they are garbage collected, such as Value, Object and
+// FunctionCallbackInfo.
+type Releasable interface {
+	Release()
+}
+
+// Release releases all the given values. Values, and the types built on a
+// Value such as Object, are released together in a single call into V8; other
+// Releasables are released one by one. As with Value.Release, releasing a
+// value more than once is a no-op. nil entries and the isolate's shared
+// Undefined and Null values are skipped.
+func Release(values ...Releasable) {
+	vals := make([]*Value, 0, len(values))
+	for _, r := range values {
+		switch r := r.(type) {
+		case nil:
+		case Valuer:
+			vals = append(vals, r.value())
+		default:
+			r.Release()
+		}
+	}
+	releaseValues(vals)
+}
+
+// releaseValues releases vals in a single call into V8. nil values are
+// skipped, and values that Value.Release would not release, such as values
+// already released or given twice.
+func releaseValues(vals []*Value) {
+	if len(vals) == 0 {
+		return
+	}
+	ptrs := make([]C.ValuePtr, 0, len(vals))
+	for _, v := range vals {
+		if v == nil {
+			continue
+		}
+		if ptr := v.take(); ptr != nil {
+			ptrs = append(ptrs, ptr)
+		}
+	}
+	if len(ptrs) == 0 {
+		return
+	}
+	C.ValuesRelease((*C.ValuePtr)(unsafe.Pointer(&ptrs[0])), C.int(len(ptrs)))
+}
@@ -0,0 +1,133 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"testing"
+
+	v8 "github.com/lizc2003/v8go"
+)
+
+func TestContextArena(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	ctx := v8.NewContext(iso)
+	defer ctx.Close()
+
+	before := ctx.RetainedValueCount()
+	ctx.Arena(func(a *v8.Arena) {
+		for i := 0; i < 10; i++ {
+			val, err := ctx.RunScript("({ answer: 42 })", "")
+			fatalIf(t, err)
+			a.Track(val)
+		}
+		// Values released early or tracked twice are only released once.
+		extra, err := ctx.RunScript("({})", "")
+		fatalIf(t, err)
+		a.Track(extra, extra, v8.Undefined(iso))
+		extra.Release()
+		str, err := a.NewValue("foo")
+		fatalIf(t, err)
+		if str.String() != "foo" {
+			t.Errorf("unexpected value: %q", str)
+		}
+		if n := ctx.RetainedValueCount(); n != before+10 {
+			t.Errorf("expected %d retained values inside the arena, got %d", before+10, n)
+		}
+	})
+	if n := ctx.RetainedValueCount(); n != before {
+		t.Errorf("expected arena values to be released, got %d retained values, want %d", n, before)
+	}
+}
+
+func TestRelease(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	ctx := v8.NewContext(iso)
+	defer ctx.Close()
+
+	before := ctx.RetainedValueCount()
+	val, err := ctx.RunScript("42", "")
+	fatalIf(t, err)
+	obj, err := ctx.RunScript("({})", "")
+	fatalIf(t, err)
+	fn, err := ctx.RunScript("(() => {})", "")
+	fatalIf(t, err)
+	f, err := fn.AsFunction()
+	fatalIf(t, err)
+	o, err := obj.AsObject()
+	fatalIf(t, err)
+	store, err := v8.NewBackingStore(iso, 8)
+	fatalIf(t, err)
+
+	v8.Release(val, o, f, val, store, nil, v8.Undefined(iso), v8.Null(iso))
+	if n := ctx.RetainedValueCount(); n != before {
+		t.Errorf("expected values to be released, got %d retained values, want %d", n, before)
+	}
+	if recoverPanic(func() { val.IsNumber() }) == nil {
+		t.Error("expected a released value to panic when used")
+	}
+	if !v8.Undefined(iso).IsUndefined() || !v8.Null(iso).IsNull() {
+		t.Error("expected the shared undefined and null values not to be released")
+	}
+}
+
+func TestFunctionCallbackInfoRelease(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	var retained int
+	fn := v8.NewFunctionTemplate(iso, func(info *v8.FunctionCallbackInfo) *v8.Value {
+		defer func() { retained = info.Context().RetainedValueCount() }()
+		defer info.Release()
+		return nil
+	})
+	global := v8.NewObjectTemplate(iso)
+	fatalIf(t, global.Set("f", fn))
+	ctx := v8.NewContext(iso, global)
+	defer ctx.Close()
+
+	before := ctx.RetainedValueCount()
+	_, err := ctx.RunScript("f(1, 'two', {})", "")
+	fatalIf(t, err)
+	if retained != before {
+		t.Errorf("expected the arguments and receiver to be released, got %d retained values, want %d", retained, before)
+	}
+}
+
+func BenchmarkValueRelease(b *testing.B) {
+	b.ReportAllocs()
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	for n := 0; n < b.N; n++ {
+		vals := make([]*v8.Value, 100)
+		for i := range vals {
+			vals[i], _ = v8.NewValue(iso, int32(i))
+		}
+		for _, v := range vals {
+			v.Release()
+		}
+	}
+}
+
+func BenchmarkArena(b *testing.B) {
+	b.ReportAllocs()
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	ctx := v8.NewContext(iso)
+	defer ctx.Close()
+	for n := 0; n < b.N; n++ {
+		ctx.Arena(func(a *v8.Arena) {
+			for i := 0; i < 100; i++ {
+				a.NewValue(int32(i))
+			}
+		})
+	}
+}
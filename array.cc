@@ -0,0 +1,56 @@
+#include "array.h"
+
+#include <algorithm>
+#include <vector>
+
+#include "deps/include/v8-container.h"
+#include "isolate-macros.h"
+#include "value-macros.h"
+#include "value.h"
+
+using namespace v8;
+
+uint32_t ArrayLength(ValuePtr ptr) {
+  LOCAL_VALUE(ptr);
+  return value.As<Array>()->Length();
+}
+
+struct arrayElements {
+  Isolate* iso;
+  m_ctx* ctx;
+  std::vector<ValuePtr> values;
+};
+
+static Array::CallbackResult ArrayCollectElement(uint32_t index,
+                                                 Local<Value> element,
+                                                 void* data) {
+  arrayElements* elements = static_cast<arrayElements*>(data);
+  m_value* val = new m_value;
+  val->id = 0;
+  val->iso = elements->iso;
+  val->ctx = elements->ctx;
+  val->ptr = Global<Value>(elements->iso, element);
+  elements->values.push_back(tracked_value(elements->ctx, val));
+  return Array::CallbackResult::kContinue;
+}
+
+RtnValues ArrayElements(ValuePtr ptr) {
+  LOCAL_VALUE(ptr);
+  RtnValues rtn = {};
+
+  Local<Array> arr = value.As<Array>();
+  arrayElements elements = {iso, ctx, {}};
+  elements.values.reserve(arr->Length());
+  if (arr->Iterate(local_ctx, ArrayCollectElement, &elements).IsNothing()) {
+    rtn.error = ExceptionError(try_catch, iso, local_ctx);
+    return rtn;
+  }
+  if (elements.values.empty()) {
+    return rtn;
+  }
+
+  rtn.count = elements.values.size();
+  rtn.values = (ValuePtr*)malloc(sizeof(ValuePtr) * rtn.count);
+  std::copy(elements.values.begin(), elements.values.end(), rtn.values);
+  return rtn;
+}
@@ -0,0 +1,58 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+// #include <stdlib.h>
+// #include "array.h"
+import "C"
+import "unsafe"
+
+// Array is a JavaScript Array object.
+type Array struct {
+	*Object
+}
+
+// Length returns the length of the array.
+func (a *Array) Length() uint32 {
+	return uint32(C.ArrayLength(a.valuePtr()))
+}
+
+// Slice returns all elements of the array as a Go slice. The elements are
+// read in a single call into V8, which is much faster than calling GetIdx for
+// each index. Holes in sparse arrays are returned as undefined.
+func (a *Array) Slice() ([]*Value, error) {
+	rtn := C.ArrayElements(a.valuePtr())
+	if rtn.error.msg != nil {
+		return nil, a.ctx.resultError(rtn.error)
+	}
+	if rtn.count == 0 {
+		return []*Value{}, nil
+	}
+	defer C.free(unsafe.Pointer(rtn.values))
+
+	ptrs := unsafe.Slice(rtn.values, rtn.count)
+	vals := make([]*Value, len(ptrs))
+	for i, ptr := range ptrs {
+		vals[i] = &Value{ptr: ptr, ctx: a.ctx}
+	}
+	return vals, nil
+}
+
+// ForEach calls fn for each element of the array, in order. Iteration stops
+// at the first error returned by fn, which is then returned by ForEach. The
+// elements are read with Slice, so changes fn makes to the array are not
+// seen by the iteration.
+func (a *Array) ForEach(fn func(i uint32, v *Value) error) error {
+	vals, err := a.Slice()
+	if err != nil {
+		return err
+	}
+	for i, v := range vals {
+		if err := fn(uint32(i), v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+#ifndef V8GO_ARRAY_H
+#define V8GO_ARRAY_H
+
+#include <stdint.h>
+
+#include "errors.h"
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+typedef struct {
+  ValuePtr* values;
+  int count;
+  RtnError error;
+} RtnValues;
+
+extern uint32_t ArrayLength(ValuePtr ptr);
+extern RtnValues ArrayElements(ValuePtr ptr);
+
+#ifdef __cplusplus
+}  // extern "C"
+#endif
+#endif
@@ -0,0 +1,105 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"errors"
+	"testing"
+
+	v8 "github.com/lizc2003/v8go"
+)
+
+func TestArraySlice(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`[1, "two", , {four: 4}]`, "array.js")
+	fatalIf(t, err)
+	arr, err := val.AsArray()
+	fatalIf(t, err)
+
+	if arr.Length() != 4 {
+		t.Errorf("expected length 4, got %d", arr.Length())
+	}
+	vals, err := arr.Slice()
+	fatalIf(t, err)
+	if len(vals) != 4 {
+		t.Fatalf("expected 4 elements, got %d", len(vals))
+	}
+	if vals[0].Int32() != 1 || vals[1].String() != "two" || !vals[2].IsUndefined() || !vals[3].IsObject() {
+		t.Errorf("unexpected elements: %v", vals)
+	}
+
+	val, err = ctx.RunScript(`[]`, "array.js")
+	fatalIf(t, err)
+	arr, err = val.AsArray()
+	fatalIf(t, err)
+	vals, err = arr.Slice()
+	fatalIf(t, err)
+	if len(vals) != 0 {
+		t.Errorf("expected no elements, got %d", len(vals))
+	}
+
+	val, err = ctx.RunScript(`({length: 1})`, "array.js")
+	fatalIf(t, err)
+	if _, err := val.AsArray(); err == nil {
+		t.Error("expected error casting an array-like object to Array")
+	}
+}
+
+func TestArrayForEach(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`[10, 20, 30]`, "array.js")
+	fatalIf(t, err)
+	arr, err := val.AsArray()
+	fatalIf(t, err)
+
+	var sum int32
+	err = arr.ForEach(func(i uint32, v *v8.Value) error {
+		sum += int32(i) * v.Int32()
+		return nil
+	})
+	fatalIf(t, err)
+	if sum != 80 {
+		t.Errorf("expected sum 80, got %d", sum)
+	}
+
+	errStop := errors.New("stop")
+	var visited int
+	err = arr.ForEach(func(i uint32, v *v8.Value) error {
+		visited++
+		if i == 1 {
+			return errStop
+		}
+		return nil
+	})
+	if err != errStop || visited != 2 {
+		t.Errorf("expected ForEach to stop at the first error, got %v after %d elements", err, visited)
+	}
+}
+
+func BenchmarkArraySlice(b *testing.B) {
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, _ := ctx.RunScript(`Array.from({length: 1000}, (_, i) => i)`, "array.js")
+	arr, _ := val.AsArray()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		vals, _ := arr.Slice()
+		for _, v := range vals {
+			v.Release()
+		}
+	}
+}
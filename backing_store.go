@@ -0,0 +1,86 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+// #include "v8go.h"
+import "C"
+import (
+	"errors"
+	"unsafe"
+)
+
+// BackingStore is a block of memory that can back one or more ArrayBuffers.
+// Reusing a BackingStore, e.g. from a pool, for the buffers handed to JS
+// avoids allocating new memory for each of them.
+//
+// The memory stays allocated while the BackingStore or any ArrayBuffer using
+// it is alive; call Release once the Go side no longer needs it.
+type BackingStore struct {
+	ptr C.BackingStorePtr
+}
+
+// NewBackingStore allocates a zero-filled BackingStore of byteLength bytes
+// using the isolate's ArrayBuffer allocator. An error is returned if
+// byteLength is negative.
+func NewBackingStore(iso *Isolate, byteLength int) (*BackingStore, error) {
+	if iso == nil {
+		panic("nil Isolate argument not supported")
+	}
+	if byteLength < 0 {
+		return nil, errors.New("v8go: BackingStore byteLength cannot be negative")
+	}
+	return &BackingStore{C.NewBackingStore(iso.ptr, C.size_t(byteLength))}, nil
+}
+
+// Bytes returns the memory of the BackingStore. The slice aliases the memory
+// seen by ArrayBuffers using the store, and must not be used after Release.
+func (b *BackingStore) Bytes() []byte {
+	data := C.BackingStoreData(b.ptr)
+	if data == nil {
+		return nil
+	}
+	return unsafe.Slice((*byte)(data), b.ByteLength())
+}
+
+// ByteLength returns the size of the BackingStore in bytes.
+func (b *BackingStore) ByteLength() int {
+	return int(C.BackingStoreByteLength(b.ptr))
+}
+
+// Release drops the Go reference to the BackingStore. The memory is freed once
+// no ArrayBuffer uses it any more.
+func (b *BackingStore) Release() {
+	C.BackingStoreRelease(b.ptr)
+	b.ptr = nil
+}
+
+// NewArrayBuffer creates an ArrayBuffer in ctx that uses the memory of the
+// given BackingStore, without copying it. Several ArrayBuffers may share one
+// BackingStore; to hand the memory to a new buffer safely, detach the previous
+// one with DetachArrayBuffer first so scripts can no longer access it.
+func NewArrayBuffer(ctx *Context, store *BackingStore) (*Value, error) {
+	if ctx == nil {
+		return nil, errors.New("v8go: Context is required")
+	}
+	if store == nil || store.ptr == nil {
+		return nil, errors.New("v8go: BackingStore is required")
+	}
+	rtn := C.NewArrayBufferWithBackingStore(ctx.ptr, store.ptr)
+	return valueResult(ctx, rtn)
+}
+
+// DetachArrayBuffer detaches an ArrayBuffer from its memory, setting the byte
+// length of the buffer and all typed arrays viewing it to zero. An error is
+// returned if the value is not an ArrayBuffer or cannot be detached, e.g.
+// because it is a WebAssembly memory buffer.
+func (v *Value) DetachArrayBuffer() error {
+	if !v.IsArrayBuffer() {
+		return errors.New("v8go: value is not an ArrayBuffer")
+	}
+	if C.ArrayBufferDetach(v.valuePtr()) == 0 {
+		return errors.New("v8go: ArrayBuffer is not detachable")
+	}
+	return nil
+}
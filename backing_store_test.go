@@ -0,0 +1,67 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"testing"
+
+	v8 "github.com/lizc2003/v8go"
+)
+
+func TestBackingStoreReuse(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	ctx := v8.NewContext(iso)
+	defer ctx.Close()
+
+	store, err := v8.NewBackingStore(iso, 4)
+	fatalIf(t, err)
+	defer store.Release()
+	if store.ByteLength() != 4 {
+		t.Fatalf("expected 4 bytes, got %d", store.ByteLength())
+	}
+
+	for i, want := range []string{"1,2,3,4", "5,6,7,8"} {
+		copy(store.Bytes(), []byte{byte(4*i + 1), byte(4*i + 2), byte(4*i + 3), byte(4*i + 4)})
+
+		buf, err := v8.NewArrayBuffer(ctx, store)
+		fatalIf(t, err)
+		if !buf.IsArrayBuffer() {
+			t.Fatal("expected an ArrayBuffer")
+		}
+		fatalIf(t, ctx.Global().Set("buf", buf))
+		val, err := ctx.RunScript("new Uint8Array(buf).join()", "buf.js")
+		fatalIf(t, err)
+		if val.String() != want {
+			t.Errorf("expected %q, got %q", want, val.String())
+		}
+
+		fatalIf(t, buf.DetachArrayBuffer())
+		val, err = ctx.RunScript("buf.byteLength", "buf.js")
+		fatalIf(t, err)
+		if val.Int32() != 0 {
+			t.Errorf("expected detached buffer to be empty, got %d bytes", val.Int32())
+		}
+	}
+
+	val, err := ctx.RunScript("({})", "buf.js")
+	fatalIf(t, err)
+	if err := val.DetachArrayBuffer(); err == nil {
+		t.Error("expected error detaching a non-ArrayBuffer")
+	}
+}
+
+func TestBackingStoreNegativeLength(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	if _, err := v8.NewBackingStore(iso, -1); err == nil {
+		t.Error("expected an error for a negative byteLength")
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+import (
+	"encoding/base64"
+	"strings"
+	"unicode/utf8"
+)
+
+// EnableBase64 defines the global `atob` and `btoa` functions of the HTML
+// standard in the context, which V8 leaves to the embedder. btoa encodes a
+// string of code points up to U+00FF, i.e. a binary string, as base64, and
+// atob decodes base64, ignoring ASCII whitespace, to a binary string. As in
+// browsers, invalid input throws an error named "InvalidCharacterError".
+func (c *Context) EnableBase64() error {
+	iso := c.iso
+	atob := NewFunctionTemplateWithError(iso, func(info *FunctionCallbackInfo) (*Value, error) {
+		s, err := base64Arg(c, info, "atob")
+		if err != nil {
+			return nil, err
+		}
+		b, ok := decodeBase64(s)
+		if !ok {
+			return nil, newInvalidCharacterError(c, "Failed to execute 'atob': The string to be decoded is not correctly encoded.")
+		}
+		return NewValue(iso, latin1String(b))
+	}).GetFunction(c)
+	btoa := NewFunctionTemplateWithError(iso, func(info *FunctionCallbackInfo) (*Value, error) {
+		s, err := base64Arg(c, info, "btoa")
+		if err != nil {
+			return nil, err
+		}
+		b, ok := latin1Bytes(s)
+		if !ok {
+			return nil, newInvalidCharacterError(c, "Failed to execute 'btoa': The string to be encoded contains characters outside of the Latin1 range.")
+		}
+		return NewValue(iso, base64.StdEncoding.EncodeToString(b))
+	}).GetFunction(c)
+
+	global := c.Global()
+	if err := global.Set("atob", atob); err != nil {
+		return err
+	}
+	return global.Set("btoa", btoa)
+}
+
+// base64Arg returns the first argument of atob or btoa converted to a string
+// as by `String(value)`.
+func base64Arg(ctx *Context, info *FunctionCallbackInfo, name string) (string, error) {
+	args := info.Args()
+	if len(args) == 0 {
+		return "", newErrorException(ctx, ErrorKindType, name+": 1 argument required, but only 0 present")
+	}
+	s, err := args[0].ToString(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer s.Release()
+	return s.String(), nil
+}
+
+// newInvalidCharacterError returns an error named "InvalidCharacterError" to
+// throw from a callback.
+func newInvalidCharacterError(ctx *Context, msg string) error {
+	return newDOMException(ctx, "InvalidCharacterError", 5, msg)
+}
+
+// decodeBase64 decodes s with the forgiving-base64 decode algorithm of the
+// Infra standard. It returns false if s is not valid base64.
+func decodeBase64(s string) ([]byte, bool) {
+	s = strings.Map(func(r rune) rune {
+		switch r {
+		case '\t', '\n', '\f', '\r', ' ':
+			return -1
+		}
+		return r
+	}, s)
+	if len(s)%4 == 0 {
+		s = strings.TrimSuffix(s, "=")
+		s = strings.TrimSuffix(s, "=")
+	}
+	if len(s)%4 == 1 {
+		return nil, false
+	}
+	// RawStdEncoding rejects '=' and other characters outside the alphabet, and
+	// ignores the unused bits of the final character, as the algorithm does.
+	b, err := base64.RawStdEncoding.DecodeString(s)
+	return b, err == nil
+}
+
+// latin1Bytes returns the code points of s as bytes, or false if s has a code
+// point above U+00FF.
+func latin1Bytes(s string) ([]byte, bool) {
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		if r > 0xff {
+			return nil, false
+		}
+		b = append(b, byte(r))
+	}
+	return b, true
+}
+
+// latin1String returns the string with the bytes of b as its code points.
+func latin1String(b []byte) string {
+	var sb strings.Builder
+	sb.Grow(len(b))
+	for _, c := range b {
+		if c < utf8.RuneSelf {
+			sb.WriteByte(c)
+		} else {
+			sb.WriteRune(rune(c))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,47 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"testing"
+
+	v8 "github.com/lizc2003/v8go"
+)
+
+func TestContextEnableBase64(t *testing.T) {
+	t.Parallel()
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	fatalIf(t, ctx.EnableBase64())
+
+	tests := [...]struct {
+		source string
+		want   string
+	}{
+		{`btoa("hello")`, "aGVsbG8="},
+		{`btoa("")`, ""},
+		{`btoa("\xff\xfe")`, "//4="},
+		{`btoa(undefined)`, "dW5kZWZpbmVk"},
+		{`atob("aGVsbG8=")`, "hello"},
+		{`atob(" aGVs\nbG8 ")`, "hello"},
+		{`atob("aGVsbG8")`, "hello"},
+		{`Array.from(atob("//4="), (c) => c.charCodeAt(0)).join(",")`, "255,254"},
+		{`atob(btoa("\x00\x80\xe9")) === "\x00\x80\xe9"`, "true"},
+		{`try { btoa("€") } catch (e) { [e instanceof Error, e.name, e.code].join("|") }`, "true|InvalidCharacterError|5"},
+		{`try { atob("a") } catch (e) { e.name }`, "InvalidCharacterError"},
+		{`try { atob("aGVs*G8=") } catch (e) { e.name }`, "InvalidCharacterError"},
+		{`try { atob("aG=Vs") } catch (e) { e.name }`, "InvalidCharacterError"},
+		{`try { atob() } catch (e) { e instanceof TypeError }`, "true"},
+	}
+	for _, tt := range tests {
+		val, err := ctx.RunScript(tt.source, "base64.js")
+		fatalIf(t, err)
+		if got := val.String(); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.source, got, tt.want)
+		}
+	}
+}
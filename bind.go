@@ -0,0 +1,251 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+// #include "context.h"
+// #include "value.h"
+import "C"
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"unicode"
+	"unicode/utf8"
+	"unsafe"
+)
+
+// maxSafeInteger is Number.MAX_SAFE_INTEGER; larger integers are returned to
+// JS as a BigInt so they don't lose precision.
+const maxSafeInteger = 1<<53 - 1
+
+var (
+	errorType = reflect.TypeOf((*error)(nil)).Elem()
+	valueType = reflect.TypeOf((*Value)(nil))
+	bytesType = reflect.TypeOf([]byte(nil))
+)
+
+// BindStruct creates an ObjectTemplate with a method for each exported method
+// of v, so a Go service can be exposed to JS by setting an instance of the
+// template on the global object. Method names start with a lower case letter
+// in JS, e.g. `GetUser` becomes `getUser`. As with Go method sets, the pointer
+// receiver methods of a struct are only bound if v is a pointer.
+//
+// Method parameters may be of type string, bool, any integer or floating
+// point type, []byte, which is copied from an ArrayBuffer or typed array, or
+// *Value, which receives the JS argument unconverted. A method may return
+// nothing, an error, a value or a value and an error; values are converted
+// like parameters, with []byte returned as a Uint8Array and integers that
+// don't fit a JS number exactly returned as a BigInt. A JS call with too few
+// arguments or arguments of the wrong type throws a TypeError, and a non-nil
+// error returned by the method is thrown as a JS error.
+//
+// An error is returned if v has a method with an unsupported signature.
+func BindStruct(iso *Isolate, v interface{}) (*ObjectTemplate, error) {
+	if iso == nil {
+		return nil, errors.New("v8go: Isolate is required")
+	}
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return nil, errors.New("v8go: cannot bind <nil>")
+	}
+
+	tmpl := NewObjectTemplate(iso)
+	rt := rv.Type()
+	for i := 0; i < rt.NumMethod(); i++ {
+		m := rt.Method(i)
+		cb, err := bindMethod(iso, m.Name, rv.Method(i))
+		if err != nil {
+			return nil, err
+		}
+		if err := tmpl.Set(jsMethodName(m.Name), NewFunctionTemplateWithError(iso, cb)); err != nil {
+			return nil, err
+		}
+	}
+	return tmpl, nil
+}
+
+func jsMethodName(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToLower(r)) + name[size:]
+}
+
+func bindMethod(iso *Isolate, name string, fn reflect.Value) (FunctionCallbackWithError, error) {
+	ft := fn.Type()
+	if ft.IsVariadic() {
+		return nil, fmt.Errorf("v8go: cannot bind variadic method %s", name)
+	}
+	for i := 0; i < ft.NumIn(); i++ {
+		if !isBindableType(ft.In(i)) {
+			return nil, fmt.Errorf("v8go: cannot bind method %s: unsupported parameter type %s", name, ft.In(i))
+		}
+	}
+	switch ft.NumOut() {
+	case 0:
+	case 1:
+		if ft.Out(0) != errorType && !isBindableType(ft.Out(0)) {
+			return nil, fmt.Errorf("v8go: cannot bind method %s: unsupported result type %s", name, ft.Out(0))
+		}
+	case 2:
+		if !isBindableType(ft.Out(0)) || ft.Out(1) != errorType {
+			return nil, fmt.Errorf("v8go: cannot bind method %s: results must be (value, error)", name)
+		}
+	default:
+		return nil, fmt.Errorf("v8go: cannot bind method %s: too many results", name)
+	}
+
+	return func(info *FunctionCallbackInfo) (*Value, error) {
+		args := info.Args()
+		if len(args) < ft.NumIn() {
+			return nil, NewTypeError(iso, fmt.Sprintf("%s expects %d arguments, got %d", jsMethodName(name), ft.NumIn(), len(args)))
+		}
+		in := make([]reflect.Value, ft.NumIn())
+		for i := range in {
+			arg, err := fromJSValue(args[i], ft.In(i))
+			if err != nil {
+				return nil, NewTypeError(iso, fmt.Sprintf("%s: argument %d: %v", jsMethodName(name), i, err))
+			}
+			in[i] = arg
+		}
+
+		out := fn.Call(in)
+		if n := len(out); n > 0 && ft.Out(n-1) == errorType {
+			if err, _ := out[n-1].Interface().(error); err != nil {
+				return nil, err
+			}
+			out = out[:n-1]
+		}
+		if len(out) == 0 {
+			return nil, nil
+		}
+		return toJSValue(info.Context(), out[0])
+	}, nil
+}
+
+func isBindableType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return t == bytesType || t == valueType
+}
+
+func fromJSValue(v *Value, t reflect.Type) (reflect.Value, error) {
+	if t == valueType {
+		return reflect.ValueOf(v), nil
+	}
+	if t == bytesType {
+		if !v.IsArrayBuffer() && !v.IsArrayBufferView() {
+			return reflect.Value{}, errors.New("expected an ArrayBuffer or typed array")
+		}
+		return reflect.ValueOf(copyBytes(v)), nil
+	}
+
+	rv := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.String:
+		if !v.IsString() {
+			return reflect.Value{}, errors.New("expected a string")
+		}
+		rv.SetString(v.String())
+	case reflect.Bool:
+		if !v.IsBoolean() {
+			return reflect.Value{}, errors.New("expected a boolean")
+		}
+		rv.SetBool(v.Boolean())
+	case reflect.Float32, reflect.Float64:
+		if !v.IsNumber() {
+			return reflect.Value{}, errors.New("expected a number")
+		}
+		rv.SetFloat(v.Number())
+	default:
+		if !v.IsNumber() {
+			return reflect.Value{}, errors.New("expected a number")
+		}
+		f := v.Number()
+		if f != math.Trunc(f) {
+			return reflect.Value{}, errors.New("expected an integer")
+		}
+		switch t.Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if f < 0 || f >= math.MaxUint64 || rv.OverflowUint(uint64(f)) {
+				return reflect.Value{}, fmt.Errorf("%v overflows %s", f, t)
+			}
+			rv.SetUint(uint64(f))
+		default:
+			if f < math.MinInt64 || f >= math.MaxInt64 || rv.OverflowInt(int64(f)) {
+				return reflect.Value{}, fmt.Errorf("%v overflows %s", f, t)
+			}
+			rv.SetInt(int64(f))
+		}
+	}
+	return rv, nil
+}
+
+func toJSValue(ctx *Context, rv reflect.Value) (*Value, error) {
+	iso := ctx.iso
+	switch t := rv.Type(); {
+	case t == valueType:
+		return rv.Interface().(*Value), nil
+	case t == bytesType:
+		return newUint8Array(ctx, rv.Bytes())
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		return NewValue(iso, rv.String())
+	case reflect.Bool:
+		return NewValue(iso, rv.Bool())
+	case reflect.Float32, reflect.Float64:
+		return NewValue(iso, rv.Float())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if u := rv.Uint(); u > maxSafeInteger {
+			return NewValue(iso, u)
+		}
+		return NewValue(iso, float64(rv.Uint()))
+	default:
+		if i := rv.Int(); i > maxSafeInteger || i < -maxSafeInteger {
+			return NewValue(iso, i)
+		}
+		return NewValue(iso, float64(rv.Int()))
+	}
+}
+
+func copyBytes(v *Value) []byte {
+	n := C.ValueCopyBytes(v.valuePtr(), nil, 0)
+	buf := make([]byte, int(n))
+	if n > 0 {
+		C.ValueCopyBytes(v.valuePtr(), unsafe.Pointer(&buf[0]), n)
+	}
+	return buf
+}
+
+// newUint8Array returns a Uint8Array holding a copy of b. Only the array is
+// retained; the ArrayBuffer it views is released.
+func newUint8Array(ctx *Context, b []byte) (*Value, error) {
+	store, err := NewBackingStore(ctx.iso, len(b))
+	if err != nil {
+		return nil, err
+	}
+	defer store.Release()
+	copy(store.Bytes(), b)
+	buf, err := NewArrayBuffer(ctx, store)
+	if err != nil {
+		return nil, err
+	}
+	defer buf.Release()
+	ctor, err := ctx.intrinsic(C.INTRINSIC_UINT8_ARRAY)
+	if err != nil {
+		return nil, err
+	}
+	arr, err := ctor.NewInstance(buf)
+	if err != nil {
+		return nil, err
+	}
+	return arr.Value, nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	v8 "github.com/lizc2003/v8go"
+)
+
+type greeter struct {
+	prefix string
+	last   []byte
+}
+
+func (g *greeter) Greet(name string, times int) string {
+	return strings.Repeat(g.prefix+name, times)
+}
+
+func (g *greeter) Half(f float64) float64 { return f / 2 }
+
+func (g *greeter) Store(b []byte) { g.last = b }
+
+func (g *greeter) Upper(b []byte) []byte { return bytes.ToUpper(b) }
+
+func (g *greeter) Fail(fail bool) (bool, error) {
+	if fail {
+		return false, errors.New("failed on purpose")
+	}
+	return true, nil
+}
+
+func TestBindStruct(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	ctx := v8.NewContext(iso)
+	defer ctx.Close()
+
+	g := &greeter{prefix: "hi "}
+	tmpl, err := v8.BindStruct(iso, g)
+	fatalIf(t, err)
+	obj, err := tmpl.NewInstance(ctx)
+	fatalIf(t, err)
+	fatalIf(t, ctx.Global().Set("greeter", obj))
+
+	tests := [...]struct {
+		source string
+		want   string
+	}{
+		{`greeter.greet("bob", 2)`, "hi bobhi bob"},
+		{`greeter.half(3)`, "1.5"},
+		{`greeter.fail(false)`, "true"},
+		{`Array.from(greeter.upper(new Uint8Array([97, 98, 99]))).join()`, "65,66,67"},
+		{`greeter.upper(new Uint8Array(0)).length`, "0"},
+	}
+	for _, tt := range tests {
+		val, err := ctx.RunScript(tt.source, "bind.js")
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.source, err)
+			continue
+		}
+		if got := val.String(); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.source, got, tt.want)
+		}
+	}
+
+	_, err = ctx.RunScript(`greeter.store(new Uint8Array([1, 2, 3]).buffer)`, "bind.js")
+	fatalIf(t, err)
+	if !bytes.Equal(g.last, []byte{1, 2, 3}) {
+		t.Errorf("unexpected bytes stored: %v", g.last)
+	}
+
+	errTests := [...]struct {
+		source string
+		want   string
+	}{
+		{`greeter.greet("bob")`, "TypeError: greet expects 2 arguments, got 1"},
+		{`greeter.greet(1, 2)`, "TypeError: greet: argument 0: expected a string"},
+		{`greeter.greet("bob", 1.5)`, "TypeError: greet: argument 1: expected an integer"},
+		{`greeter.fail(true)`, "failed on purpose"},
+	}
+	for _, tt := range errTests {
+		_, err := ctx.RunScript(tt.source, "bind.js")
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.source, tt.want, err)
+		}
+	}
+}
+
+type unbindable struct{}
+
+func (unbindable) Map(m map[string]string) {}
+
+func TestBindStructUnsupported(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	if _, err := v8.BindStruct(iso, unbindable{}); err == nil {
+		t.Error("expected error binding a method with a map parameter")
+	}
+	if _, err := v8.BindStruct(iso, nil); err == nil {
+		t.Error("expected error binding <nil>")
+	}
+}
@@ -7,10 +7,11 @@
 #include "deps/include/v8-locker.h"
 
 #include "context.h"
+#include "isolate-macros.h"
 
 #define LOCAL_CONTEXT(ctx)                              \
   v8::Isolate* iso = ctx->iso;                          \
-  v8::Locker locker(iso);                               \
+  IsolateLocker locker(iso);                            \
   v8::Isolate::Scope isolate_scope(iso);                \
   v8::HandleScope handle_scope(iso);                    \
   v8::TryCatch try_catch(iso);                          \
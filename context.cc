@@ -1,16 +1,69 @@
+#include "deps/include/v8-debug.h"
+#include "deps/include/v8-json.h"
 #include "deps/include/v8-template.h"
 
 #include "context-macros.h"
+#include "isolate-macros.h"
+#include "module.h"
 #include "template.h"
 #include "unbound_script.h"
+#include "utils.h"
 #include "value.h"
 
 using namespace v8;
 
+// The property paths of the intrinsics from the global object, indexed by
+// IntrinsicIndex.
+static const std::vector<const char*> intrinsic_paths[INTRINSIC_COUNT] = {
+    {"Object", "isExtensible"},
+    {"Object", "preventExtensions"},
+    {"Uint8Array"},
+    {"FinalizationRegistry"},
+    {"WeakMap"},
+    {"WeakMap", "prototype", "get"},
+    {"WeakMap", "prototype", "set"},
+    {"WeakMap", "prototype", "has"},
+    {"WeakMap", "prototype", "delete"},
+    {"WeakSet"},
+    {"WeakSet", "prototype", "add"},
+    {"WeakSet", "prototype", "has"},
+    {"WeakSet", "prototype", "delete"},
+    {"Math"},
+    {"WeakRef", "prototype", "deref"},
+    {"Function", "prototype", "bind"},
+};
+
+// ResolveIntrinsics reads the intrinsics from the global object of a context.
+// Interceptors of the global template are skipped, so the lookup can't call
+// back into Go.
+static void ResolveIntrinsics(Isolate* iso,
+                              Local<Context> local_ctx,
+                              m_ctx* ctx) {
+  TryCatch try_catch(iso);
+  for (int i = 0; i < INTRINSIC_COUNT; i++) {
+    Local<Value> val = local_ctx->Global();
+    for (const char* name : intrinsic_paths[i]) {
+      Local<String> key;
+      if (!val->IsObject() ||
+          !String::NewFromUtf8(iso, name).ToLocal(&key) ||
+          !val.As<Object>()->GetRealNamedProperty(local_ctx, key).ToLocal(
+              &val)) {
+        val.Clear();
+        break;
+      }
+    }
+    if (!val.IsEmpty()) {
+      ctx->intrinsics[i].Reset(iso, val);
+    }
+  }
+  ctx->intrinsics_resolved = true;
+}
+
 ContextPtr NewContext(IsolatePtr iso,
                       TemplatePtr global_template_ptr,
+                      ValuePtr global_object_ptr,
                       int ref) {
-  Locker locker(iso);
+  IsolateLocker locker(iso);
   Isolate::Scope isolate_scope(iso);
   HandleScope handle_scope(iso);
 
@@ -21,12 +74,18 @@ ContextPtr NewContext(IsolatePtr iso,
     global_template = ObjectTemplate::New(iso);
   }
 
+  MaybeLocal<Value> global_object;
+  if (global_object_ptr != nullptr) {
+    global_object = global_object_ptr->ptr.Get(iso);
+  }
+
   // For function callbacks we need a reference to the context, but because of
   // the complexities of C -> Go function pointers, we store a reference to the
   // context as a simple integer identifier; this can then be used on the Go
   // side to lookup the context in the context registry. We use slot 1 as slot 0
   // has special meaning for the Chrome debugger.
-  Local<Context> local_ctx = Context::New(iso, nullptr, global_template);
+  Local<Context> local_ctx =
+      Context::New(iso, nullptr, global_template, global_object);
   local_ctx->SetEmbedderData(1, Integer::New(iso, ref));
 
   m_ctx* ctx = new m_ctx;
@@ -35,11 +94,30 @@ ContextPtr NewContext(IsolatePtr iso,
   return ctx;
 }
 
+ValuePtr ContextDetachGlobal(ContextPtr ctx) {
+  LOCAL_CONTEXT(ctx);
+  Local<Object> global = local_ctx->Global();
+  local_ctx->DetachGlobal();
+
+  // Track the detached global proxy in the isolate's internal context, so it
+  // stays valid after this context is closed.
+  m_ctx* internal_ctx = isolateInternalContext(iso);
+  m_value* val = new m_value;
+  val->id = 0;
+  val->iso = iso;
+  val->ctx = internal_ctx;
+  val->ptr = Global<Value>(iso, global);
+  return tracked_value(internal_ctx, val);
+}
+
 void ContextFree(ContextPtr ctx) {
   if (ctx == nullptr) {
     return;
   }
   ctx->ptr.Reset();
+  for (Global<Value>& intrinsic : ctx->intrinsics) {
+    intrinsic.Reset();
+  }
 
   for (auto it = ctx->vals.begin(); it != ctx->vals.end(); ++it) {
     auto value = it->second;
@@ -53,6 +131,12 @@ void ContextFree(ContextPtr ctx) {
     delete us;
   }
 
+  for (auto it = ctx->modules.begin(); it != ctx->modules.end(); ++it) {
+    m_module* mod = it->second;
+    mod->ptr.Reset();
+    delete mod;
+  }
+
   delete ctx;
 }
 
@@ -80,33 +164,132 @@ ValuePtr ContextGlobal(ContextPtr ctx) {
   return tracked_value(ctx, val);
 }
 
-int ContextRetainedValueCount(ContextPtr ctx) {
-  return ctx->vals.size();
+ValuePtr ContextIntrinsic(ContextPtr ctx, IntrinsicIndex intrinsic) {
+  LOCAL_CONTEXT(ctx);
+  // All of the intrinsics are read at once, so a script can't replace the
+  // ones v8go hasn't used yet once it relies on any of them.
+  if (!ctx->intrinsics_resolved) {
+    ResolveIntrinsics(iso, local_ctx, ctx);
+  }
+  if (ctx->intrinsics[intrinsic].IsEmpty()) {
+    return nullptr;
+  }
+  m_value* val = new m_value;
+  val->id = 0;
+  val->iso = iso;
+  val->ctx = ctx;
+  val->ptr = Global<Value>(iso, ctx->intrinsics[intrinsic].Get(iso));
+  return tracked_value(ctx, val);
 }
 
-RtnValue RunScript(ContextPtr ctx, const char* source, const char* origin) {
+RtnError ContextDefineGlobal(ContextPtr ctx,
+                             const char* name,
+                             ValuePtr val,
+                             int attributes) {
   LOCAL_CONTEXT(ctx);
+  RtnError rtn = {};
+
+  Local<String> key;
+  if (!String::NewFromUtf8(iso, name, NewStringType::kNormal).ToLocal(&key)) {
+    return ExceptionError(try_catch, iso, local_ctx);
+  }
+  Maybe<bool> defined = local_ctx->Global()->DefineOwnProperty(
+      local_ctx, key, val->ptr.Get(iso), (PropertyAttribute)attributes);
+  if (defined.IsNothing()) {
+    return ExceptionError(try_catch, iso, local_ctx);
+  }
+  if (!defined.FromJust()) {
+    rtn.msg = CopyString("TypeError: Cannot redefine property: " +
+                         std::string(name));
+  }
+  return rtn;
+}
 
+RtnValue ContextNewObject(ContextPtr ctx,
+                          const char** names,
+                          ValuePtr* values,
+                          int length) {
+  LOCAL_CONTEXT(ctx);
   RtnValue rtn = {};
 
-  MaybeLocal<String> maybeSrc =
-      String::NewFromUtf8(iso, source, NewStringType::kNormal);
-  MaybeLocal<String> maybeOgn =
-      String::NewFromUtf8(iso, origin, NewStringType::kNormal);
+  std::vector<Local<Name>> local_names(length);
+  std::vector<Local<Value>> local_values(length);
+  for (int i = 0; i < length; i++) {
+    Local<String> name;
+    if (!String::NewFromUtf8(iso, names[i], NewStringType::kInternalized)
+             .ToLocal(&name)) {
+      rtn.error = ExceptionError(try_catch, iso, local_ctx);
+      return rtn;
+    }
+    local_names[i] = name;
+    local_values[i] = values[i]->ptr.Get(iso);
+  }
+  // Object::New takes the prototype explicitly; use Object.prototype of the
+  // context, as an object literal would.
+  Local<Value> proto = Object::New(iso)->GetPrototype();
+  Local<Object> obj = Object::New(iso, proto, local_names.data(),
+                                  local_values.data(), length);
+
+  m_value* val = new m_value;
+  val->id = 0;
+  val->iso = iso;
+  val->ctx = ctx;
+  val->ptr = Global<Value>(iso, obj);
+  rtn.value = tracked_value(ctx, val);
+  return rtn;
+}
+
+void ContextSetSecurityToken(ContextPtr ctx, ValuePtr token) {
+  LOCAL_CONTEXT(ctx);
+  local_ctx->SetSecurityToken(token->ptr.Get(iso));
+}
+
+ValuePtr ContextGetSecurityToken(ContextPtr ctx) {
+  LOCAL_CONTEXT(ctx);
+  m_value* val = new m_value;
+  val->id = 0;
+  val->iso = iso;
+  val->ctx = ctx;
+  val->ptr = Global<Value>(iso, local_ctx->GetSecurityToken());
+  return tracked_value(ctx, val);
+}
+
+void ContextUseDefaultSecurityToken(ContextPtr ctx) {
+  LOCAL_CONTEXT(ctx);
+  local_ctx->UseDefaultSecurityToken();
+}
+
+int ContextRetainedValueCount(ContextPtr ctx) {
+  return ctx->vals.size();
+}
+
+static MaybeLocal<Value> CompileAndRun(Isolate* iso,
+                                       Local<Context> local_ctx,
+                                       const char* source,
+                                       const char* origin) {
   Local<String> src, ogn;
-  if (!maybeSrc.ToLocal(&src) || !maybeOgn.ToLocal(&ogn)) {
-    rtn.error = ExceptionError(try_catch, iso, local_ctx);
-    return rtn;
+  if (!String::NewFromUtf8(iso, source, NewStringType::kNormal)
+           .ToLocal(&src) ||
+      !String::NewFromUtf8(iso, origin, NewStringType::kNormal)
+           .ToLocal(&ogn)) {
+    return MaybeLocal<Value>();
   }
 
   ScriptOrigin script_origin(ogn);
   Local<Script> script;
   if (!Script::Compile(local_ctx, src, &script_origin).ToLocal(&script)) {
-    rtn.error = ExceptionError(try_catch, iso, local_ctx);
-    return rtn;
+    return MaybeLocal<Value>();
   }
+  return script->Run(local_ctx);
+}
+
+RtnValue RunScript(ContextPtr ctx, const char* source, const char* origin) {
+  LOCAL_CONTEXT(ctx);
+
+  RtnValue rtn = {};
+
   Local<Value> result;
-  if (!script->Run(local_ctx).ToLocal(&result)) {
+  if (!CompileAndRun(iso, local_ctx, source, origin).ToLocal(&result)) {
     rtn.error = ExceptionError(try_catch, iso, local_ctx);
     return rtn;
   }
@@ -119,3 +302,58 @@ RtnValue RunScript(ContextPtr ctx, const char* source, const char* origin) {
   rtn.value = tracked_value(ctx, val);
   return rtn;
 }
+
+RtnString RunScriptJSON(ContextPtr ctx,
+                        const char* source,
+                        const char* origin) {
+  LOCAL_CONTEXT(ctx);
+
+  RtnString rtn = {};
+
+  Local<Value> result;
+  Local<String> str;
+  if (!CompileAndRun(iso, local_ctx, source, origin).ToLocal(&result) ||
+      !JSON::Stringify(local_ctx, result).ToLocal(&str)) {
+    rtn.error = ExceptionError(try_catch, iso, local_ctx);
+    return rtn;
+  }
+  // Values that have no JSON representation, such as undefined or functions,
+  // stringify to undefined, which V8 returns as an empty or "undefined" string;
+  // neither is valid JSON text.
+  if (str->Length() == 0 ||
+      str->StringEquals(String::NewFromUtf8Literal(iso, "undefined"))) {
+    return rtn;
+  }
+  String::Utf8Value json(iso, str);
+  rtn.data = CopyString(json);
+  rtn.length = json.length();
+  return rtn;
+}
+
+RtnStackTrace ContextCaptureStackTrace(ContextPtr ctx, int frame_limit) {
+  LOCAL_CONTEXT(ctx);
+  RtnStackTrace rtn = {};
+
+  Local<StackTrace> trace = StackTrace::CurrentStackTrace(iso, frame_limit);
+  int count = trace->GetFrameCount();
+  if (count == 0) {
+    return rtn;
+  }
+  rtn.frames = (StackFrameInfo*)malloc(sizeof(StackFrameInfo) * count);
+  rtn.count = count;
+  for (int i = 0; i < count; ++i) {
+    Local<StackFrame> frame = trace->GetFrame(iso, i);
+    String::Utf8Value function_name(iso, frame->GetFunctionName());
+    String::Utf8Value script_name(iso, frame->GetScriptNameOrSourceURL());
+    rtn.frames[i] = {
+        CopyString(function_name),
+        CopyString(script_name),
+        frame->GetLineNumber(),
+        frame->GetColumn(),
+        frame->GetScriptId(),
+        frame->IsEval(),
+        frame->IsConstructor(),
+    };
+  }
+  return rtn;
+}
@@ -8,7 +8,13 @@ package v8go
 // #include "context.h"
 import "C"
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
 	"runtime"
+	"sort"
 	"sync"
 	"unsafe"
 )
@@ -31,11 +37,32 @@ type Context struct {
 	ref int
 	ptr C.ContextPtr
 	iso *Isolate
+
+	goCtx context.Context
+
+	// moduleResolver resolves the imports of the module being instantiated.
+	moduleResolver ModuleResolver
+
+	jsonModuleLoader func(specifier string) ([]byte, error)
+	jsonModules      map[string]*Module
+
+	// onClose are the functions registered with OnClose.
+	onClose []func()
+
+	// errorCtors are the constructors registered with SetErrorConstructor.
+	errorCtors map[string]*Function
+
+	// intrinsics caches the values returned by intrinsicValue.
+	intrinsics [C.INTRINSIC_COUNT]*Value
+
+	// randomSource is the function set with SetRandomSource.
+	randomSource func() float64
 }
 
 type contextOptions struct {
-	iso   *Isolate
-	gTmpl *ObjectTemplate
+	iso     *Isolate
+	gTmpl   *ObjectTemplate
+	gObject *Object
 }
 
 // ContextOption sets options such as Isolate and Global Template to the NewContext
@@ -45,7 +72,22 @@ type ContextOption interface {
 
 // NewContext creates a new JavaScript context; if no Isolate is passed as a
 // ContextOption than a new Isolate will be created.
+//
+// NewContext panics if the context can't be set up as its isolate requires,
+// which only happens in IsolateOptions.PredictableMode if the global template
+// prevents replacing Date or Math.random; use NewContextWithError to handle
+// that case.
 func NewContext(opt ...ContextOption) *Context {
+	ctx, err := NewContextWithError(opt...)
+	if err != nil {
+		panic(err)
+	}
+	return ctx
+}
+
+// NewContextWithError is like NewContext, but returns an error instead of
+// panicking if the context can't be set up as its isolate requires.
+func NewContextWithError(opt ...ContextOption) (*Context, error) {
 	opts := contextOptions{}
 	for _, o := range opt {
 		if o != nil {
@@ -66,14 +108,40 @@ func NewContext(opt ...ContextOption) *Context {
 	ref := ctxSeq
 	ctxMutex.Unlock()
 
+	var gObject C.ValuePtr
+	if opts.gObject != nil {
+		gObject = opts.gObject.ptr
+	}
+
 	ctx := &Context{
 		ref: ref,
-		ptr: C.NewContext(opts.iso.ptr, opts.gTmpl.ptr, C.int(ref)),
+		ptr: C.NewContext(opts.iso.ptr, opts.gTmpl.ptr, gObject, C.int(ref)),
 		iso: opts.iso,
 	}
 	ctx.register()
 	runtime.KeepAlive(opts.gTmpl)
-	return ctx
+	if opts.iso.predictable != nil {
+		if err := opts.iso.predictable.install(ctx); err != nil {
+			ctx.Close()
+			return nil, err
+		}
+	}
+	return ctx, nil
+}
+
+type reuseGlobal struct {
+	global *Object
+}
+
+// ReuseGlobal is a ContextOption that makes the new Context reuse a global
+// proxy object previously returned by Context.DetachGlobal, instead of
+// creating a new one. See DetachGlobal for details.
+func ReuseGlobal(global *Object) ContextOption {
+	return reuseGlobal{global}
+}
+
+func (r reuseGlobal) apply(opts *contextOptions) {
+	opts.gObject = r.global
 }
 
 // Isolate gets the current context's parent isolate.
@@ -87,12 +155,25 @@ func (c *Context) RetainedValueCount() int {
 	return int(C.ContextRetainedValueCount(c.ptr))
 }
 
+// AnonymousScriptOrigin is the origin given to scripts that are compiled or
+// run with an empty origin. It matches the name V8 uses in stack traces for
+// code without an origin, such as eval'd code.
+const AnonymousScriptOrigin = "<anonymous>"
+
+func scriptOrigin(origin string) string {
+	if origin == "" {
+		return AnonymousScriptOrigin
+	}
+	return origin
+}
+
 // RunScript executes the source JavaScript; origin (a.k.a. filename) provides a
 // reference for the script and used in the stack trace if there is an error.
+// An empty origin is replaced with AnonymousScriptOrigin.
 // error will be of type `JSError` if not nil.
 func (c *Context) RunScript(source string, origin string) (*Value, error) {
 	cSource := C.CString(source)
-	cOrigin := C.CString(origin)
+	cOrigin := C.CString(scriptOrigin(origin))
 	defer C.free(unsafe.Pointer(cSource))
 	defer C.free(unsafe.Pointer(cOrigin))
 
@@ -100,6 +181,73 @@ func (c *Context) RunScript(source string, origin string) (*Value, error) {
 	return valueResult(c, rtn)
 }
 
+// EvalJSON runs source like RunScript and returns the result serialized with
+// JSON.stringify, without converting it to a Go value first. If the result has
+// no JSON representation, such as undefined or a function, EvalJSON returns
+// nil and no error. A script error, or an error serializing the result such
+// as for a BigInt or a cyclic object, will be of type `JSError`.
+func (c *Context) EvalJSON(source string) (json.RawMessage, error) {
+	cSource := C.CString(source)
+	cOrigin := C.CString(AnonymousScriptOrigin)
+	defer C.free(unsafe.Pointer(cSource))
+	defer C.free(unsafe.Pointer(cOrigin))
+
+	rtn := C.RunScriptJSON(c.ptr, cSource, cOrigin)
+	if rtn.error.msg != nil {
+		return nil, c.resultError(rtn.error)
+	}
+	if rtn.data == nil {
+		return nil, nil
+	}
+	defer C.free(unsafe.Pointer(rtn.data))
+	return json.RawMessage(C.GoBytes(unsafe.Pointer(rtn.data), rtn.length)), nil
+}
+
+// DetachGlobal detaches the global proxy object from the context and returns
+// it, so it can be passed to a new context with the ReuseGlobal option. The
+// new context gets a fresh global object behind the same proxy, so any
+// reference to the proxy held elsewhere, e.g. by other contexts, now refers to
+// the new context's globals. Properties set on the old global object are not
+// carried over.
+//
+// After DetachGlobal the context should no longer be used, and should be
+// closed. The returned object stays valid after the context is closed. It must only be
+// reused in the same isolate, and the new context should be created with the
+// same global ObjectTemplate, if any, as the original one.
+func (c *Context) DetachGlobal() *Object {
+	ptr := C.ContextDetachGlobal(c.ptr)
+	return &Object{&Value{ptr: ptr}}
+}
+
+// SetGoContext associates a Go context.Context with the context, e.g. the
+// request context of the script being run. Function callbacks can retrieve it
+// with info.Context().GoContext() to honor cancellation and deadlines, or to
+// read request-scoped values such as trace IDs.
+func (c *Context) SetGoContext(goCtx context.Context) {
+	c.goCtx = goCtx
+}
+
+// GoContext returns the Go context.Context set with SetGoContext, or
+// context.Background() if none was set.
+func (c *Context) GoContext() context.Context {
+	if c.goCtx == nil {
+		return context.Background()
+	}
+	return c.goCtx
+}
+
+// RunScriptFile reads the JavaScript file at path and runs it like RunScript,
+// using path as the origin so that it appears in stack traces and error
+// locations. An error reading the file is returned wrapped, so it can be told
+// apart from a *JSError raised while compiling or running the script.
+func (c *Context) RunScriptFile(path string) (*Value, error) {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("v8go: unable to read script: %w", err)
+	}
+	return c.RunScript(string(source), path)
+}
+
 // Global returns the global proxy object.
 // Global proxy object is a thin wrapper whose prototype points to actual
 // context's global object with the properties like Object, etc. This is
@@ -109,19 +257,275 @@ func (c *Context) RunScript(source string, origin string) (*Value, error) {
 // global proxy object.
 func (c *Context) Global() *Object {
 	valPtr := C.ContextGlobal(c.ptr)
-	v := &Value{valPtr, c}
+	v := &Value{ptr: valPtr, ctx: c}
 	return &Object{v}
 }
 
+// SetGlobal defines a property on the global object of the context with the
+// given attributes, e.g. ReadOnly|DontDelete to inject a global that scripts
+// cannot reassign or delete. An error is returned if the global already
+// exists and cannot be redefined.
+func (c *Context) SetGlobal(name string, val Valuer, attributes PropertyAttribute) error {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	rtn := C.ContextDefineGlobal(c.ptr, cname, val.value().valuePtr(), C.int(attributes))
+	if rtn.msg != nil {
+		return c.resultError(rtn)
+	}
+	return nil
+}
+
+// NewObjectFrom creates a plain object with the given properties in a single
+// call into V8, e.g. for a FunctionCallback to return several values as
+// `{ok: true, value: ...}`. The properties are added in the sorted order of
+// their names.
+func (c *Context) NewObjectFrom(props map[string]Valuer) (*Object, error) {
+	nv, err := newNamedValues(props, "property")
+	if err != nil {
+		return nil, err
+	}
+	defer nv.free()
+
+	rtn := C.ContextNewObject(c.ptr, nv.namesPtr(), nv.valuesPtr(), nv.count())
+	return objectResult(c, rtn)
+}
+
+// namedValues holds the names of a map of values, sorted, and the values in
+// the same order as C arrays, for the C functions that take both.
+type namedValues struct {
+	names  []*C.char
+	values []C.ValuePtr
+}
+
+// newNamedValues converts m into namedValues, which must be freed. kind
+// describes the values in the error for a nil value, e.g. "property".
+func newNamedValues(m map[string]Valuer, kind string) (*namedValues, error) {
+	names := make([]string, 0, len(m))
+	for n, v := range m {
+		if v == nil {
+			return nil, fmt.Errorf("v8go: %s %q has no value", kind, n)
+		}
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	nv := &namedValues{
+		names:  make([]*C.char, len(names)),
+		values: make([]C.ValuePtr, len(names)),
+	}
+	for i, n := range names {
+		nv.names[i] = C.CString(n)
+		nv.values[i] = m[n].value().valuePtr()
+	}
+	return nv, nil
+}
+
+func (nv *namedValues) namesPtr() **C.char {
+	if len(nv.names) == 0 {
+		return nil
+	}
+	return &nv.names[0]
+}
+
+func (nv *namedValues) valuesPtr() *C.ValuePtr {
+	if len(nv.values) == 0 {
+		return nil
+	}
+	return &nv.values[0]
+}
+
+func (nv *namedValues) count() C.int {
+	return C.int(len(nv.names))
+}
+
+func (nv *namedValues) free() {
+	for _, n := range nv.names {
+		C.free(unsafe.Pointer(n))
+	}
+}
+
+// ThrowException throws v as a JS exception from a callback running in the
+// context, like Isolate.ThrowException, for callbacks whose control flow
+// doesn't map onto returning an error. The callback must not use JS after
+// throwing and should return immediately; its return value is ignored, and
+// an error returned from a FunctionCallbackWithError replaces the exception.
+func (c *Context) ThrowException(v Valuer) {
+	c.iso.ThrowException(v.value())
+}
+
+// ThrowError throws a new Error with the given message from a callback running
+// in the context, like ThrowException. The error is created in the context, so
+// `instanceof Error` holds in the script that catches it.
+func (c *Context) ThrowError(msg string) {
+	errv, err := NewErrorValue(c, ErrorKindGeneric, msg)
+	if err != nil {
+		c.iso.ThrowException(NewError(c.iso, msg).Value)
+		return
+	}
+	c.ThrowException(errv)
+}
+
+// SetSecurityToken sets the security token for the context. Contexts that
+// share an isolate may only access each other's objects when their security
+// tokens are identical; by default each context has its own unique token.
+func (c *Context) SetSecurityToken(token Valuer) {
+	C.ContextSetSecurityToken(c.ptr, token.value().valuePtr())
+}
+
+// GetSecurityToken returns the security token of the context.
+func (c *Context) GetSecurityToken() *Value {
+	valPtr := C.ContextGetSecurityToken(c.ptr)
+	return &Value{ptr: valPtr, ctx: c}
+}
+
+// UseDefaultSecurityToken restores the context's own unique security token.
+func (c *Context) UseDefaultSecurityToken() {
+	C.ContextUseDefaultSecurityToken(c.ptr)
+}
+
+// StackFrame is a single frame of a JavaScript stack trace.
+type StackFrame struct {
+	FunctionName  string
+	ScriptName    string
+	LineNumber    int
+	Column        int
+	ScriptID      int
+	IsEval        bool
+	IsConstructor bool
+	// IsAsync is true for a frame of an async function awaiting the frames
+	// above it. It is only set by JSError.StackFrames.
+	IsAsync bool
+}
+
+// CaptureStackTrace returns the current JavaScript stack, with at most
+// frameLimit frames starting from the innermost one. This is useful from
+// within a FunctionCallback to find out which script called it, without
+// having to throw an exception. It returns an empty slice when no JavaScript
+// is executing.
+func (c *Context) CaptureStackTrace(frameLimit int) []StackFrame {
+	rtn := C.ContextCaptureStackTrace(c.ptr, C.int(frameLimit))
+	if rtn.count == 0 {
+		return nil
+	}
+	defer C.free(unsafe.Pointer(rtn.frames))
+
+	cframes := unsafe.Slice(rtn.frames, rtn.count)
+	frames := make([]StackFrame, len(cframes))
+	for i, f := range cframes {
+		frames[i] = StackFrame{
+			FunctionName:  C.GoString(f.function_name),
+			ScriptName:    C.GoString(f.script_name),
+			LineNumber:    int(f.line_number),
+			Column:        int(f.column),
+			ScriptID:      int(f.script_id),
+			IsEval:        f.is_eval != 0,
+			IsConstructor: f.is_constructor != 0,
+		}
+		C.free(unsafe.Pointer(f.function_name))
+		C.free(unsafe.Pointer(f.script_name))
+	}
+	return frames
+}
+
 // PerformMicrotaskCheckpoint runs the default MicrotaskQueue until empty.
 // This is used to make progress on Promises.
+//
+// Once the queue is empty, the checkpoint also runs any foreground tasks V8
+// has posted for the isolate, which is where FinalizationRegistry cleanup
+// callbacks run. See [NewFinalizationRegistry] for the ordering guarantees.
 func (c *Context) PerformMicrotaskCheckpoint() {
 	C.IsolatePerformMicrotaskCheckpoint(c.iso.ptr)
 }
 
+// OnClose registers fn to be called when the context is closed, e.g. to free
+// Go resources that native objects in the context hold handles to. The
+// functions are called in the reverse order of their registration, before the
+// context is freed, so they may still use it. v8go keeps a context alive until
+// Close is called, so the functions never run for a context that isn't
+// closed.
+func (c *Context) OnClose(fn func()) {
+	if fn == nil {
+		panic("nil OnClose function not supported")
+	}
+	c.onClose = append(c.onClose, fn)
+}
+
+// SetRandomSource replaces Math.random in the context with a function that
+// returns the result of fn, which must be in the range [0, 1), e.g. from a
+// seeded math/rand.Rand so that simulation scripts are reproducible. Unlike
+// V8's entropy source, which only seeds V8's own generator, is shared by the
+// whole process and is read once, fn produces every number scripts get, so
+// the sequence is entirely under the caller's control. Each call crosses
+// from JS into Go, which makes Math.random slower.
+func (c *Context) SetRandomSource(fn func() float64) error {
+	if fn == nil {
+		panic("nil random source not supported")
+	}
+	mathVal, err := c.intrinsicValue(C.INTRINSIC_MATH)
+	if err != nil {
+		return err
+	}
+	math, err := mathVal.AsObject()
+	if err != nil {
+		return err
+	}
+	c.randomSource = fn
+	random := c.iso.randomSourceTemplate().GetFunction(c)
+	defer random.Release()
+	return math.Set("random", random)
+}
+
+// randomSourceTemplate returns the template of the Math.random functions set
+// by SetRandomSource, created once per isolate. The function calls the
+// source of the context it is called in, and throws a RangeError if the
+// number is not in the range [0, 1).
+func (i *Isolate) randomSourceTemplate() *FunctionTemplate {
+	if i.randomSource != nil {
+		return i.randomSource
+	}
+	i.randomSource = NewFunctionTemplateWithError(i, func(info *FunctionCallbackInfo) (*Value, error) {
+		ctx := info.Context()
+		if ctx.randomSource == nil {
+			return nil, newErrorException(ctx, ErrorKindGeneric, "v8go: no random source in the context")
+		}
+		n := ctx.randomSource()
+		if !(n >= 0 && n < 1) {
+			return nil, newErrorException(ctx, ErrorKindRange, fmt.Sprintf("v8go: random source returned %v, not a number in [0, 1)", n))
+		}
+		return NewValue(i, n)
+	})
+	return i.randomSource
+}
+
+// SetErrorConstructor registers ctor, typically a JS class extending Error,
+// under name, so NewCustomError can create errors that scripts in the context
+// can catch by class, e.g. with `err instanceof MyAppError`. Registering a
+// name again replaces its constructor.
+func (c *Context) SetErrorConstructor(name string, ctor *Function) error {
+	if name == "" {
+		return errors.New("v8go: error constructor name is required")
+	}
+	if ctor == nil {
+		return errors.New("v8go: nil error constructor")
+	}
+	if ctor.ctx != c {
+		return errors.New("v8go: error constructor belongs to a different context")
+	}
+	if c.errorCtors == nil {
+		c.errorCtors = make(map[string]*Function)
+	}
+	c.errorCtors[name] = ctor
+	return nil
+}
+
 // Close will dispose the context and free the memory.
 // Access to any values associated with the context after calling Close may panic.
 func (c *Context) Close() {
+	for i := len(c.onClose) - 1; i >= 0; i-- {
+		c.onClose[i]()
+	}
+	c.onClose = nil
+	c.errorCtors = nil
 	c.deregister()
 	C.ContextFree(c.ptr)
 	c.ptr = nil
@@ -161,22 +565,111 @@ func getContext(ref int) *Context {
 	return r.ctx
 }
 
+// Contexts returns the contexts of the isolate that have not been closed, in
+// the order they were created, e.g. to measure the memory of each tenant's
+// context. It reads the registry v8go keeps to route callbacks to contexts,
+// so it doesn't keep any context alive, and contexts closed afterwards must
+// no longer be used.
+func (i *Isolate) Contexts() []*Context {
+	ctxMutex.RLock()
+	refs := make([]int, 0, len(ctxRegistry))
+	for ref, r := range ctxRegistry {
+		if r.ctx.iso == i {
+			refs = append(refs, ref)
+		}
+	}
+	sort.Ints(refs)
+	ctxs := make([]*Context, len(refs))
+	for n, ref := range refs {
+		ctxs[n] = ctxRegistry[ref].ctx
+	}
+	ctxMutex.RUnlock()
+	return ctxs
+}
+
 //export goContext
 func goContext(ref int) C.ContextPtr {
-	ctx := getContext(ref)
-	return ctx.ptr
+	return getContext(ref).contextPtr()
+}
+
+// intrinsic returns a built-in function of the context. The built-ins are
+// all read the first time v8go needs one of them, rather than for every new
+// context, so v8go can call them even if a script replaces them afterwards.
+// The function is retained until the context is closed.
+func (c *Context) intrinsic(i C.IntrinsicIndex) (*Function, error) {
+	v, err := c.intrinsicValue(i)
+	if err != nil {
+		return nil, err
+	}
+	return &Function{v}, nil
+}
+
+// intrinsicValue returns a built-in of the context like intrinsic, for the
+// built-ins that aren't functions, such as Math.
+func (c *Context) intrinsicValue(i C.IntrinsicIndex) (*Value, error) {
+	if v := c.intrinsics[i]; v != nil {
+		return v, nil
+	}
+	ptr := C.ContextIntrinsic(c.ptr, i)
+	if ptr == nil {
+		return nil, errors.New("v8go: built-in is not available in the context")
+	}
+	v := &Value{ptr: ptr, ctx: c}
+	c.intrinsics[i] = v
+	return v, nil
+}
+
+// runShim runs source, a script evaluating to a function, and calls the
+// function with args, which are then released. v8go uses such shims to
+// define the parts of built-ins that are simpler to write in JS.
+func (c *Context) runShim(source, origin string, args ...*Value) error {
+	shim, err := c.RunScript(source, origin)
+	if err != nil {
+		releaseValues(args)
+		return err
+	}
+	defer shim.Release()
+	fn, err := shim.AsFunction()
+	if err != nil {
+		releaseValues(args)
+		return err
+	}
+	rtn, err := fn.CallAndRelease(Undefined(c.iso), args...)
+	if err != nil {
+		return err
+	}
+	rtn.Release()
+	return nil
+}
+
+// contextPtr returns the C pointer for c, or nil if c is nil.
+func (c *Context) contextPtr() C.ContextPtr {
+	if c == nil {
+		return nil
+	}
+	return c.ptr
 }
 
 func valueResult(ctx *Context, rtn C.RtnValue) (*Value, error) {
 	if rtn.value == nil {
-		return nil, newJSError(rtn.error)
+		return nil, ctx.resultError(rtn.error)
 	}
-	return &Value{rtn.value, ctx}, nil
+	return &Value{ptr: rtn.value, ctx: ctx}, nil
 }
 
 func objectResult(ctx *Context, rtn C.RtnValue) (*Object, error) {
 	if rtn.value == nil {
-		return nil, newJSError(rtn.error)
+		return nil, ctx.resultError(rtn.error)
+	}
+	return &Object{&Value{ptr: rtn.value, ctx: ctx}}, nil
+}
+
+// resultError converts the error of a call into the context, which is a
+// *HeapLimitError if the NearHeapLimitCallback terminated it.
+func (c *Context) resultError(rtn C.RtnError) error {
+	err := newJSError(rtn)
+	if c == nil || c.iso == nil {
+		return err
 	}
-	return &Object{&Value{rtn.value, ctx}}, nil
+	return c.iso.takeHeapLimitError(err)
 }
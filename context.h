@@ -3,6 +3,30 @@
 
 #include "errors.h"
 #include "isolate.h"
+#include "value.h"
+
+// IntrinsicIndex identifies a built-in function or object that v8go reads from a
+// context the first time it needs one, so that scripts replacing the global
+// it was read from afterwards can't intercept the calls v8go makes to it.
+typedef enum {
+  INTRINSIC_OBJECT_IS_EXTENSIBLE = 0,
+  INTRINSIC_OBJECT_PREVENT_EXTENSIONS,
+  INTRINSIC_UINT8_ARRAY,
+  INTRINSIC_FINALIZATION_REGISTRY,
+  INTRINSIC_WEAK_MAP,
+  INTRINSIC_WEAK_MAP_GET,
+  INTRINSIC_WEAK_MAP_SET,
+  INTRINSIC_WEAK_MAP_HAS,
+  INTRINSIC_WEAK_MAP_DELETE,
+  INTRINSIC_WEAK_SET,
+  INTRINSIC_WEAK_SET_ADD,
+  INTRINSIC_WEAK_SET_HAS,
+  INTRINSIC_WEAK_SET_DELETE,
+  INTRINSIC_MATH,
+  INTRINSIC_WEAK_REF_DEREF,
+  INTRINSIC_FUNCTION_BIND,
+  INTRINSIC_COUNT
+} IntrinsicIndex;
 
 #ifdef __cplusplus
 
@@ -10,7 +34,6 @@
 
 #include <unordered_map>
 #include <vector>
-#include "value.h"
 
 namespace v8 {
 class Isolate;
@@ -19,13 +42,21 @@ class Context;
 
 typedef v8::Isolate v8Isolate;
 typedef struct m_unboundScript m_unboundScript;
+typedef struct m_module m_module;
 
 struct m_ctx {
   v8::Isolate* iso;
   std::unordered_map<long, m_value*> vals;
   std::vector<m_unboundScript*> unboundScripts;
+  // Keyed by the identity hash of the module, which is not unique.
+  std::unordered_multimap<int, m_module*> modules;
   v8::Persistent<v8::Context> ptr;
   long nextValId;
+  // The stack limit of the isolate, only set on its internal context.
+  size_t stack_limit_kb = 0;
+  // Read on the first call to ContextIntrinsic.
+  v8::Global<v8::Value> intrinsics[INTRINSIC_COUNT];
+  bool intrinsics_resolved = false;
 };
 typedef m_ctx* ContextPtr;
 
@@ -43,15 +74,50 @@ typedef m_value* ValuePtr;
 typedef struct m_template m_template;
 typedef m_template* TemplatePtr;
 
+typedef struct {
+  const char* function_name;
+  const char* script_name;
+  int line_number;
+  int column;
+  int script_id;
+  int is_eval;
+  int is_constructor;
+} StackFrameInfo;
+
+typedef struct {
+  StackFrameInfo* frames;
+  int count;
+} RtnStackTrace;
+
 extern ContextPtr NewContext(IsolatePtr iso_ptr,
                              TemplatePtr global_template_ptr,
+                             ValuePtr global_object_ptr,
                              int ref);
+extern ValuePtr ContextDetachGlobal(ContextPtr ctx_ptr);
 extern int ContextRetainedValueCount(ContextPtr ctx);
 extern ValuePtr ContextGlobal(ContextPtr ctx_ptr);
+extern ValuePtr ContextIntrinsic(ContextPtr ctx_ptr,
+                                 IntrinsicIndex intrinsic);
 extern void ContextFree(ContextPtr ctx);
 extern RtnValue RunScript(ContextPtr ctx_ptr,
                           const char* source,
                           const char* origin);
+extern RtnString RunScriptJSON(ContextPtr ctx_ptr,
+                               const char* source,
+                               const char* origin);
+extern RtnError ContextDefineGlobal(ContextPtr ctx_ptr,
+                                   const char* name,
+                                   ValuePtr val_ptr,
+                                   int attributes);
+extern RtnValue ContextNewObject(ContextPtr ctx_ptr,
+                                 const char** names,
+                                 ValuePtr* values,
+                                 int length);
+extern void ContextSetSecurityToken(ContextPtr ctx_ptr, ValuePtr token_ptr);
+extern ValuePtr ContextGetSecurityToken(ContextPtr ctx_ptr);
+extern void ContextUseDefaultSecurityToken(ContextPtr ctx_ptr);
+extern RtnStackTrace ContextCaptureStackTrace(ContextPtr ctx_ptr,
+                                              int frame_limit);
 
 #ifdef __cplusplus
 }  // extern "C"
@@ -5,8 +5,15 @@
 package v8go_test
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	v8 "github.com/lizc2003/v8go"
@@ -39,6 +46,100 @@ func TestContextExec(t *testing.T) {
 	}
 }
 
+func TestContextDetachGlobal(t *testing.T) {
+	t.Parallel()
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	global := v8.NewObjectTemplate(iso)
+	fatalIf(t, global.Set("version", "1"))
+
+	ctx1 := v8.NewContext(iso, global)
+	_, err := ctx1.RunScript(`var marker = "request 1"`, "req1.js")
+	fatalIf(t, err)
+	proxy := ctx1.DetachGlobal()
+	ctx1.Close()
+
+	ctx2 := v8.NewContext(iso, global, v8.ReuseGlobal(proxy))
+	defer ctx2.Close()
+	val, err := ctx2.RunScript(`typeof marker + "," + version`, "req2.js")
+	fatalIf(t, err)
+	if val.String() != "undefined,1" {
+		t.Errorf("expected fresh globals from the template, got %q", val.String())
+	}
+	if !ctx2.Global().SameValue(proxy.Value) {
+		t.Error("expected the new context to reuse the detached global proxy")
+	}
+}
+
+func TestContextGoContext(t *testing.T) {
+	t.Parallel()
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	type traceKey struct{}
+	global := v8.NewObjectTemplate(iso)
+	fatalIf(t, global.Set("fetch", v8.NewFunctionTemplateWithError(iso, func(info *v8.FunctionCallbackInfo) (*v8.Value, error) {
+		goCtx := info.Context().GoContext()
+		if err := goCtx.Err(); err != nil {
+			return nil, err
+		}
+		return v8.NewValue(iso, goCtx.Value(traceKey{}).(string))
+	})))
+
+	ctx := v8.NewContext(iso, global)
+	defer ctx.Close()
+
+	if ctx.GoContext() != context.Background() {
+		t.Error("expected context.Background() by default")
+	}
+
+	goCtx, cancel := context.WithCancel(context.WithValue(context.Background(), traceKey{}, "trace-1"))
+	ctx.SetGoContext(goCtx)
+	val, err := ctx.RunScript("fetch()", "main.js")
+	fatalIf(t, err)
+	if val.String() != "trace-1" {
+		t.Errorf("expected trace ID from the Go context, got %q", val.String())
+	}
+
+	cancel()
+	if _, err := ctx.RunScript("fetch()", "main.js"); err == nil || !strings.Contains(err.Error(), "context canceled") {
+		t.Errorf("expected the callback to honor cancellation, got %v", err)
+	}
+}
+
+func TestContextRunScriptFile(t *testing.T) {
+	t.Parallel()
+	ctx := v8.NewContext(nil)
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	dir := t.TempDir()
+	add := filepath.Join(dir, "add.js")
+	fatalIf(t, os.WriteFile(add, []byte("const add = (a, b) => a + b; add(3, 4)"), 0o644))
+	val, err := ctx.RunScriptFile(add)
+	fatalIf(t, err)
+	if val.String() != "7" {
+		t.Errorf("script returned an unexpected value: expected %q, got %q", "7", val.String())
+	}
+
+	throws := filepath.Join(dir, "throws.js")
+	fatalIf(t, os.WriteFile(throws, []byte("\nthrow new Error('boom')"), 0o644))
+	_, err = ctx.RunScriptFile(throws)
+	var jsErr *v8.JSError
+	if !errors.As(err, &jsErr) {
+		t.Fatalf("expected a *JSError, got %v", err)
+	}
+	if !strings.HasPrefix(jsErr.Location, throws+":2") {
+		t.Errorf("expected the location to use the file path as origin, got %q", jsErr.Location)
+	}
+
+	_, err = ctx.RunScriptFile(filepath.Join(dir, "missing.js"))
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected a not-exist error, got %v", err)
+	}
+}
+
 func TestJSExceptions(t *testing.T) {
 	t.Parallel()
 
@@ -114,6 +215,250 @@ func TestMemoryLeak(t *testing.T) {
 	}
 }
 
+func TestContextCaptureStackTrace(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	var frames []v8.StackFrame
+	global := v8.NewObjectTemplate(iso)
+	err := global.Set("audit", v8.NewFunctionTemplate(iso, func(info *v8.FunctionCallbackInfo) *v8.Value {
+		frames = info.Context().CaptureStackTrace(10)
+		return nil
+	}))
+	fatalIf(t, err)
+
+	ctx := v8.NewContext(iso, global)
+	defer ctx.Close()
+
+	if st := ctx.CaptureStackTrace(10); len(st) != 0 {
+		t.Errorf("expected no frames outside of JS, got %d", len(st))
+	}
+
+	_, err = ctx.RunScript("function handler() {\n  audit();\n}\nhandler();", "audit.js")
+	fatalIf(t, err)
+
+	if len(frames) != 2 {
+		t.Fatalf("expected 2 frames, got %d: %+v", len(frames), frames)
+	}
+	top := frames[0]
+	if top.FunctionName != "handler" || top.ScriptName != "audit.js" || top.LineNumber != 2 {
+		t.Errorf("unexpected top frame: %+v", top)
+	}
+	if frames[1].LineNumber != 4 {
+		t.Errorf("unexpected outer frame: %+v", frames[1])
+	}
+}
+
+func TestContextSecurityToken(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	ctx1 := v8.NewContext(iso)
+	defer ctx1.Close()
+	ctx2 := v8.NewContext(iso)
+	defer ctx2.Close()
+
+	_, err := ctx1.RunScript(`var secret = "tenant1"`, "ctx1.js")
+	fatalIf(t, err)
+	fatalIf(t, ctx2.Global().Set("other", ctx1.Global()))
+
+	if _, err := ctx2.RunScript(`other.secret`, "ctx2.js"); err == nil {
+		t.Error("expected cross-context access to be denied")
+	}
+
+	token, err := v8.NewValue(iso, "shared")
+	fatalIf(t, err)
+	ctx1.SetSecurityToken(token)
+	ctx2.SetSecurityToken(token)
+	if !ctx1.GetSecurityToken().SameValue(token) {
+		t.Error("expected GetSecurityToken to return the token that was set")
+	}
+
+	val, err := ctx2.RunScript(`other.secret`, "ctx2.js")
+	fatalIf(t, err)
+	if val.String() != "tenant1" {
+		t.Errorf("unexpected value: %q", val.String())
+	}
+
+	ctx1.UseDefaultSecurityToken()
+	if _, err := ctx2.RunScript(`other.secret`, "ctx2.js"); err == nil {
+		t.Error("expected cross-context access to be denied after UseDefaultSecurityToken")
+	}
+}
+
+func TestContextSetGlobal(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	api, err := v8.NewValue(ctx.Isolate(), "trusted")
+	fatalIf(t, err)
+	fatalIf(t, ctx.SetGlobal("api", api, v8.ReadOnly|v8.DontDelete))
+
+	val, err := ctx.RunScript(`api = "clobbered"; delete api; api`, "main.js")
+	fatalIf(t, err)
+	if val.String() != "trusted" {
+		t.Errorf("expected read-only global to be unchanged, got %q", val.String())
+	}
+
+	if _, err := ctx.RunScript(`"use strict"; api = "clobbered"`, "strict.js"); err == nil {
+		t.Error("expected assignment to a read-only global to throw in strict mode")
+	}
+
+	other, err := v8.NewValue(ctx.Isolate(), "other")
+	fatalIf(t, err)
+	if err := ctx.SetGlobal("api", other, v8.None); err == nil {
+		t.Error("expected error redefining a non-configurable global")
+	}
+}
+
+func TestContextEvalJSON(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	tests := [...]struct {
+		source string
+		want   string
+	}{
+		{`({id: 1, tags: ["a", "b"], skip: undefined})`, `{"id":1,"tags":["a","b"]}`},
+		{`"caf\u00e9"`, `"café"`},
+		{`null`, `null`},
+		{`undefined`, ``},
+		{`(function() {})`, ``},
+	}
+	for _, tt := range tests {
+		tt := tt
+		got, err := ctx.EvalJSON(tt.source)
+		fatalIf(t, err)
+		if string(got) != tt.want {
+			t.Errorf("EvalJSON(%s): got %q, want %q", tt.source, got, tt.want)
+		}
+		if tt.want == "" && got != nil {
+			t.Errorf("EvalJSON(%s): expected nil result, got %q", tt.source, got)
+		}
+	}
+
+	if _, err := ctx.EvalJSON(`throw new Error("boom")`); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("expected script error, got %v", err)
+	}
+	var jsErr *v8.JSError
+	if _, err := ctx.EvalJSON(`10n`); !errors.As(err, &jsErr) || !strings.Contains(err.Error(), "BigInt") {
+		t.Errorf("expected BigInt serialization error, got %v", err)
+	}
+}
+
+func TestContextThrowError(t *testing.T) {
+	t.Parallel()
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	global := v8.NewObjectTemplate(iso)
+	global.Set("fail", v8.NewFunctionTemplate(iso, func(info *v8.FunctionCallbackInfo) *v8.Value {
+		info.Context().ThrowError("imperative failure")
+		return nil
+	}))
+	global.Set("throwArg", v8.NewFunctionTemplate(iso, func(info *v8.FunctionCallbackInfo) *v8.Value {
+		info.Context().ThrowException(info.Args()[0])
+		ignored, _ := v8.NewValue(iso, "ignored")
+		return ignored
+	}))
+	ctx := v8.NewContext(iso, global)
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`
+		let caught;
+		try { fail(); } catch (e) { caught = (e instanceof Error) + ":" + e.message; }
+		try { throwArg({code: 7}); } catch (e) { caught += ":" + e.code; }
+		caught
+	`, "throw.js")
+	fatalIf(t, err)
+	if got, want := val.String(), "true:imperative failure:7"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if _, err := ctx.RunScript(`fail()`, "uncaught.js"); err == nil || !strings.Contains(err.Error(), "imperative failure") {
+		t.Errorf("expected uncaught error, got %v", err)
+	}
+}
+
+func TestContextSetRandomSource(t *testing.T) {
+	t.Parallel()
+
+	run := func(seed int64) string {
+		ctx := v8.NewContext()
+		defer ctx.Isolate().Dispose()
+		defer ctx.Close()
+
+		fatalIf(t, ctx.SetRandomSource(rand.New(rand.NewSource(seed)).Float64))
+		val, err := ctx.RunScript(`
+			const xs = Array.from({ length: 3 }, () => Math.random());
+			[xs.every(x => x >= 0 && x < 1), Object.keys(Math).includes("random"), ...xs].join("|")`, "")
+		fatalIf(t, err)
+		return val.String()
+	}
+
+	first := run(1)
+	if !strings.HasPrefix(first, "true|false|") {
+		t.Errorf("unexpected output %q", first)
+	}
+	if second := run(1); second != first {
+		t.Errorf("expected the same numbers from the same seed, got %q and %q", first, second)
+	}
+	if other := run(2); other == first {
+		t.Errorf("expected different numbers from another seed, got %q twice", first)
+	}
+}
+
+func TestContextSetRandomSourceOutOfRange(t *testing.T) {
+	t.Parallel()
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	fatalIf(t, ctx.SetRandomSource(func() float64 { return 0.5 }))
+	fatalIf(t, ctx.SetRandomSource(func() float64 { return 1 }))
+	val, err := ctx.RunScript(`try { Math.random() } catch (e) { e instanceof RangeError }`, "")
+	fatalIf(t, err)
+	if !val.Boolean() {
+		t.Errorf("expected a RangeError, got %v", val)
+	}
+}
+
+func TestContextOnClose(t *testing.T) {
+	t.Parallel()
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	ctx := v8.NewContext(iso)
+
+	var calls []string
+	ctx.OnClose(func() { calls = append(calls, "first") })
+	ctx.OnClose(func() {
+		// The context is still usable while the hooks run.
+		val, err := ctx.RunScript(`"second"`, "")
+		if err != nil {
+			t.Errorf("expected the context to be usable, got %v", err)
+			return
+		}
+		calls = append(calls, val.String())
+	})
+	if len(calls) != 0 {
+		t.Fatalf("expected no calls before Close, got %v", calls)
+	}
+	ctx.Close()
+	if got, want := strings.Join(calls, ","), "second,first"; got != want {
+		t.Errorf("got calls %q, want %q", got, want)
+	}
+}
+
 // https://github.com/rogchap/v8go/issues/186
 func TestRegistryFromJSON(t *testing.T) {
 	t.Parallel()
@@ -152,6 +497,41 @@ func TestRegistryFromJSON(t *testing.T) {
 	}
 }
 
+func TestContextNewObjectFrom(t *testing.T) {
+	t.Parallel()
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	ctx := v8.NewContext(iso)
+	defer ctx.Close()
+
+	lookup := v8.NewFunctionTemplateWithError(iso, func(info *v8.FunctionCallbackInfo) (*v8.Value, error) {
+		ok, _ := v8.NewValue(iso, true)
+		value, _ := v8.NewValue(iso, int32(42))
+		obj, err := info.Context().NewObjectFrom(map[string]v8.Valuer{"value": value, "ok": ok})
+		if err != nil {
+			return nil, err
+		}
+		return obj.Value, nil
+	})
+	fatalIf(t, ctx.Global().Set("lookup", lookup.GetFunction(ctx)))
+
+	val, err := ctx.RunScript(`const r = lookup(); JSON.stringify(r) + " " + (Object.getPrototypeOf(r) === Object.prototype)`, "lookup.js")
+	fatalIf(t, err)
+	if got, want := val.String(), `{"ok":true,"value":42} true`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	empty, err := ctx.NewObjectFrom(nil)
+	fatalIf(t, err)
+	if s, _ := v8.JSONStringify(ctx, empty); s != "{}" {
+		t.Errorf("expected empty object, got %s", s)
+	}
+
+	if _, err := ctx.NewObjectFrom(map[string]v8.Valuer{"missing": nil}); err == nil {
+		t.Error("expected error for a nil property value, got <nil>")
+	}
+}
+
 func BenchmarkContext(b *testing.B) {
 	b.ReportAllocs()
 	iso := v8.NewIsolate()
@@ -0,0 +1,89 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+import "encoding/json"
+
+// CoverageOptions configures InspectorSession.StartPreciseCoverage.
+type CoverageOptions struct {
+	// CallCount counts how many times each function or block runs, rather
+	// than only whether it ran.
+	CallCount bool
+	// Detailed collects block coverage, i.e. ranges for the branches within
+	// functions, rather than only function coverage.
+	Detailed bool
+}
+
+// ScriptCoverage is the coverage of one script.
+type ScriptCoverage struct {
+	// ScriptID is the id V8 assigned to the script.
+	ScriptID string `json:"scriptId"`
+	// URL is the origin the script was compiled with.
+	URL       string             `json:"url"`
+	Functions []FunctionCoverage `json:"functions"`
+}
+
+// FunctionCoverage is the coverage of one function. The top-level code of a
+// script is reported as a function with an empty name.
+type FunctionCoverage struct {
+	FunctionName string `json:"functionName"`
+	// Ranges are the source ranges of the function. The first covers the
+	// whole function; with block coverage, the others are nested blocks whose
+	// count differs from the enclosing range.
+	Ranges []CoverageRange `json:"ranges"`
+	// IsBlockCoverage is whether Ranges contains block coverage.
+	IsBlockCoverage bool `json:"isBlockCoverage"`
+}
+
+// CoverageRange is a range of source code and how often it ran. Offsets are
+// in UTF-16 code units from the start of the script.
+type CoverageRange struct {
+	StartOffset int `json:"startOffset"`
+	EndOffset   int `json:"endOffset"`
+	Count       int `json:"count"`
+}
+
+// StartPreciseCoverage starts collecting the code coverage of the scripts run
+// in the contexts registered with the [Inspector] of the session, by enabling
+// its Profiler domain. Only code that runs after the call is covered, and
+// scripts compiled before it may not be reported at all. The collection stops
+// with StopPreciseCoverage, or when the session is disposed.
+func (s *InspectorSession) StartPreciseCoverage(opts CoverageOptions) error {
+	if err := s.EnableDomain("Profiler"); err != nil {
+		return err
+	}
+	_, err := s.Send("Profiler.startPreciseCoverage", map[string]bool{
+		"callCount": opts.CallCount,
+		"detailed":  opts.Detailed,
+	})
+	return err
+}
+
+// TakePreciseCoverage returns the coverage collected since
+// StartPreciseCoverage, or since the previous TakePreciseCoverage. With
+// CoverageOptions.CallCount, taking the coverage resets the counts. An
+// *InspectorError is returned if coverage is not being collected.
+func (s *InspectorSession) TakePreciseCoverage() ([]ScriptCoverage, error) {
+	rtn, err := s.Send("Profiler.takePreciseCoverage", nil)
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		Result []ScriptCoverage `json:"result"`
+	}
+	if err := json.Unmarshal(rtn, &result); err != nil {
+		return nil, err
+	}
+	return result.Result, nil
+}
+
+// StopPreciseCoverage stops collecting coverage and disables the Profiler
+// domain of the session.
+func (s *InspectorSession) StopPreciseCoverage() error {
+	if _, err := s.Send("Profiler.stopPreciseCoverage", nil); err != nil {
+		return err
+	}
+	return s.DisableDomain("Profiler")
+}
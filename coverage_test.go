@@ -0,0 +1,56 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"testing"
+
+	v8 "github.com/lizc2003/v8go"
+)
+
+func TestInspectorSessionPreciseCoverage(t *testing.T) {
+	t.Parallel()
+	iso := NewIsolateWithInspectorClient(&consoleAPIMessageRecorder{})
+	defer iso.Dispose()
+	ctx := iso.NewContext()
+	defer ctx.Dispose()
+	session := iso.inspector.Connect()
+	defer session.Dispose()
+
+	if _, err := session.TakePreciseCoverage(); err == nil {
+		t.Error("expected error taking coverage before starting it, got <nil>")
+	}
+	fatalIf(t, session.StartPreciseCoverage(v8.CoverageOptions{CallCount: true, Detailed: true}))
+
+	_, err := ctx.RunScript(`
+		function add(a, b) { return a + b; }
+		function unused() { return 0; }
+		add(1, 2); add(3, 4);`, "cov.js")
+	fatalIf(t, err)
+
+	scripts, err := session.TakePreciseCoverage()
+	fatalIf(t, err)
+	counts := map[string]int{}
+	for _, script := range scripts {
+		if script.URL != "cov.js" {
+			continue
+		}
+		for _, fn := range script.Functions {
+			if len(fn.Ranges) == 0 {
+				t.Fatalf("expected ranges for function %q", fn.FunctionName)
+			}
+			counts[fn.FunctionName] = fn.Ranges[0].Count
+		}
+	}
+	if counts["add"] != 2 || counts["unused"] != 0 {
+		t.Errorf("unexpected call counts %v", counts)
+	}
+
+	fatalIf(t, session.StopPreciseCoverage())
+	if _, err := session.TakePreciseCoverage(); err == nil {
+		t.Error("expected error taking coverage after stopping it, got <nil>")
+	}
+	fatalIf(t, session.StartPreciseCoverage(v8.CoverageOptions{}))
+}
@@ -0,0 +1,178 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+import (
+	"errors"
+	"strings"
+)
+
+// DifferenceKind is the kind of a Difference found by DiffValues.
+type DifferenceKind int
+
+const (
+	// DifferenceAdded is a property that is only in the second value.
+	DifferenceAdded DifferenceKind = iota
+	// DifferenceRemoved is a property that is only in the first value.
+	DifferenceRemoved
+	// DifferenceChanged is a value that differs between the two.
+	DifferenceChanged
+)
+
+func (k DifferenceKind) String() string {
+	switch k {
+	case DifferenceAdded:
+		return "added"
+	case DifferenceRemoved:
+		return "removed"
+	case DifferenceChanged:
+		return "changed"
+	}
+	return "unknown"
+}
+
+// Difference is a difference between two values found by DiffValues.
+type Difference struct {
+	Kind DifferenceKind
+	// Path is the property names leading to the value from the compared
+	// values, with array indices as decimal strings. It is empty if the
+	// compared values themselves differ.
+	Path []string
+	// Before is the value in the first value, and nil if the property was
+	// added. After is the value in the second value, and nil if the property
+	// was removed.
+	Before *Value
+	After  *Value
+}
+
+func (d Difference) String() string {
+	return d.Kind.String() + " " + strings.Join(d.Path, ".")
+}
+
+// DiffValues compares a and b structurally and returns their differences, in
+// the order of the properties of a, followed by the properties only in b.
+// Objects are compared by their own enumerable string-keyed properties, as
+// with Object.GetOwnPropertyNames, recursing into nested objects and arrays,
+// and other values with SameValue. Two values are compared as objects only if
+// both are arrays or both are objects that are neither arrays nor functions,
+// so e.g. an array replaced with an object is a single change. A pair of
+// objects already being compared further up the path, as in cyclic
+// structures, is not compared again. Reading the properties runs the getters
+// of accessor properties.
+func DiffValues(a, b *Value) ([]Difference, error) {
+	if a == nil || b == nil {
+		return nil, errors.New("v8go: values to compare cannot be <nil>")
+	}
+	d := &differ{}
+	if _, err := d.compare(nil, a, b); err != nil {
+		return nil, err
+	}
+	return d.diffs, nil
+}
+
+type differ struct {
+	diffs []Difference
+	// stack holds the pairs of objects being compared, for cycle detection.
+	stack [][2]*Value
+}
+
+// compare adds the differences between a and b at path, and returns whether a
+// difference refers to a or b or any of their properties, which must then not
+// be released.
+func (d *differ) compare(path []string, a, b *Value) (kept bool, err error) {
+	if a.SameValue(b) {
+		return false, nil
+	}
+	if !comparableObjects(a, b) {
+		d.add(DifferenceChanged, path, a, b)
+		return true, nil
+	}
+	for _, pair := range d.stack {
+		if pair[0].SameValue(a) && pair[1].SameValue(b) {
+			return false, nil
+		}
+	}
+	d.stack = append(d.stack, [2]*Value{a, b})
+	defer func() { d.stack = d.stack[:len(d.stack)-1] }()
+
+	objA, _ := a.AsObject()
+	objB, _ := b.AsObject()
+	namesA, err := objA.GetOwnPropertyNames()
+	if err != nil {
+		return false, err
+	}
+	namesB, err := objB.GetOwnPropertyNames()
+	if err != nil {
+		return false, err
+	}
+	inB := make(map[string]bool, len(namesB))
+	for _, name := range namesB {
+		inB[name] = true
+	}
+	inA := make(map[string]bool, len(namesA))
+	for _, name := range namesA {
+		inA[name] = true
+		childPath := appendPath(path, name)
+		before, err := objA.Get(name)
+		if err != nil {
+			return kept, err
+		}
+		if !inB[name] {
+			d.add(DifferenceRemoved, childPath, before, nil)
+			kept = true
+			continue
+		}
+		after, err := objB.Get(name)
+		if err != nil {
+			before.Release()
+			return kept, err
+		}
+		childKept, err := d.compare(childPath, before, after)
+		if err != nil {
+			return kept, err
+		}
+		if childKept {
+			kept = true
+		} else {
+			before.Release()
+			after.Release()
+		}
+	}
+	for _, name := range namesB {
+		if inA[name] {
+			continue
+		}
+		after, err := objB.Get(name)
+		if err != nil {
+			return kept, err
+		}
+		d.add(DifferenceAdded, appendPath(path, name), nil, after)
+		kept = true
+	}
+	return kept, nil
+}
+
+func (d *differ) add(kind DifferenceKind, path []string, before, after *Value) {
+	d.diffs = append(d.diffs, Difference{Kind: kind, Path: path, Before: before, After: after})
+}
+
+// comparableObjects reports whether a and b are compared by their
+// properties: both arrays, or both objects that are neither arrays nor
+// functions.
+func comparableObjects(a, b *Value) bool {
+	if !a.IsObject() || !b.IsObject() || a.IsFunction() || b.IsFunction() {
+		return false
+	}
+	return a.IsArray() == b.IsArray()
+}
+
+// appendPath returns a new path of path followed by name, so paths of
+// sibling properties don't share their backing arrays.
+func appendPath(path []string, name string) []string {
+	p := make([]string, len(path)+1)
+	copy(p, path)
+	p[len(path)] = name
+	return p
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"testing"
+
+	v8 "github.com/lizc2003/v8go"
+)
+
+func TestDiffValues(t *testing.T) {
+	t.Parallel()
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	before, err := ctx.RunScript(`
+		const shared = { x: 1 };
+		var state = { name: "a", items: [1, 2, 3], nested: { deep: true, gone: 1 }, shared, list: [] };
+		state.self = state;
+		state`, "")
+	fatalIf(t, err)
+	after, err := ctx.RunScript(`({
+		name: "b", items: [1, 5], nested: { deep: true }, shared, list: {}, self: null, extra: NaN,
+	})`, "")
+	fatalIf(t, err)
+
+	diffs, err := v8.DiffValues(before, after)
+	fatalIf(t, err)
+	want := []string{
+		"changed name",
+		"changed items.1",
+		"removed items.2",
+		"removed nested.gone",
+		"changed list",
+		"changed self",
+		"added extra",
+	}
+	if len(diffs) != len(want) {
+		t.Fatalf("got %v, want %v", diffs, want)
+	}
+	for i, d := range diffs {
+		if got := d.String(); got != want[i] {
+			t.Errorf("difference %d: got %q, want %q", i, got, want[i])
+		}
+	}
+	if d := diffs[0]; d.Before.String() != "a" || d.After.String() != "b" {
+		t.Errorf("unexpected values of the changed name: %v and %v", d.Before, d.After)
+	}
+	if d := diffs[2]; d.Before.Integer() != 3 || d.After != nil {
+		t.Errorf("unexpected values of the removed item: %v and %v", d.Before, d.After)
+	}
+	if d := diffs[6]; d.Before != nil || !d.After.IsNumber() {
+		t.Errorf("unexpected values of the added property: %v and %v", d.Before, d.After)
+	}
+
+	// Cyclic structures compare without recursing forever.
+	cyclic, err := ctx.RunScript(`
+		var copy = { name: "a", items: [1, 2, 3], nested: { deep: true, gone: 1 }, shared, list: [] };
+		copy.self = copy;
+		copy`, "")
+	fatalIf(t, err)
+	diffs, err = v8.DiffValues(before, cyclic)
+	fatalIf(t, err)
+	if len(diffs) != 0 {
+		t.Errorf("expected no differences between equal cyclic objects, got %v", diffs)
+	}
+
+	same, err := v8.DiffValues(before, before)
+	fatalIf(t, err)
+	if len(same) != 0 {
+		t.Errorf("expected no differences comparing a value to itself, got %v", same)
+	}
+}
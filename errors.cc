@@ -12,7 +12,7 @@ using namespace v8;
 RtnError ExceptionError(TryCatch& try_catch, Isolate* iso, Local<Context> ctx) {
   HandleScope handle_scope(iso);
 
-  RtnError rtn = {nullptr, nullptr, nullptr};
+  RtnError rtn = {nullptr, nullptr, nullptr, nullptr, 0, 0};
 
   if (try_catch.HasTerminated()) {
     rtn.msg =
@@ -25,19 +25,35 @@ RtnError ExceptionError(TryCatch& try_catch, Isolate* iso, Local<Context> ctx) {
 
   Local<Message> msg = try_catch.Message();
   if (!msg.IsEmpty()) {
-    String::Utf8Value origin(iso, msg->GetScriptOrigin().ResourceName());
+    // Code without an origin, e.g. from eval or new Function, has an
+    // undefined resource name; name it the way V8 does in stack traces.
+    Local<Value> resource_name = msg->GetScriptOrigin().ResourceName();
     std::ostringstream sb;
-    sb << *origin;
-    Maybe<int> line = try_catch.Message()->GetLineNumber(ctx);
+    if (resource_name.IsEmpty() || !resource_name->IsString() ||
+        resource_name.As<String>()->Length() == 0) {
+      sb << "<anonymous>";
+    } else {
+      String::Utf8Value origin(iso, resource_name);
+      sb << *origin;
+    }
+    Maybe<int> line = msg->GetLineNumber(ctx);
     if (line.IsJust()) {
       sb << ":" << line.ToChecked();
     }
-    Maybe<int> start = try_catch.Message()->GetStartColumn(ctx);
+    Maybe<int> start = msg->GetStartColumn(ctx);
     if (start.IsJust()) {
       sb << ":"
          << start.ToChecked() + 1;  // + 1 to match output from stack trace
     }
     rtn.location = CopyString(sb.str());
+
+    Local<String> source_line;
+    if (msg->GetSourceLine(ctx).ToLocal(&source_line)) {
+      String::Utf8Value line(iso, source_line);
+      rtn.source_line = CopyString(line);
+      rtn.start_column = msg->GetStartColumn(ctx).FromMaybe(0);
+      rtn.end_column = msg->GetEndColumn(ctx).FromMaybe(0);
+    }
   }
 
   Local<Value> mstack;
@@ -48,3 +64,26 @@ RtnError ExceptionError(TryCatch& try_catch, Isolate* iso, Local<Context> ctx) {
 
   return rtn;
 }
+
+Local<Value> NewErrorOfType(ErrorTypeIndex idx, Local<String> message) {
+  switch (idx) {
+    case ERROR_RANGE:
+      return Exception::RangeError(message);
+    case ERROR_REFERENCE:
+      return Exception::ReferenceError(message);
+    case ERROR_SYNTAX:
+      return Exception::SyntaxError(message);
+    case ERROR_TYPE:
+      return Exception::TypeError(message);
+    case ERROR_WASM_COMPILE:
+      return Exception::WasmCompileError(message);
+    case ERROR_WASM_LINK:
+      return Exception::WasmLinkError(message);
+    case ERROR_WASM_RUNTIME:
+      return Exception::WasmRuntimeError(message);
+    case ERROR_GENERIC:
+      return Exception::Error(message);
+    default:
+      return Local<Value>();
+  }
+}
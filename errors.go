@@ -10,6 +10,8 @@ import "C"
 import (
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
 	"unsafe"
 )
 
@@ -20,20 +22,94 @@ type JSError struct {
 	Message    string
 	Location   string
 	StackTrace string
+	// SourceLine is the line of source code at Location, if available.
+	SourceLine string
+	// StartColumn and EndColumn are the zero-based range of SourceLine that
+	// caused the error, in UTF-16 code units as in JS strings, e.g. to render
+	// a caret under the offending token. They are only set with SourceLine.
+	StartColumn int
+	EndColumn   int
 }
 
 func newJSError(rtnErr C.RtnError) error {
 	err := &JSError{
-		Message:    C.GoString(rtnErr.msg),
-		Location:   C.GoString(rtnErr.location),
-		StackTrace: C.GoString(rtnErr.stack),
+		Message:     C.GoString(rtnErr.msg),
+		Location:    C.GoString(rtnErr.location),
+		StackTrace:  C.GoString(rtnErr.stack),
+		SourceLine:  C.GoString(rtnErr.source_line),
+		StartColumn: int(rtnErr.start_column),
+		EndColumn:   int(rtnErr.end_column),
 	}
 	C.free(unsafe.Pointer(rtnErr.msg))
 	C.free(unsafe.Pointer(rtnErr.location))
 	C.free(unsafe.Pointer(rtnErr.stack))
+	C.free(unsafe.Pointer(rtnErr.source_line))
 	return err
 }
 
+// StackFrames parses StackTrace into its frames, innermost first. V8 captures
+// zero-cost async stack traces by default, so when the error is thrown after
+// an await the trace continues past it with the async functions awaiting the
+// throwing one, which have IsAsync set. They can be turned off with the
+// `--no-async-stack-traces` flag; see SetFlags. As the frames are parsed from
+// the text of the stack, ScriptID is always 0, and frames without a source
+// position, e.g. of builtins, have their location in ScriptName and no line
+// or column.
+func (e *JSError) StackFrames() []StackFrame {
+	var frames []StackFrame
+	lines := strings.Split(e.StackTrace, "\n")
+	// The stack starts with the message, whose lines may look like frames.
+	if strings.HasPrefix(e.StackTrace, e.Message) {
+		lines = lines[strings.Count(e.Message, "\n")+1:]
+	}
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "at ") {
+			continue
+		}
+		frames = append(frames, parseStackFrame(strings.TrimPrefix(line, "at ")))
+	}
+	return frames
+}
+
+// parseStackFrame parses a frame of a V8 stack trace, without the leading
+// "at ", e.g. "async fn (script.js:3:7)" or "script.js:1:1".
+func parseStackFrame(s string) StackFrame {
+	var f StackFrame
+	if rest := strings.TrimPrefix(s, "async "); rest != s {
+		f.IsAsync = true
+		s = rest
+	}
+	if rest := strings.TrimPrefix(s, "new "); rest != s {
+		f.IsConstructor = true
+		s = rest
+	}
+	location := s
+	if i := strings.Index(s, " ("); i >= 0 && strings.HasSuffix(s, ")") {
+		f.FunctionName = s[:i]
+		location = s[i+2 : len(s)-1]
+	}
+	if strings.HasPrefix(location, "eval at ") {
+		f.IsEval = true
+		if i := strings.LastIndex(location, ", "); i >= 0 {
+			location = location[i+2:]
+		}
+	}
+	f.ScriptName = location
+	if col := strings.LastIndexByte(location, ':'); col > 0 {
+		if line := strings.LastIndexByte(location[:col], ':'); line > 0 {
+			l, lerr := strconv.Atoi(location[line+1 : col])
+			c, cerr := strconv.Atoi(location[col+1:])
+			if lerr == nil && cerr == nil {
+				f.ScriptName = location[:line]
+				f.LineNumber = l
+				f.Column = c
+			}
+		}
+	}
+	return f
+}
+
 func (e *JSError) Error() string {
 	return e.Message
 }
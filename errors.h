@@ -5,6 +5,9 @@ typedef struct {
   const char* msg;
   const char* location;
   const char* stack;
+  const char* source_line;
+  int start_column;
+  int end_column;
 } RtnError;
 
 #ifdef __cplusplus
@@ -14,7 +17,9 @@ typedef struct {
 namespace v8 {
 class Isolate;
 class Context;
+class String;
 class TryCatch;
+class Value;
 }  // namespace v8
 
 extern "C" {
@@ -45,5 +50,10 @@ typedef struct {
 
 #ifdef __cplusplus
 }
+
+// Creates an Error of the given type in the currently entered context, or an
+// empty handle if the type is unknown.
+extern v8::Local<v8::Value> NewErrorOfType(ErrorTypeIndex idx,
+                                           v8::Local<v8::String> message);
 #endif
 #endif
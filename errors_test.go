@@ -5,7 +5,10 @@
 package v8go_test
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 
 	v8 "github.com/lizc2003/v8go"
@@ -117,3 +120,142 @@ func TestJSErrorFormat_forSyntaxError(t *testing.T) {
 		t.Errorf("unexpected verbose error message: %q", msg)
 	}
 }
+
+func TestJSErrorAnonymousOrigin(t *testing.T) {
+	t.Parallel()
+	ctx := v8.NewContext(nil)
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	tests := [...]struct {
+		name   string
+		source string
+	}{
+		{"script", "throw new Error('oops')"},
+		{"eval", "eval(\"throw new Error('oops')\")"},
+		{"function", "new Function(\"throw new Error('oops')\")()"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ctx.RunScript(tt.source, "")
+			var jsErr *v8.JSError
+			if !errors.As(err, &jsErr) {
+				t.Fatalf("expected a *JSError, got %v", err)
+			}
+			if !strings.HasPrefix(jsErr.Location, v8.AnonymousScriptOrigin+":") {
+				t.Errorf("expected location to start with %q, got %q", v8.AnonymousScriptOrigin, jsErr.Location)
+			}
+			if strings.Contains(jsErr.StackTrace, "undefined") {
+				t.Errorf("unexpected undefined origin in stack trace: %q", jsErr.StackTrace)
+			}
+		})
+	}
+}
+
+func TestJSErrorSourceLine(t *testing.T) {
+	t.Parallel()
+	ctx := v8.NewContext(nil)
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	tests := [...]struct {
+		name     string
+		source   string
+		location string
+		line     string
+		start    int
+	}{
+		{"statement", "let a = 1;\nlet b = 2;\nnull.x;\nlet c = 3;", "repl.js:3:6", "null.x;", 5},
+		{"throw", "let a = 1;\n  if (a) { throw 'oops'; }", "repl.js:2:12", "  if (a) { throw 'oops'; }", 11},
+		{"syntax", "let a = 1;\nlet b = a +;", "repl.js:2:12", "let b = a +;", 11},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ctx.RunScript(tt.source, "repl.js")
+			var jsErr *v8.JSError
+			if !errors.As(err, &jsErr) {
+				t.Fatalf("expected a *JSError, got %v", err)
+			}
+			if jsErr.Location != tt.location {
+				t.Errorf("expected location %q, got %q", tt.location, jsErr.Location)
+			}
+			if jsErr.SourceLine != tt.line {
+				t.Errorf("expected source line %q, got %q", tt.line, jsErr.SourceLine)
+			}
+			if jsErr.StartColumn != tt.start {
+				t.Errorf("expected start column %d, got %d", tt.start, jsErr.StartColumn)
+			}
+			if jsErr.EndColumn <= jsErr.StartColumn || jsErr.EndColumn > len(tt.line) {
+				t.Errorf("expected end column in (%d, %d], got %d", tt.start, len(tt.line), jsErr.EndColumn)
+			}
+		})
+	}
+}
+
+func TestJSErrorStackFrames(t *testing.T) {
+	t.Parallel()
+	ctx := v8.NewContext(nil)
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	_, err := ctx.RunScript(`async function load() {
+  await null;
+  throw new Error("boom");
+}
+async function handler() {
+  await load();
+}
+let failure;
+handler().catch(e => { failure = e; });`, "async.js")
+	fatalIf(t, err)
+	ctx.PerformMicrotaskCheckpoint()
+	_, err = ctx.RunScript(`throw failure`, "rethrow.js")
+	var jsErr *v8.JSError
+	if !errors.As(err, &jsErr) {
+		t.Fatalf("expected a *JSError, got %v", err)
+	}
+	frames := jsErr.StackFrames()
+	if len(frames) != 2 {
+		t.Fatalf("expected 2 frames, got %+v", frames)
+	}
+	if want := (v8.StackFrame{FunctionName: "load", ScriptName: "async.js", LineNumber: 3, Column: 9}); frames[0] != want {
+		t.Errorf("got frame %+v, want %+v", frames[0], want)
+	}
+	if f := frames[1]; f.FunctionName != "handler" || !f.IsAsync || f.ScriptName != "async.js" || f.LineNumber != 6 {
+		t.Errorf("expected async frame of handler at line 6, got %+v", f)
+	}
+
+	_, err = ctx.RunScript("class A { constructor() { throw new Error(\"x\"); } }\nnew A();", "ctor.js")
+	if !errors.As(err, &jsErr) {
+		t.Fatalf("expected a *JSError, got %v", err)
+	}
+	want := []v8.StackFrame{
+		{FunctionName: "A", ScriptName: "ctor.js", LineNumber: 1, Column: 33, IsConstructor: true},
+		{ScriptName: "ctor.js", LineNumber: 2, Column: 1},
+	}
+	if got := jsErr.StackFrames(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got frames %+v, want %+v", got, want)
+	}
+}
+
+func TestJSErrorStackFramesSkipMessage(t *testing.T) {
+	t.Parallel()
+	ctx := v8.NewContext(nil)
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	_, err := ctx.RunScript("function f() {\n  throw new Error(\"failed\\n    at fake (fake.js:1:1)\");\n}\nf();", "message.js")
+	var jsErr *v8.JSError
+	if !errors.As(err, &jsErr) {
+		t.Fatalf("expected a *JSError, got %v", err)
+	}
+	want := []v8.StackFrame{
+		{FunctionName: "f", ScriptName: "message.js", LineNumber: 2, Column: 9},
+		{ScriptName: "message.js", LineNumber: 4, Column: 1},
+	}
+	if got := jsErr.StackFrames(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got frames %+v, want %+v", got, want)
+	}
+}
@@ -9,10 +9,27 @@ import (
 	// #include "v8go.h"
 	"C"
 
+	"errors"
 	"fmt"
+	"sort"
 	"unsafe"
 )
 
+// ErrorKind is the type of a native JS error, i.e. the constructor it is
+// created with.
+type ErrorKind int
+
+const (
+	ErrorKindRange       ErrorKind = C.ERROR_RANGE
+	ErrorKindReference   ErrorKind = C.ERROR_REFERENCE
+	ErrorKindSyntax      ErrorKind = C.ERROR_SYNTAX
+	ErrorKindType        ErrorKind = C.ERROR_TYPE
+	ErrorKindWasmCompile ErrorKind = C.ERROR_WASM_COMPILE
+	ErrorKindWasmLink    ErrorKind = C.ERROR_WASM_LINK
+	ErrorKindWasmRuntime ErrorKind = C.ERROR_WASM_RUNTIME
+	ErrorKindGeneric     ErrorKind = C.ERROR_GENERIC
+)
+
 // NewRangeError creates a RangeError.
 func NewRangeError(iso *Isolate, msg string) *Exception {
 	return newExceptionError(iso, C.ERROR_RANGE, msg)
@@ -64,6 +81,122 @@ func newExceptionError(iso *Isolate, typ C.ErrorTypeIndex, msg string) *Exceptio
 	return &Exception{&Value{ptr: eptr}}
 }
 
+// NewErrorValue creates a native error of the given kind in the given context,
+// with `name`, `message` and `stack` set as for errors created in JS. Unlike
+// the isolate-level constructors such as NewTypeError, the error inherits from
+// the context's own Error prototype, so `instanceof Error` holds in scripts
+// running in ctx. Own properties can be attached with AsObject before the
+// error is thrown, i.e. returned from a FunctionCallbackWithError.
+func NewErrorValue(ctx *Context, kind ErrorKind, msg string) (*Value, error) {
+	if ctx == nil {
+		return nil, errors.New("v8go: Context is required")
+	}
+	cmsg := C.CString(msg)
+	defer C.free(unsafe.Pointer(cmsg))
+	return valueResult(ctx, C.ContextNewError(ctx.ptr, C.ErrorTypeIndex(kind), cmsg))
+}
+
+// newErrorException returns an error of the given kind to throw from a
+// callback, or the error creating it.
+func newErrorException(ctx *Context, kind ErrorKind, msg string) error {
+	errv, err := NewErrorValue(ctx, kind, msg)
+	if err != nil {
+		return err
+	}
+	return &Exception{errv}
+}
+
+// newDOMException returns an error with the name and code of a DOMException
+// of the web platform, which V8 doesn't have, to throw from a callback, or
+// the error creating it.
+func newDOMException(ctx *Context, name string, code int32, msg string) error {
+	fields := map[string]Valuer{}
+	for k, v := range map[string]interface{}{"name": name, "message": msg, "code": code} {
+		val, err := NewValue(ctx.iso, v)
+		if err != nil {
+			return err
+		}
+		defer val.Release()
+		fields[k] = val
+	}
+	exc, err := NewErrorObject(ctx, fields)
+	if err != nil {
+		return err
+	}
+	return exc
+}
+
+// NewErrorObject creates an Error in the given context and assigns each of
+// fields as an own property of the error, e.g. a "code" for the error
+// condition. A "message" field is passed to the Error constructor instead, so
+// it behaves like the message of any other JS error.
+//
+// The result can be returned from a FunctionCallbackWithError to throw it, or
+// passed to PromiseResolver.Reject.
+func NewErrorObject(ctx *Context, fields map[string]Valuer) (*Exception, error) {
+	if ctx == nil {
+		return nil, errors.New("v8go: Context is required")
+	}
+	var msg string
+	if m, ok := fields["message"]; ok && m != nil {
+		msg = m.value().String()
+	}
+
+	val, err := NewErrorValue(ctx, ErrorKindGeneric, msg)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := val.AsObject()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		if k != "message" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := obj.Set(k, fields[k]); err != nil {
+			return nil, err
+		}
+	}
+	return &Exception{obj.Value}, nil
+}
+
+// NewCustomError creates an error with the constructor registered as name with
+// Context.SetErrorConstructor, by calling `new ctor(msg)`. Its `name` and
+// `message` are whatever the constructor sets, as for errors created in JS.
+// The result can be returned from a FunctionCallbackWithError to throw it. An
+// error is returned if no constructor is registered as name, if the
+// constructor throws, or if it doesn't create a native error, i.e. doesn't
+// extend Error.
+func NewCustomError(ctx *Context, name, msg string) (*Exception, error) {
+	if ctx == nil {
+		return nil, errors.New("v8go: Context is required")
+	}
+	ctor, ok := ctx.errorCtors[name]
+	if !ok {
+		return nil, fmt.Errorf("v8go: no error constructor registered as %q", name)
+	}
+	cmsg, err := NewValue(ctx.iso, msg)
+	if err != nil {
+		return nil, err
+	}
+	defer cmsg.Release()
+	obj, err := ctor.NewInstance(cmsg)
+	if err != nil {
+		return nil, err
+	}
+	if !obj.IsNativeError() {
+		obj.Release()
+		return nil, fmt.Errorf("v8go: error constructor %q did not create an Error", name)
+	}
+	return &Exception{obj.Value}, nil
+}
+
 // An Exception is a JavaScript exception.
 type Exception struct {
 	*Value
@@ -103,7 +236,7 @@ func (e *Exception) String() string {
 	if e.Value == nil {
 		return "<nil>"
 	}
-	s := C.ExceptionGetMessageString(e.ptr)
+	s := C.ExceptionGetMessageString(e.valuePtr())
 	defer C.free(unsafe.Pointer(s))
 	return C.GoString(s)
 }
@@ -44,6 +44,135 @@ func TestNewError(t *testing.T) {
 	}
 }
 
+func TestNewErrorObject(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	ctx := v8.NewContext(iso)
+	defer ctx.Close()
+
+	msg, _ := v8.NewValue(iso, "not found")
+	code, _ := v8.NewValue(iso, "ENOENT")
+	errno, _ := v8.NewValue(iso, int32(2))
+	errObj, err := v8.NewErrorObject(ctx, map[string]v8.Valuer{
+		"message": msg,
+		"code":    code,
+		"errno":   errno,
+	})
+	fatalIf(t, err)
+	fn := v8.NewFunctionTemplateWithError(iso, func(info *v8.FunctionCallbackInfo) (*v8.Value, error) {
+		return nil, errObj
+	})
+	fatalIf(t, ctx.Global().Set("fail", fn.GetFunction(ctx)))
+
+	val, err := ctx.RunScript(`
+		try { fail() } catch (e) {
+			[e instanceof Error, e.message, e.code, e.errno, Object.keys(e).join()].join("|")
+		}`, "")
+	fatalIf(t, err)
+	if got, want := val.String(), "true|not found|ENOENT|2|code,errno"; got != want {
+		t.Errorf("unexpected error object: got %q, want %q", got, want)
+	}
+}
+
+func TestNewErrorValue(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	ctx := v8.NewContext(iso)
+	defer ctx.Close()
+
+	fn := v8.NewFunctionTemplateWithError(iso, func(info *v8.FunctionCallbackInfo) (*v8.Value, error) {
+		val, err := v8.NewErrorValue(info.Context(), v8.ErrorKindType, "bad input")
+		if err != nil {
+			return nil, err
+		}
+		obj, err := val.AsObject()
+		if err != nil {
+			return nil, err
+		}
+		if err := obj.Set("code", "EINVAL"); err != nil {
+			return nil, err
+		}
+		return nil, &v8.Exception{Value: val}
+	})
+	fatalIf(t, ctx.Global().Set("fail", fn.GetFunction(ctx)))
+
+	val, err := ctx.RunScript(`
+		try { fail() } catch (e) {
+			[e instanceof TypeError, e.name, e.message, e.code, e.stack.startsWith("TypeError: bad input")].join("|")
+		}`, "")
+	fatalIf(t, err)
+	if got, want := val.String(), "true|TypeError|bad input|EINVAL|true"; got != want {
+		t.Errorf("unexpected error value: got %q, want %q", got, want)
+	}
+
+	if _, err := v8.NewErrorValue(nil, v8.ErrorKindGeneric, "msg"); err == nil {
+		t.Error("expected error for nil context, got <nil>")
+	}
+}
+
+func TestNewCustomError(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	ctx := v8.NewContext(iso)
+	defer ctx.Close()
+
+	ctor, err := ctx.RunScript(`
+		class MyAppError extends Error {
+			constructor(message) {
+				super(message);
+				this.name = "MyAppError";
+			}
+		}
+		MyAppError`, "")
+	fatalIf(t, err)
+	fn, err := ctor.AsFunction()
+	fatalIf(t, err)
+	fatalIf(t, ctx.SetErrorConstructor("MyAppError", fn))
+
+	if _, err := v8.NewCustomError(ctx, "OtherError", "msg"); err == nil {
+		t.Error("expected error for an unregistered constructor, got <nil>")
+	}
+
+	throw := v8.NewFunctionTemplateWithError(iso, func(info *v8.FunctionCallbackInfo) (*v8.Value, error) {
+		e, err := v8.NewCustomError(info.Context(), "MyAppError", "not found")
+		if err != nil {
+			return nil, err
+		}
+		return nil, e
+	})
+	fatalIf(t, ctx.Global().Set("fail", throw.GetFunction(ctx)))
+
+	val, err := ctx.RunScript(`
+		try { fail() } catch (e) {
+			[e instanceof MyAppError, e instanceof Error, e.name, e.message].join("|")
+		}`, "")
+	fatalIf(t, err)
+	if got, want := val.String(), "true|true|MyAppError|not found"; got != want {
+		t.Errorf("unexpected error value: got %q, want %q", got, want)
+	}
+
+	notError, err := ctx.RunScript(`(class NotAnError { constructor(message) { this.message = message } })`, "")
+	fatalIf(t, err)
+	notErrorFn, err := notError.AsFunction()
+	fatalIf(t, err)
+	fatalIf(t, ctx.SetErrorConstructor("NotAnError", notErrorFn))
+	if _, err := v8.NewCustomError(ctx, "NotAnError", "msg"); err == nil {
+		t.Error("expected error for a constructor that doesn't create an Error, got <nil>")
+	}
+
+	other := v8.NewContext(iso)
+	defer other.Close()
+	if err := other.SetErrorConstructor("MyAppError", fn); err == nil {
+		t.Error("expected error for a constructor from another context, got <nil>")
+	}
+}
+
 func TestExceptionAs(t *testing.T) {
 	iso := v8.NewIsolate()
 	defer iso.Dispose()
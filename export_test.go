@@ -4,6 +4,8 @@
 
 package v8go
 
+import "runtime"
+
 // RegisterCallback is exported for testing only.
 func (i *Isolate) RegisterCallback(cb FunctionCallbackWithError) int {
 	return i.registerCallback(cb)
@@ -14,6 +16,27 @@ func (i *Isolate) GetCallback(ref int) FunctionCallbackWithError {
 	return i.getCallback(ref)
 }
 
+// CallbackCount is exported for testing only.
+func (i *Isolate) CallbackCount() int {
+	i.cbMutex.RLock()
+	defer i.cbMutex.RUnlock()
+	return len(i.cbs)
+}
+
+// AccessCheckCount is exported for testing only.
+func (i *Isolate) AccessCheckCount() int {
+	i.cbMutex.RLock()
+	defer i.cbMutex.RUnlock()
+	return len(i.acbs)
+}
+
+// Finalize runs the finalizer of the template right away, as the garbage
+// collector would. It is exported for testing only.
+func (t *template) Finalize() {
+	runtime.SetFinalizer(t, nil)
+	t.finalizer()
+}
+
 // GetContext is exported for testing only.
 var GetContext = getContext
 
@@ -21,3 +44,31 @@ var GetContext = getContext
 func (c *Context) Ref() int {
 	return c.ref
 }
+
+// Finalize runs the finalizer of the streamer right away, as the garbage
+// collector would. It is exported for testing only.
+func (s *ScriptStreamer) Finalize() {
+	runtime.SetFinalizer(s, nil)
+	s.finalizer()
+}
+
+// AbandonedStreamerCount is exported for testing only.
+func (i *Isolate) AbandonedStreamerCount() int {
+	i.abandoned.mu.Lock()
+	defer i.abandoned.mu.Unlock()
+	return len(i.abandoned.ptrs)
+}
+
+// IsolateCallbackCount is exported for testing only.
+func (i *Isolate) IsolateCallbackCount() int {
+	isoCbMutex.Lock()
+	defer isoCbMutex.Unlock()
+	return len(i.isoCbRefs)
+}
+
+// SetHeapLimitError records a termination by the NearHeapLimitCallback, as a
+// garbage collection outside of any script would. It is exported for testing
+// only.
+func (i *Isolate) SetHeapLimitError(limit uint64) {
+	i.heapLimitErr = &HeapLimitError{Limit: limit}
+}
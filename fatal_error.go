@@ -0,0 +1,65 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+import "C"
+import (
+	"fmt"
+	"sync"
+)
+
+// FatalError is an error V8 can't recover from, such as running out of
+// memory or misuse of its API.
+type FatalError struct {
+	Location string
+	Message  string
+	// OutOfMemory is true if V8 failed to allocate memory, and HeapOutOfMemory
+	// if it was the JS heap of an isolate that reached its limit.
+	OutOfMemory     bool
+	HeapOutOfMemory bool
+}
+
+func (e *FatalError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("v8go: fatal error in %s", e.Location)
+	}
+	return fmt.Sprintf("v8go: fatal error in %s: %s", e.Location, e.Message)
+}
+
+var (
+	fatalErrorMutex   sync.Mutex
+	fatalErrorHandler func(err *FatalError)
+)
+
+// SetFatalErrorHandler sets a function that is called when any isolate hits
+// a FatalError, or removes it if handler is nil. The process is aborted once
+// the handler returns, so it can't prevent the crash, but it can log the
+// error or flush state the process would otherwise lose. The handler runs on
+// the thread of the failing isolate and must not use the isolate.
+//
+// Exceeding the maximum call stack size isn't fatal: V8 throws a RangeError
+// when JS recurses too deeply, which is returned as a *JSError like any other
+// exception.
+func SetFatalErrorHandler(handler func(err *FatalError)) {
+	fatalErrorMutex.Lock()
+	defer fatalErrorMutex.Unlock()
+	fatalErrorHandler = handler
+}
+
+//export goFatalError
+func goFatalError(location, message *C.char, oom, heapOOM C.int) {
+	fatalErrorMutex.Lock()
+	handler := fatalErrorHandler
+	fatalErrorMutex.Unlock()
+	if handler == nil {
+		return
+	}
+	handler(&FatalError{
+		Location:        C.GoString(location),
+		Message:         C.GoString(message),
+		OutOfMemory:     oom != 0,
+		HeapOutOfMemory: heapOOM != 0,
+	})
+}
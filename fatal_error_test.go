@@ -0,0 +1,80 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"errors"
+	"testing"
+
+	v8 "github.com/lizc2003/v8go"
+)
+
+func TestStackOverflowIsJSError(t *testing.T) {
+	t.Parallel()
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	// Recurse through a Go callback as well, so the native frames of each
+	// call count towards the stack limit.
+	var recurse *v8.Function
+	viaGo := v8.NewFunctionTemplateWithError(iso, func(info *v8.FunctionCallbackInfo) (*v8.Value, error) {
+		return recurse.Call(v8.Undefined(iso))
+	})
+	global := v8.NewObjectTemplate(iso)
+	fatalIf(t, global.Set("viaGo", viaGo))
+	ctx := v8.NewContext(iso, global)
+	defer ctx.Close()
+
+	_, err := ctx.RunScript("function recurse() { return viaGo() }", "recurse.js")
+	fatalIf(t, err)
+	val, err := ctx.Global().Get("recurse")
+	fatalIf(t, err)
+	recurse, err = val.AsFunction()
+	fatalIf(t, err)
+
+	tests := [...]struct {
+		name   string
+		source string
+	}{
+		{"js", "function f() { return f() + 1; } f()"},
+		{"go", "recurse()"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ctx.RunScript(tt.source, "overflow.js")
+			var jsErr *v8.JSError
+			if !errors.As(err, &jsErr) {
+				t.Fatalf("expected a *JSError, got %v", err)
+			}
+			if jsErr.Message != "RangeError: Maximum call stack size exceeded" {
+				t.Errorf("unexpected error message %q", jsErr.Message)
+			}
+		})
+	}
+
+	// The isolate is still usable after the stack overflow.
+	val, err = ctx.RunScript("1 + 1", "after.js")
+	fatalIf(t, err)
+	if val.Integer() != 2 {
+		t.Errorf("expected 2, got %v", val)
+	}
+}
+
+func TestFatalError(t *testing.T) {
+	t.Parallel()
+
+	err := &v8.FatalError{Location: "v8::Object::New", Message: "bad argument"}
+	if got, want := err.Error(), "v8go: fatal error in v8::Object::New: bad argument"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	err = &v8.FatalError{Location: "Heap::CollectGarbage", OutOfMemory: true, HeapOutOfMemory: true}
+	if got, want := err.Error(), "v8go: fatal error in Heap::CollectGarbage"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	v8.SetFatalErrorHandler(func(*v8.FatalError) {})
+	v8.SetFatalErrorHandler(nil)
+}
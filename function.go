@@ -7,6 +7,7 @@ package v8go
 // #include "v8go.h"
 import "C"
 import (
+	"errors"
 	"unsafe"
 )
 
@@ -21,30 +22,85 @@ func (fn *Function) Call(recv Valuer, args ...Valuer) (*Value, error) {
 	if len(args) > 0 {
 		var cArgs = make([]C.ValuePtr, len(args))
 		for i, arg := range args {
-			cArgs[i] = arg.value().ptr
+			cArgs[i] = arg.value().valuePtr()
 		}
 		argptr = (*C.ValuePtr)(unsafe.Pointer(&cArgs[0]))
 	}
-	rtn := C.FunctionCall(fn.ptr, recv.value().ptr, C.int(len(args)), argptr)
+	rtn := C.FunctionCall(fn.valuePtr(), recv.value().valuePtr(), C.int(len(args)), argptr)
 	return valueResult(fn.ctx, rtn)
 }
 
-// Invoke a constructor function to create an object instance.
+// CallAndRelease calls the function like Call and then releases args, whether
+// the call returns or throws, for the common pattern of creating arguments
+// only to pass them to a single call. The args are consumed and must not be
+// used after the call; the receiver and the result are not released. nil
+// args are passed as undefined, and the isolate's shared undefined and null
+// values are never released.
+func (fn *Function) CallAndRelease(recv Valuer, args ...*Value) (*Value, error) {
+	vals := make([]Valuer, len(args))
+	for i, arg := range args {
+		if arg == nil {
+			vals[i] = Undefined(fn.ctx.iso)
+		} else {
+			vals[i] = arg
+		}
+	}
+	defer releaseValues(args)
+	return fn.Call(recv, vals...)
+}
+
+// Bind creates a new function that, when called, calls this function with
+// recv as "this" and args prepended to the arguments it is called with.
+// This is equivalent to `fn.bind(recv, ...args)` in JS, with the built-in
+// bind even if a script replaced it.
+func (fn *Function) Bind(recv Valuer, args ...Valuer) (*Function, error) {
+	bindArgs := make([]Valuer, 0, len(args)+1)
+	bindArgs = append(bindArgs, recv)
+	bindArgs = append(bindArgs, args...)
+	bound, err := callBuiltin(fn.ctx, C.INTRINSIC_FUNCTION_BIND, fn, bindArgs...)
+	if err != nil {
+		return nil, err
+	}
+	return bound.AsFunction()
+}
+
+// Invoke a constructor function to create an object instance. Calling
+// NewInstance on a function that is not a constructor throws a TypeError; see
+// IsConstructor.
 func (fn *Function) NewInstance(args ...Valuer) (*Object, error) {
 	var argptr *C.ValuePtr
 	if len(args) > 0 {
 		var cArgs = make([]C.ValuePtr, len(args))
 		for i, arg := range args {
-			cArgs[i] = arg.value().ptr
+			cArgs[i] = arg.value().valuePtr()
 		}
 		argptr = (*C.ValuePtr)(unsafe.Pointer(&cArgs[0]))
 	}
-	rtn := C.FunctionNewInstance(fn.ptr, C.int(len(args)), argptr)
+	rtn := C.FunctionNewInstance(fn.valuePtr(), C.int(len(args)), argptr)
 	return objectResult(fn.ctx, rtn)
 }
 
+// Prototype returns the object in the `prototype` property of the function,
+// which instances created with `new` inherit from, so methods can be added to
+// a class defined in JS. An error is returned if the property doesn't hold an
+// object, e.g. for arrow functions and methods, which have no prototype.
+func (fn *Function) Prototype() (*Object, error) {
+	obj, err := fn.AsObject()
+	if err != nil {
+		return nil, err
+	}
+	proto, err := obj.Get("prototype")
+	if err != nil {
+		return nil, err
+	}
+	if !proto.IsObject() {
+		return nil, errors.New("v8go: function has no prototype object")
+	}
+	return proto.AsObject()
+}
+
 // Return the source map url for a function.
 func (fn *Function) SourceMapUrl() *Value {
-	ptr := C.FunctionSourceMapUrl(fn.ptr)
-	return &Value{ptr, fn.ctx}
+	ptr := C.FunctionSourceMapUrl(fn.valuePtr())
+	return &Value{ptr: ptr, ctx: fn.ctx}
 }
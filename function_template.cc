@@ -49,8 +49,10 @@ void FunctionTemplateCallback(const FunctionCallbackInfo<Value>& info) {
   }
 }
 
-TemplatePtr NewFunctionTemplate(IsolatePtr iso, int callback_ref) {
-  Locker locker(iso);
+TemplatePtr NewFunctionTemplate(IsolatePtr iso,
+                                int callback_ref,
+                                TemplatePtr receiver) {
+  IsolateLocker locker(iso);
   Isolate::Scope isolate_scope(iso);
   HandleScope handle_scope(iso);
 
@@ -60,10 +62,16 @@ TemplatePtr NewFunctionTemplate(IsolatePtr iso, int callback_ref) {
   // iso->GetData(0)
   Local<Integer> cbData = Integer::New(iso, callback_ref);
 
+  Local<Signature> signature;
+  if (receiver != nullptr) {
+    signature = Signature::New(
+        iso, receiver->ptr.Get(iso).As<FunctionTemplate>());
+  }
+
   m_template* ot = new m_template;
   ot->iso = iso;
-  ot->ptr.Reset(iso,
-                FunctionTemplate::New(iso, FunctionTemplateCallback, cbData));
+  ot->ptr.Reset(iso, FunctionTemplate::New(iso, FunctionTemplateCallback,
+                                           cbData, signature));
   return ot;
 }
 
@@ -8,6 +8,8 @@ package v8go
 // #include "function_template.h"
 import "C"
 import (
+	"fmt"
+	"math"
 	"runtime"
 	"unsafe"
 )
@@ -26,6 +28,7 @@ type FunctionCallbackInfo struct {
 	ctx  *Context
 	args []*Value
 	this *Object
+	data *Value
 }
 
 // A ValueError can be returned from a FunctionCallbackWithError, and
@@ -45,16 +48,168 @@ func (i *FunctionCallbackInfo) This() *Object {
 	return i.this
 }
 
+// Holder returns the object the callback was invoked on for the purposes of a
+// Receiver signature check. It corresponds to FunctionCallbackInfo::Holder in
+// the C++ API, which differed from This only when the receiver matched the
+// signature through a hidden prototype. V8 no longer has hidden prototypes,
+// so Holder is always the same object as This, and V8 deprecates Holder in
+// favour of This.
+func (i *FunctionCallbackInfo) Holder() *Object {
+	return i.this
+}
+
+// Data returns the value set as FunctionTemplateOptions.Data when the
+// function's template was created, or undefined if none was set.
+func (i *FunctionCallbackInfo) Data() *Value {
+	if i.data == nil {
+		return Undefined(i.ctx.iso)
+	}
+	return i.data
+}
+
 // Args returns a slice of the value arguments that are passed to the JS function.
 func (i *FunctionCallbackInfo) Args() []*Value {
 	return i.args
 }
 
+// ArgumentError is returned by the FunctionCallbackInfo.Arg helpers when an
+// argument is missing or of the wrong type. Returned from a
+// FunctionCallbackWithError, it is thrown as a JS error with the same message.
+type ArgumentError struct {
+	// Index is the position of the argument.
+	Index int
+	// Expected is the type the callback expected, e.g. "string".
+	Expected string
+	// Got is the JS type of the argument passed, as returned by `typeof`
+	// except that null is "null", or empty if the argument is missing.
+	Got string
+}
+
+func (e *ArgumentError) Error() string {
+	if e.Got == "" {
+		return fmt.Sprintf("missing argument %d: expected %s", e.Index, e.Expected)
+	}
+	return fmt.Sprintf("argument %d: expected %s, got %s", e.Index, e.Expected, e.Got)
+}
+
+// arg returns argument i if it is present and ok returns true for it, and an
+// *ArgumentError otherwise.
+func (i *FunctionCallbackInfo) arg(idx int, expected string, ok func(*Value) bool) (*Value, error) {
+	if idx < 0 || idx >= len(i.args) {
+		return nil, &ArgumentError{Index: idx, Expected: expected}
+	}
+	v := i.args[idx]
+	if !ok(v) {
+		return nil, &ArgumentError{Index: idx, Expected: expected, Got: typeOf(v)}
+	}
+	return v, nil
+}
+
+// ArgString returns argument idx as a string. The argument must be a string;
+// other values are not converted.
+func (i *FunctionCallbackInfo) ArgString(idx int) (string, error) {
+	v, err := i.arg(idx, "string", (*Value).IsString)
+	if err != nil {
+		return "", err
+	}
+	return v.String(), nil
+}
+
+// ArgInt returns argument idx as an integer. The argument must be a number
+// without a fractional part that fits in an int64.
+func (i *FunctionCallbackInfo) ArgInt(idx int) (int64, error) {
+	v, err := i.arg(idx, "integer", func(v *Value) bool {
+		if !v.IsNumber() {
+			return false
+		}
+		f := v.Number()
+		return f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64
+	})
+	if err != nil {
+		return 0, err
+	}
+	return v.Integer(), nil
+}
+
+// ArgFloat returns argument idx as a float64. The argument must be a number.
+func (i *FunctionCallbackInfo) ArgFloat(idx int) (float64, error) {
+	v, err := i.arg(idx, "number", (*Value).IsNumber)
+	if err != nil {
+		return 0, err
+	}
+	return v.Number(), nil
+}
+
+// ArgObject returns argument idx as an Object. The argument must be an
+// object, which includes functions and arrays but not null.
+func (i *FunctionCallbackInfo) ArgObject(idx int) (*Object, error) {
+	v, err := i.arg(idx, "object", (*Value).IsObject)
+	if err != nil {
+		return nil, err
+	}
+	return v.AsObject()
+}
+
+// TaggedTemplate returns the parts of a tagged template literal when the
+// function is called as its tag, e.g. sql`SELECT * FROM t WHERE id = ${id}`.
+// cooked holds the literal strings with escape sequences processed, or
+// undefined for invalid escapes, raw holds them as written in the source, and
+// substitutions holds the values of the ${} expressions, one fewer than the
+// strings. Keeping the strings and the substitutions apart lets a tag escape
+// the substitutions safely, e.g. to build SQL queries.
+//
+// ok is false if the arguments don't have the shape V8 passes to tags: an
+// array of strings with a raw array of the same length, followed by one
+// substitution less than there are strings. As that shape can be built by
+// hand, ok does not prove the function was called as a tag.
+func (i *FunctionCallbackInfo) TaggedTemplate() (cooked *Array, raw *Array, substitutions []*Value, ok bool) {
+	if len(i.args) == 0 || !i.args[0].IsArray() {
+		return nil, nil, nil, false
+	}
+	cooked, _ = i.args[0].AsArray()
+	rawVal, err := cooked.Get("raw")
+	if err != nil || !rawVal.IsArray() {
+		return nil, nil, nil, false
+	}
+	raw, _ = rawVal.AsArray()
+	if raw.Length() != cooked.Length() || int(cooked.Length()) != len(i.args) {
+		return nil, nil, nil, false
+	}
+	return cooked, raw, i.args[1:], true
+}
+
+// typeOf returns the result of `typeof v` in JS, except that null is "null".
+func typeOf(v *Value) string {
+	switch {
+	case v.IsUndefined():
+		return "undefined"
+	case v.IsNull():
+		return "null"
+	case v.IsBoolean():
+		return "boolean"
+	case v.IsNumber():
+		return "number"
+	case v.IsString():
+		return "string"
+	case v.IsSymbol():
+		return "symbol"
+	case v.IsBigInt():
+		return "bigint"
+	case v.IsFunction():
+		return "function"
+	}
+	return "object"
+}
+
+// Release releases the arguments and the receiver of the call together, in a
+// single call into V8. They must not be used afterwards.
 func (i *FunctionCallbackInfo) Release() {
-	for _, arg := range i.args {
-		arg.Release()
+	vals := make([]*Value, 0, len(i.args)+1)
+	vals = append(vals, i.args...)
+	if i.this != nil {
+		vals = append(vals, i.this.Value)
 	}
-	i.this.Release()
+	releaseValues(vals)
 }
 
 // FunctionTemplate is used to create functions at runtime.
@@ -88,6 +243,29 @@ func NewFunctionTemplate(iso *Isolate, callback FunctionCallback) *FunctionTempl
 func NewFunctionTemplateWithError(
 	iso *Isolate,
 	callback FunctionCallbackWithError,
+) *FunctionTemplate {
+	return NewFunctionTemplateWithOptions(iso, callback, FunctionTemplateOptions{})
+}
+
+// FunctionTemplateOptions are the optional settings of a FunctionTemplate.
+type FunctionTemplateOptions struct {
+	// Data is passed to every call of the callback as
+	// FunctionCallbackInfo.Data, so one callback can serve several templates.
+	Data Valuer
+
+	// Receiver restricts the receiver ("this") of the function to instances
+	// of the Receiver template. Calling the function on any other receiver
+	// throws a TypeError without invoking the callback. This is the usual
+	// setting for methods on a PrototypeTemplate.
+	Receiver *FunctionTemplate
+}
+
+// NewFunctionTemplateWithOptions creates a FunctionTemplate for a given
+// callback, like NewFunctionTemplateWithError, with the given options.
+func NewFunctionTemplateWithOptions(
+	iso *Isolate,
+	callback FunctionCallbackWithError,
+	opts FunctionTemplateOptions,
 ) *FunctionTemplate {
 	if iso == nil {
 		panic("nil Isolate argument not supported")
@@ -96,18 +274,33 @@ func NewFunctionTemplateWithError(
 		panic("nil FunctionCallback argument not supported")
 	}
 
+	if opts.Data != nil {
+		data := opts.Data.value()
+		cb := callback
+		callback = func(info *FunctionCallbackInfo) (*Value, error) {
+			info.data = data
+			return cb(info)
+		}
+	}
 	cbref := iso.registerCallback(callback)
 
+	var receiver *C.m_template
+	if opts.Receiver != nil {
+		receiver = opts.Receiver.ptr
+	}
 	tmpl := &template{
-		ptr: C.NewFunctionTemplate(iso.ptr, C.int(cbref)),
-		iso: iso,
+		ptr:   C.NewFunctionTemplate(iso.ptr, C.int(cbref), receiver),
+		iso:   iso,
+		cbref: cbref,
 	}
+	runtime.KeepAlive(opts.Receiver)
 	runtime.SetFinalizer(tmpl, (*template).finalizer)
 	return &FunctionTemplate{tmpl}
 }
 
 // GetFunction returns an instance of this function template bound to the given context.
 func (tmpl *FunctionTemplate) GetFunction(ctx *Context) *Function {
+	tmpl.escaped = true
 	rtn := C.FunctionTemplateGetFunction(tmpl.ptr, ctx.ptr)
 	runtime.KeepAlive(tmpl)
 	val, err := valueResult(ctx, rtn)
@@ -135,9 +328,13 @@ func (tmpl *FunctionTemplate) GetFunction(ctx *Context) *Function {
 //
 // [own properties]: https://developer.mozilla.org/en-US/docs/Web/JavaScript/Enumerability_and_ownership_of_properties
 func (tmpl *FunctionTemplate) InstanceTemplate() *ObjectTemplate {
+	// Instantiating the instance template creates the constructor function.
+	tmpl.escaped = true
 	result := &template{
 		ptr: C.FunctionTemplateInstanceTemplate(tmpl.ptr),
 		iso: tmpl.iso,
+		// Objects are created from it along with the function.
+		escaped: true,
 	}
 	runtime.SetFinalizer(result, (*template).finalizer)
 	return &ObjectTemplate{result}
@@ -165,15 +362,19 @@ func (tmpl *FunctionTemplate) InstanceTemplate() *ObjectTemplate {
 //
 // [own property]: https://developer.mozilla.org/en-US/docs/Web/JavaScript/Enumerability_and_ownership_of_properties
 func (tmpl *FunctionTemplate) PrototypeTemplate() *ObjectTemplate {
+	tmpl.escaped = true
 	result := &template{
 		ptr: C.FunctionTemplatePrototypeTemplate(tmpl.ptr),
 		iso: tmpl.iso,
+		// Objects are created from it along with the function.
+		escaped: true,
 	}
 	runtime.SetFinalizer(result, (*template).finalizer)
 	return &ObjectTemplate{result}
 }
 
 func (tmpl *FunctionTemplate) Inherit(base *FunctionTemplate) {
+	base.escaped = true
 	C.FunctionTemplateInherit(tmpl.ptr, base.ptr)
 }
 
@@ -21,7 +21,9 @@ typedef struct v8Isolate v8Isolate;
 typedef struct m_template m_template;
 typedef struct m_ctx m_ctx;
 
-extern m_template* NewFunctionTemplate(v8Isolate* iso_ptr, int callback_ref);
+extern m_template* NewFunctionTemplate(v8Isolate* iso_ptr,
+                                      int callback_ref,
+                                      m_template* receiver);
 extern RtnValue FunctionTemplateGetFunction(m_template* ptr, m_ctx* ctx_ptr);
 extern m_template* FunctionTemplateInstanceTemplate(m_template* ptr);
 extern m_template* FunctionTemplatePrototypeTemplate(m_template* ptr);
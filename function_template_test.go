@@ -301,6 +301,204 @@ func TestFunctionTemplate_prototype_template(t *testing.T) {
 	}
 }
 
+func TestFunctionTemplateWithOptions(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	// One callback serves both methods, parameterized by the template data.
+	greet := func(info *v8.FunctionCallbackInfo) (*v8.Value, error) {
+		return v8.NewValue(iso, "hello "+info.Data().String())
+	}
+
+	constructor := v8.NewFunctionTemplate(iso,
+		func(info *v8.FunctionCallbackInfo) *v8.Value { return nil })
+	for _, name := range []string{"alice", "bob"} {
+		data, err := v8.NewValue(iso, name)
+		fatalIf(t, err)
+		method := v8.NewFunctionTemplateWithOptions(iso, greet, v8.FunctionTemplateOptions{
+			Data:     data,
+			Receiver: constructor,
+		})
+		fatalIf(t, constructor.PrototypeTemplate().Set(name, method))
+	}
+	global := v8.NewObjectTemplate(iso)
+	fatalIf(t, global.Set("Foo", constructor))
+
+	ctx := v8.NewContext(iso, global)
+	defer ctx.Close()
+
+	val, err := ctx.RunScript("const foo = new Foo(); [foo.alice(), foo.bob()].join()", "")
+	fatalIf(t, err)
+	if val.String() != "hello alice,hello bob" {
+		t.Errorf("unexpected value: %q", val.String())
+	}
+
+	if _, err := ctx.RunScript("Foo.prototype.alice.call({})", ""); err == nil {
+		t.Error("expected error calling method on a receiver of the wrong type")
+	}
+}
+
+func TestFunctionCallbackInfoHolder(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	constructor := v8.NewFunctionTemplate(iso,
+		func(info *v8.FunctionCallbackInfo) *v8.Value { return nil })
+	constructor.InstanceTemplate().SetInternalFieldCount(1)
+	getID := v8.NewFunctionTemplateWithOptions(iso,
+		func(info *v8.FunctionCallbackInfo) (*v8.Value, error) {
+			return info.Holder().GetInternalField(0), nil
+		},
+		v8.FunctionTemplateOptions{Receiver: constructor})
+	fatalIf(t, constructor.PrototypeTemplate().Set("id", getID))
+	global := v8.NewObjectTemplate(iso)
+	fatalIf(t, global.Set("Foo", constructor))
+
+	ctx := v8.NewContext(iso, global)
+	defer ctx.Close()
+
+	foo, err := ctx.RunScript("new Foo()", "")
+	fatalIf(t, err)
+	obj, err := foo.AsObject()
+	fatalIf(t, err)
+	fatalIf(t, obj.SetInternalField(0, "foo-1"))
+	fatalIf(t, ctx.Global().Set("foo", obj))
+
+	val, err := ctx.RunScript("foo.id()", "")
+	fatalIf(t, err)
+	if val.String() != "foo-1" {
+		t.Errorf("expected internal field of the holder, got %q", val.String())
+	}
+}
+
+func TestFunctionCallbackInfoData_undefined(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	var data *v8.Value
+	fn := v8.NewFunctionTemplate(iso, func(info *v8.FunctionCallbackInfo) *v8.Value {
+		data = info.Data()
+		return nil
+	})
+	ctx := v8.NewContext(iso)
+	defer ctx.Close()
+
+	_, err := fn.GetFunction(ctx).Call(v8.Undefined(iso))
+	fatalIf(t, err)
+	if data == nil || !data.IsUndefined() {
+		t.Errorf("expected undefined data for a template without data, got %v", data)
+	}
+}
+
+func TestFunctionCallbackInfoArgs(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	ctx := v8.NewContext(iso)
+	defer ctx.Close()
+
+	fn := v8.NewFunctionTemplateWithError(iso, func(info *v8.FunctionCallbackInfo) (*v8.Value, error) {
+		s, err := info.ArgString(0)
+		if err != nil {
+			return nil, err
+		}
+		n, err := info.ArgInt(1)
+		if err != nil {
+			return nil, err
+		}
+		f, err := info.ArgFloat(2)
+		if err != nil {
+			return nil, err
+		}
+		obj, err := info.ArgObject(3)
+		if err != nil {
+			return nil, err
+		}
+		x, err := obj.Get("x")
+		if err != nil {
+			return nil, err
+		}
+		return v8.NewValue(iso, fmt.Sprintf("%s %d %.1f %s", s, n, f, x))
+	})
+	fatalIf(t, ctx.Global().Set("fn", fn.GetFunction(ctx)))
+
+	val, err := ctx.RunScript(`fn("a", 2, 0.5, {x: "y"})`, "args.js")
+	fatalIf(t, err)
+	if got, want := val.String(), "a 2 0.5 y"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	tests := [...]struct {
+		source string
+		want   string
+	}{
+		{`fn()`, "missing argument 0: expected string"},
+		{`fn(1)`, "argument 0: expected string, got number"},
+		{`fn("a", 1.5)`, "argument 1: expected integer, got number"},
+		{`fn("a", 1, "2")`, "argument 2: expected number, got string"},
+		{`fn("a", 1, 2, null)`, "argument 3: expected object, got null"},
+	}
+	for _, tt := range tests {
+		_, err := ctx.RunScript(tt.source, "args.js")
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.source, tt.want, err)
+		}
+	}
+}
+
+func TestFunctionCallbackInfoTaggedTemplate(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	ctx := v8.NewContext(iso)
+	defer ctx.Close()
+
+	// sql builds a parameterized query from a tagged template.
+	sql := v8.NewFunctionTemplateWithError(iso, func(info *v8.FunctionCallbackInfo) (*v8.Value, error) {
+		cooked, raw, subs, ok := info.TaggedTemplate()
+		if !ok {
+			return v8.NewValue(iso, "not a tagged template")
+		}
+		strs, err := cooked.Slice()
+		if err != nil {
+			return nil, err
+		}
+		rawStrs, err := raw.Slice()
+		if err != nil {
+			return nil, err
+		}
+		var query strings.Builder
+		for i, s := range strs {
+			query.WriteString(s.String())
+			if i < len(subs) {
+				fmt.Fprintf(&query, "$%d", i+1)
+			}
+		}
+		return v8.NewValue(iso, fmt.Sprintf("%s %v %s", query.String(), subs, rawStrs[len(rawStrs)-1]))
+	})
+	fatalIf(t, ctx.Global().Set("sql", sql.GetFunction(ctx)))
+
+	val, err := ctx.RunScript("const id = 7; sql`SELECT * FROM t WHERE id = ${id} AND name = ${'bob'}\\n`", "tagged.js")
+	fatalIf(t, err)
+	if got, want := val.String(), "SELECT * FROM t WHERE id = $1 AND name = $2\n [7 bob] \\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	val, err = ctx.RunScript(`sql("SELECT 1")`, "tagged.js")
+	fatalIf(t, err)
+	if got := val.String(); got != "not a tagged template" {
+		t.Errorf("expected a plain call not to be a tagged template, got %q", got)
+	}
+}
+
 func TestFunctionTemplate_inherit(t *testing.T) {
 	t.Parallel()
 
@@ -376,3 +574,28 @@ func ExampleFunctionTemplate() {
 	// Output:
 	// [foo bar 0 1]
 }
+
+func ExampleFunctionCallbackInfo_Data() {
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	// A single callback, parameterized by the data of each template.
+	log := func(info *v8.FunctionCallbackInfo) (*v8.Value, error) {
+		fmt.Printf("[%s] %s\n", info.Data(), info.Args()[0])
+		return nil, nil
+	}
+	console := v8.NewObjectTemplate(iso)
+	for _, level := range []string{"info", "warn"} {
+		data, _ := v8.NewValue(iso, level)
+		console.Set(level, v8.NewFunctionTemplateWithOptions(iso, log, v8.FunctionTemplateOptions{Data: data}))
+	}
+	global := v8.NewObjectTemplate(iso)
+	global.Set("console", console)
+
+	ctx := v8.NewContext(iso, global)
+	defer ctx.Close()
+	ctx.RunScript("console.info('started'); console.warn('low memory')", "")
+	// Output:
+	// [info] started
+	// [warn] low memory
+}
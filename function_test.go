@@ -5,6 +5,7 @@
 package v8go_test
 
 import (
+	"strings"
 	"testing"
 
 	v8 "github.com/lizc2003/v8go"
@@ -94,6 +95,117 @@ func TestFunctionCallWithObjectReceiver(t *testing.T) {
 	}
 }
 
+func TestFunctionBind(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContext()
+	iso := ctx.Isolate()
+	defer iso.Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`
+		const recv = { prefix: "id" };
+		(function (a, b) { return [this.prefix, a, b].join("-"); })`, "")
+	fatalIf(t, err)
+	fn, _ := val.AsFunction()
+	recv, err := ctx.RunScript("recv", "")
+	fatalIf(t, err)
+	first, _ := v8.NewValue(iso, "first")
+
+	bound, err := fn.Bind(recv, first)
+	fatalIf(t, err)
+
+	second, _ := v8.NewValue(iso, "second")
+	result, err := bound.Call(v8.Undefined(iso), second)
+	fatalIf(t, err)
+	if got, want := result.String(), "id-first-second"; got != want {
+		t.Errorf("unexpected result of bound function: got %q, want %q", got, want)
+	}
+
+	_, err = ctx.RunScript(`Function.prototype.bind = () => { throw new Error("replaced"); }`, "")
+	fatalIf(t, err)
+	if _, err := fn.Bind(recv); err != nil {
+		t.Errorf("expected the built-in bind, got %v", err)
+	}
+}
+
+func TestFunctionCallAndRelease(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContext()
+	iso := ctx.Isolate()
+	defer iso.Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`(a, b) => { if (b === undefined) throw new Error("missing b"); return a + b; }`, "")
+	fatalIf(t, err)
+	add, _ := val.AsFunction()
+
+	a, _ := v8.NewValue(iso, int32(40))
+	b, _ := v8.NewValue(iso, int32(2))
+	result, err := add.CallAndRelease(v8.Undefined(iso), a, b)
+	fatalIf(t, err)
+	if result.Integer() != 42 {
+		t.Errorf("expected 42, got %v", result)
+	}
+	if recoverPanic(func() { a.IsNumber() }) == nil || recoverPanic(func() { b.IsNumber() }) == nil {
+		t.Error("expected the arguments to be released")
+	}
+
+	c, _ := v8.NewValue(iso, int32(1))
+	if _, err := add.CallAndRelease(v8.Undefined(iso), c, v8.Undefined(iso)); err == nil {
+		t.Error("expected error, got <nil>")
+	}
+	if recoverPanic(func() { c.IsNumber() }) == nil {
+		t.Error("expected the argument to be released after a throw")
+	}
+	if !v8.Undefined(iso).IsUndefined() {
+		t.Error("expected the shared undefined value not to be released")
+	}
+
+	// nil args are passed as undefined.
+	d, _ := v8.NewValue(iso, int32(1))
+	if _, err := add.CallAndRelease(v8.Undefined(iso), d, nil); err == nil || !strings.Contains(err.Error(), "missing b") {
+		t.Errorf("expected the nil argument to be undefined, got %v", err)
+	}
+}
+
+func TestFunctionPrototype(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContext()
+	iso := ctx.Isolate()
+	defer iso.Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`class Point { constructor(x, y) { this.x = x; this.y = y; } }; Point`, "")
+	fatalIf(t, err)
+	point, _ := val.AsFunction()
+	proto, err := point.Prototype()
+	fatalIf(t, err)
+
+	norm := v8.NewFunctionTemplate(iso, func(info *v8.FunctionCallbackInfo) *v8.Value {
+		x, _ := info.This().Get("x")
+		y, _ := info.This().Get("y")
+		n, _ := v8.NewValue(iso, x.Number()*x.Number()+y.Number()*y.Number())
+		return n
+	})
+	fatalIf(t, proto.Set("norm2", norm.GetFunction(ctx)))
+
+	result, err := ctx.RunScript(`new Point(3, 4).norm2()`, "")
+	fatalIf(t, err)
+	if result.Number() != 25 {
+		t.Errorf("expected 25, got %v", result)
+	}
+
+	val, err = ctx.RunScript(`() => {}`, "")
+	fatalIf(t, err)
+	arrow, _ := val.AsFunction()
+	if _, err := arrow.Prototype(); err == nil {
+		t.Error("expected error for an arrow function, got <nil>")
+	}
+}
+
 func TestFunctionCallError(t *testing.T) {
 	t.Parallel()
 
@@ -113,7 +225,7 @@ func TestFunctionCallError(t *testing.T) {
 		t.Errorf("expected an error, got none")
 	}
 	got := *(err.(*v8.JSError))
-	want := v8.JSError{Message: "error", Location: "script.js:1:21"}
+	want := v8.JSError{Message: "error", Location: "script.js:1:21", SourceLine: "function throws() { throw 'error'; }", StartColumn: 20, EndColumn: 21}
 	if got != want {
 		t.Errorf("want %+v, got: %+v", want, got)
 	}
@@ -197,7 +309,7 @@ func TestFunctionNewInstanceError(t *testing.T) {
 		t.Errorf("expected an error, got none")
 	}
 	got := *(err.(*v8.JSError))
-	want := v8.JSError{Message: "error", Location: "script.js:1:21"}
+	want := v8.JSError{Message: "error", Location: "script.js:1:21", SourceLine: "function throws() { throw 'error'; }", StartColumn: 20, EndColumn: 21}
 	if got != want {
 		t.Errorf("want %+v, got: %+v", want, got)
 	}
@@ -0,0 +1,120 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+import "sort"
+
+// GlobalNames returns the names of the own enumerable properties of the
+// global object of the context, sorted. The built-in globals, such as Array or
+// JSON, are not enumerable, so these are mostly the globals defined by scripts
+// and the embedder: `var` and function declarations at the top level of a
+// script, and assignments to undeclared variables. Top-level `let`, `const`
+// and `class` declarations don't create properties of the global object, so
+// they are not included.
+func (c *Context) GlobalNames() ([]string, error) {
+	global := c.Global()
+	defer global.Release()
+	names, err := global.GetOwnPropertyNames()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// GlobalsSnapshot records the enumerable globals of a context and their
+// values, to find out with Diff which globals a script added, removed or
+// changed, e.g. to reject scripts that pollute the global scope. Create one
+// with Context.SnapshotGlobals.
+type GlobalsSnapshot struct {
+	ctx    *Context
+	values map[string]*Value
+}
+
+// GlobalsDiff is the difference between the globals of a context and a
+// GlobalsSnapshot of them, as the sorted names of the globals in each case.
+type GlobalsDiff struct {
+	// Added are the globals that are not in the snapshot.
+	Added []string
+	// Removed are the globals of the snapshot that no longer exist.
+	Removed []string
+	// Modified are the globals whose value is not the same value as in the
+	// snapshot, as by `Object.is`. Changes to the properties of a global
+	// object, e.g. `config.debug = true`, do not modify the global.
+	Modified []string
+}
+
+// Empty reports whether the diff has no changes.
+func (d GlobalsDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0
+}
+
+// SnapshotGlobals records the globals of the context, as returned by
+// GlobalNames, and their values. Reading the values runs the getters of
+// accessor properties. Release the snapshot when done with it.
+func (c *Context) SnapshotGlobals() (*GlobalsSnapshot, error) {
+	values, err := c.globalValues()
+	if err != nil {
+		return nil, err
+	}
+	return &GlobalsSnapshot{ctx: c, values: values}, nil
+}
+
+// Diff compares the current globals of the context with the snapshot.
+func (s *GlobalsSnapshot) Diff() (GlobalsDiff, error) {
+	var d GlobalsDiff
+	values, err := s.ctx.globalValues()
+	if err != nil {
+		return d, err
+	}
+	for name, val := range values {
+		old, ok := s.values[name]
+		if !ok {
+			d.Added = append(d.Added, name)
+		} else if !val.SameValue(old) {
+			d.Modified = append(d.Modified, name)
+		}
+		val.Release()
+	}
+	for name := range s.values {
+		if _, ok := values[name]; !ok {
+			d.Removed = append(d.Removed, name)
+		}
+	}
+	sort.Strings(d.Added)
+	sort.Strings(d.Removed)
+	sort.Strings(d.Modified)
+	return d, nil
+}
+
+// Release releases the values recorded in the snapshot, after which it can't
+// be used.
+func (s *GlobalsSnapshot) Release() {
+	for _, val := range s.values {
+		val.Release()
+	}
+	s.values = nil
+}
+
+func (c *Context) globalValues() (map[string]*Value, error) {
+	global := c.Global()
+	defer global.Release()
+	names, err := global.GetOwnPropertyNames()
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]*Value, len(names))
+	for _, name := range names {
+		val, err := global.Get(name)
+		if err != nil {
+			for _, v := range values {
+				v.Release()
+			}
+			return nil, err
+		}
+		values[name] = val
+	}
+	return values, nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"reflect"
+	"testing"
+
+	v8 "github.com/lizc2003/v8go"
+)
+
+func TestContextGlobalNames(t *testing.T) {
+	t.Parallel()
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	names, err := ctx.GlobalNames()
+	fatalIf(t, err)
+	if len(names) != 0 {
+		t.Errorf("expected no enumerable globals in a new context, got %v", names)
+	}
+
+	_, err = ctx.RunScript(`var b = 1; function a() {} c = 2; let hidden = 3;`, "")
+	fatalIf(t, err)
+	names, err = ctx.GlobalNames()
+	fatalIf(t, err)
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("got %v, want %v", names, want)
+	}
+}
+
+func TestContextSnapshotGlobals(t *testing.T) {
+	t.Parallel()
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	_, err := ctx.RunScript(`var config = { debug: false }; var count = 1; gone = true;`, "")
+	fatalIf(t, err)
+	snap, err := ctx.SnapshotGlobals()
+	fatalIf(t, err)
+	defer snap.Release()
+
+	diff, err := snap.Diff()
+	fatalIf(t, err)
+	if !diff.Empty() {
+		t.Errorf("expected no changes, got %+v", diff)
+	}
+
+	_, err = ctx.RunScript(`config.debug = true; count = 2; delete globalThis.gone; leaked = 1; var added;`, "")
+	fatalIf(t, err)
+	diff, err = snap.Diff()
+	fatalIf(t, err)
+	want := v8.GlobalsDiff{
+		Added:    []string{"added", "leaked"},
+		Removed:  []string{"gone"},
+		Modified: []string{"count"},
+	}
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("got %+v, want %+v", diff, want)
+	}
+}
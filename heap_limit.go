@@ -0,0 +1,106 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+// #include "isolate.h"
+import "C"
+import (
+	"fmt"
+	"strings"
+)
+
+// NearHeapLimitCallback is called when the JS heap of an isolate is close to
+// its current limit, in bytes, and returns the new limit, and whether to
+// terminate the script that is allocating. initialLimit is the limit the
+// isolate was created with, e.g. IsolateOptions.MaxHeapSize.
+//
+// V8 crashes the process with an out of memory FatalError if the heap still
+// exceeds the returned limit after collecting garbage, so when terminating,
+// the limit must be raised enough for the script to unwind. The call that ran
+// the script, e.g. Context.RunScript, then returns a *HeapLimitError.
+//
+// The callback runs on the isolate's thread in the middle of a garbage
+// collection, so it must not run JS or create values.
+type NearHeapLimitCallback func(iso *Isolate, currentLimit, initialLimit uint64) (newLimit uint64, terminate bool)
+
+// HeapLimitError is returned by a call running JS, e.g. Context.RunScript,
+// when execution was terminated by a NearHeapLimitCallback.
+type HeapLimitError struct {
+	// Limit is the heap limit, in bytes, that the heap was close to.
+	Limit uint64
+	// Err is the termination error returned by V8.
+	Err error
+}
+
+func (e *HeapLimitError) Error() string {
+	return fmt.Sprintf("v8go: execution terminated near the heap limit of %d bytes", e.Limit)
+}
+
+func (e *HeapLimitError) Unwrap() error {
+	return e.Err
+}
+
+// TerminateOnHeapLimit returns a NearHeapLimitCallback that terminates
+// execution and raises the limit by headroom bytes for the script to unwind,
+// enforcing a per-isolate memory quota without crashing the process. A
+// headroom of zero raises the limit by half.
+func TerminateOnHeapLimit(headroom uint64) NearHeapLimitCallback {
+	return func(iso *Isolate, currentLimit, initialLimit uint64) (uint64, bool) {
+		if headroom == 0 {
+			return currentLimit + currentLimit/2, true
+		}
+		return currentLimit + headroom, true
+	}
+}
+
+// nearHeapLimitFunc is how a NearHeapLimitCallback is held in isoCbRegistry.
+type nearHeapLimitFunc func(currentLimit, initialLimit uint64) uint64
+
+func (i *Isolate) setNearHeapLimitCallback(cb NearHeapLimitCallback) {
+	ref := i.registerIsolateCallback(nearHeapLimitFunc(func(currentLimit, initialLimit uint64) uint64 {
+		limit, terminate := cb(i, currentLimit, initialLimit)
+		if terminate {
+			i.TerminateExecution()
+			if i.heapLimitErr == nil {
+				i.heapLimitErr = &HeapLimitError{Limit: currentLimit}
+			}
+		}
+		return limit
+	}))
+	C.IsolateAddNearHeapLimitCallback(i.ptr, C.int(ref))
+}
+
+// takeHeapLimitError returns err as a *HeapLimitError if execution was
+// terminated by the NearHeapLimitCallback, and err otherwise. The callback
+// may also run for a garbage collection outside of any script, whose
+// termination only takes effect in the next script, so an error other than
+// a termination leaves the *HeapLimitError for that script.
+func (i *Isolate) takeHeapLimitError(err error) error {
+	herr := i.heapLimitErr
+	if herr == nil || !isTermination(err) {
+		return err
+	}
+	i.heapLimitErr = nil
+	herr.Err = err
+	return herr
+}
+
+// isTermination reports whether err is the error of a call whose execution
+// was terminated.
+func isTermination(err error) bool {
+	jsErr, ok := err.(*JSError)
+	return ok && strings.HasPrefix(jsErr.Message, "ExecutionTerminated:")
+}
+
+//export goNearHeapLimitCallback
+func goNearHeapLimitCallback(ref int, currentLimit, initialLimit C.size_t) C.size_t {
+	isoCbMutex.Lock()
+	cb, _ := isoCbRegistry[ref].(nearHeapLimitFunc)
+	isoCbMutex.Unlock()
+	if cb == nil {
+		return currentLimit
+	}
+	return C.size_t(cb(uint64(currentLimit), uint64(initialLimit)))
+}
@@ -3,6 +3,7 @@
 #include "_cgo_export.h"
 #include "context-macros.h"
 #include "inspector.h"
+#include "isolate-macros.h"
 
 using namespace v8;
 using namespace v8_inspector;
@@ -28,6 +29,27 @@ class InspectorClient : public V8InspectorClient {
                          V8StackTrace*) override;
 };
 
+/**
+ * InspectorChannel receives the protocol messages of an inspector session,
+ * and passes them on to the Go InspectorSession identified by a cgo handle.
+ */
+class InspectorChannel : public V8Inspector::Channel {
+  uintptr_t _cgoHandle;
+
+ public:
+  InspectorChannel(uintptr_t cgoHandle) { _cgoHandle = cgoHandle; }
+  void sendResponse(int callId,
+                    std::unique_ptr<StringBuffer> message) override;
+  void sendNotification(std::unique_ptr<StringBuffer> message) override;
+  void flushProtocolNotifications() override {}
+};
+
+struct m_session {
+  v8::Isolate* iso;
+  InspectorChannel* channel;
+  std::unique_ptr<V8InspectorSession> session;
+};
+
 StringViewData ConvertStringView(const StringView& view) {
   StringViewData msg;
   msg.is8bit = view.is8Bit();
@@ -51,6 +73,17 @@ void InspectorClient::consoleAPIMessage(int contextGroupId,
       ConvertStringView(url), lineNumber, columnNumber);
 }
 
+void InspectorChannel::sendResponse(int callId,
+                                    std::unique_ptr<StringBuffer> message) {
+  goHandleInspectorMessage(_cgoHandle, callId,
+                           ConvertStringView(message->string()));
+}
+
+void InspectorChannel::sendNotification(
+    std::unique_ptr<StringBuffer> message) {
+  goHandleInspectorMessage(_cgoHandle, 0, ConvertStringView(message->string()));
+}
+
 extern "C" {
 
 v8Inspector* CreateInspector(v8Isolate* iso, v8InspectorClient* client) {
@@ -83,4 +116,37 @@ void InspectorContextDestroyed(v8Inspector* inspector, ContextPtr context) {
 void DeleteInspectorClient(v8InspectorClient* client) {
   delete client;
 }
+
+/********** InspectorSession **********/
+
+v8InspectorSession* InspectorConnect(v8Inspector* inspector,
+                                     v8Isolate* iso,
+                                     uintptr_t cgoHandle) {
+  ISOLATE_SCOPE(iso);
+  m_session* s = new m_session;
+  s->iso = iso;
+  s->channel = new InspectorChannel(cgoHandle);
+  int groupId = 1;
+  s->session = inspector->connect(groupId, s->channel, StringView(),
+                                  V8Inspector::kFullyTrusted);
+  return s;
+}
+
+void InspectorSessionDispatch(v8InspectorSession* s,
+                              const char* message,
+                              int length) {
+  ISOLATE_SCOPE(s->iso);
+  // 8-bit messages are parsed as UTF-8 JSON.
+  s->session->dispatchProtocolMessage(
+      StringView(reinterpret_cast<const uint8_t*>(message), length));
+}
+
+void DeleteInspectorSession(v8InspectorSession* s) {
+  {
+    ISOLATE_SCOPE(s->iso);
+    s->session.reset();
+  }
+  delete s->channel;
+  delete s;
+}
 }
@@ -1,11 +1,17 @@
 package v8go
 
+// #include <stdlib.h>
 // #include "inspector.h"
 import "C"
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"runtime/cgo"
 	"strconv"
+	"sync"
 	"unicode/utf16"
+	"unsafe"
 )
 
 // Represents the level of console output from JavaScript. E.g., `console.log`,
@@ -54,6 +60,7 @@ const (
 // See also: https://v8.github.io/api/head/classv8__inspector_1_1V8Inspector.html
 type Inspector struct {
 	ptr *C.v8Inspector
+	iso *Isolate
 }
 
 // An InspectorClient is the bridge from the [Inspector] to your code.
@@ -94,6 +101,7 @@ func NewInspector(iso *Isolate, client *InspectorClient) *Inspector {
 	ptr := C.CreateInspector(iso.ptr, client.ptr)
 	return &Inspector{
 		ptr: ptr,
+		iso: iso,
 	}
 }
 
@@ -172,3 +180,244 @@ func goHandleConsoleAPIMessageCallback(
 		})
 	}
 }
+
+// An InspectorSession speaks the Chrome DevTools protocol with the [Inspector],
+// like a DevTools frontend does, so domains such as Runtime, Debugger,
+// Profiler and HeapProfiler can be used from Go, e.g. to collect coverage with
+// StartPreciseCoverage.
+//
+// Pausing in the debugger is not supported, as it requires running a nested
+// message loop. Dispose the session before the [Inspector].
+//
+// See also: https://chromedevtools.github.io/devtools-protocol/v8/
+type InspectorSession struct {
+	ptr    *C.v8InspectorSession
+	handle cgo.Handle
+
+	mu        sync.Mutex
+	seq       int
+	responses map[int]json.RawMessage
+	subs      map[string][]*inspectorSubscriber
+}
+
+// InspectorEvent is a notification sent by the [Inspector], such as
+// Runtime.consoleAPICalled.
+type InspectorEvent struct {
+	// Method is the name of the event, e.g. "Runtime.consoleAPICalled".
+	Method string
+	// Params are the JSON encoded parameters of the event.
+	Params json.RawMessage
+}
+
+// Decode unmarshals the parameters of the event into v, typically a struct
+// describing the event.
+func (e InspectorEvent) Decode(v interface{}) error {
+	return json.Unmarshal(e.Params, v)
+}
+
+// InspectorError is the error returned by the [Inspector] for a failed
+// command, e.g. for a method that doesn't exist.
+type InspectorError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *InspectorError) Error() string {
+	return fmt.Sprintf("inspector: %s (%d)", e.Message, e.Code)
+}
+
+// Connect opens a new [InspectorSession] to the inspector, which sees the
+// contexts registered with [Inspector.ContextCreated].
+func (i *Inspector) Connect() *InspectorSession {
+	s := &InspectorSession{
+		responses: make(map[int]json.RawMessage),
+		subs:      make(map[string][]*inspectorSubscriber),
+	}
+	s.handle = cgo.NewHandle(s)
+	s.ptr = C.InspectorConnect(i.ptr, i.iso.ptr, C.uintptr_t(s.handle))
+	return s
+}
+
+// Send calls the protocol method with the given params, which are encoded as
+// JSON and may be nil, and returns the JSON encoded result. The [Inspector]
+// handles the command synchronously; methods that only respond later, such as
+// Runtime.awaitPromise, are not supported.
+func (s *InspectorSession) Send(method string, params interface{}) (json.RawMessage, error) {
+	if s.ptr == nil {
+		return nil, errors.New("v8go: InspectorSession has been disposed")
+	}
+	s.mu.Lock()
+	s.seq++
+	id := s.seq
+	s.mu.Unlock()
+
+	msg := struct {
+		ID     int         `json:"id"`
+		Method string      `json:"method"`
+		Params interface{} `json:"params,omitempty"`
+	}{id, method, params}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	cdata := C.CString(string(data))
+	defer C.free(unsafe.Pointer(cdata))
+	C.InspectorSessionDispatch(s.ptr, cdata, C.int(len(data)))
+
+	s.mu.Lock()
+	rtn, ok := s.responses[id]
+	delete(s.responses, id)
+	s.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("inspector: no response to %s", method)
+	}
+	var resp struct {
+		Result json.RawMessage `json:"result"`
+		Error  *InspectorError `json:"error"`
+	}
+	if err := json.Unmarshal(rtn, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	return resp.Result, nil
+}
+
+// EnableDomain enables the domain name, e.g. "Runtime" or "Profiler", by
+// calling its enable method, so the [Inspector] starts sending its events.
+func (s *InspectorSession) EnableDomain(name string) error {
+	_, err := s.Send(name+".enable", nil)
+	return err
+}
+
+// DisableDomain disables a domain enabled with EnableDomain.
+func (s *InspectorSession) DisableDomain(name string) error {
+	_, err := s.Send(name+".disable", nil)
+	return err
+}
+
+// Subscribe returns a channel that receives the events named method, e.g.
+// "Runtime.consoleAPICalled", in the order they are sent. Events are queued
+// without limit until they are received, so the channel never blocks the
+// script that triggers them. The channel is closed by Dispose; events not
+// received by then are dropped. After Dispose, the channel returned is
+// already closed.
+func (s *InspectorSession) Subscribe(method string) <-chan InspectorEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.subs == nil {
+		out := make(chan InspectorEvent)
+		close(out)
+		return out
+	}
+	sub := newInspectorSubscriber()
+	s.subs[method] = append(s.subs[method], sub)
+	return sub.out
+}
+
+// Dispose disconnects the session and closes the channels returned by
+// Subscribe. Calling Dispose more than once is a no-op.
+func (s *InspectorSession) Dispose() {
+	if s.ptr == nil {
+		return
+	}
+	C.DeleteInspectorSession(s.ptr)
+	s.ptr = nil
+	s.handle.Delete()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, subs := range s.subs {
+		for _, sub := range subs {
+			sub.close()
+		}
+	}
+	s.subs = nil
+}
+
+func (s *InspectorSession) handleMessage(callID int, msg []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if callID != 0 {
+		s.responses[callID] = msg
+		return
+	}
+	var event InspectorEvent
+	if err := json.Unmarshal(msg, &event); err != nil {
+		return
+	}
+	for _, sub := range s.subs[event.Method] {
+		sub.push(event)
+	}
+}
+
+// inspectorSubscriber queues events for a channel returned by Subscribe.
+type inspectorSubscriber struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	queue  []InspectorEvent
+	closed bool
+	out    chan InspectorEvent
+	done   chan struct{}
+}
+
+func newInspectorSubscriber() *inspectorSubscriber {
+	sub := &inspectorSubscriber{
+		out:  make(chan InspectorEvent),
+		done: make(chan struct{}),
+	}
+	sub.cond = sync.NewCond(&sub.mu)
+	go sub.forward()
+	return sub
+}
+
+func (sub *inspectorSubscriber) push(event InspectorEvent) {
+	sub.mu.Lock()
+	sub.queue = append(sub.queue, event)
+	sub.mu.Unlock()
+	sub.cond.Signal()
+}
+
+func (sub *inspectorSubscriber) close() {
+	sub.mu.Lock()
+	sub.closed = true
+	sub.queue = nil
+	sub.mu.Unlock()
+	close(sub.done)
+	sub.cond.Signal()
+}
+
+// forward sends the queued events to out until the subscriber is closed.
+func (sub *inspectorSubscriber) forward() {
+	defer close(sub.out)
+	for {
+		sub.mu.Lock()
+		for len(sub.queue) == 0 && !sub.closed {
+			sub.cond.Wait()
+		}
+		if sub.closed {
+			sub.mu.Unlock()
+			return
+		}
+		event := sub.queue[0]
+		sub.queue = sub.queue[1:]
+		sub.mu.Unlock()
+		select {
+		case sub.out <- event:
+		case <-sub.done:
+			return
+		}
+	}
+}
+
+// goHandleInspectorMessage is called by C code when the [Inspector] sends a
+// response, with the id of the command, or a notification, with a callID of
+// 0, to an [InspectorSession] identified by the cgoHandle.
+//
+//export goHandleInspectorMessage
+func goHandleInspectorMessage(cgoHandle C.uintptr_t, callID C.int, message C.StringViewData) {
+	handle := cgo.Handle(cgoHandle)
+	if s, ok := handle.Value().(*InspectorSession); ok {
+		s.handleMessage(int(callID), []byte(stringViewToString(message)))
+	}
+}
@@ -15,11 +15,13 @@ class V8InspectorClient;
 typedef v8::Isolate v8Isolate;
 typedef v8_inspector::V8Inspector v8Inspector;
 typedef v8_inspector::V8InspectorClient v8InspectorClient;
+typedef struct m_session v8InspectorSession;
 
 extern "C" {
 #else
 typedef struct v8Inspector v8Inspector;
 typedef struct v8InspectorClient v8InspectorClient;
+typedef struct v8InspectorSession v8InspectorSession;
 
 typedef struct v8Isolate v8Isolate;
 
@@ -40,6 +42,14 @@ extern void InspectorContextDestroyed(v8Inspector* inspector, m_ctx* context);
 extern v8InspectorClient* NewInspectorClient(uintptr_t callbackRef);
 extern void DeleteInspectorClient(v8InspectorClient* client);
 
+extern v8InspectorSession* InspectorConnect(v8Inspector* inspector,
+                                            v8Isolate* iso,
+                                            uintptr_t callbackRef);
+extern void InspectorSessionDispatch(v8InspectorSession* session,
+                                     const char* message,
+                                     int length);
+extern void DeleteInspectorSession(v8InspectorSession* session);
+
 typedef struct StringViewData {
   bool is8bit;
   void const* data;
@@ -1,8 +1,11 @@
 package v8go_test
 
 import (
+	"encoding/json"
+	"errors"
 	"reflect"
 	"testing"
+	"time"
 
 	v8 "github.com/lizc2003/v8go"
 )
@@ -128,3 +131,64 @@ func TestMonitorConsoleLogWideCharacters(t *testing.T) {
 		t.Fatalf("Unexpected messages. \nExpected: %v\nGot: %v", expected, actual)
 	}
 }
+
+func TestInspectorSession(t *testing.T) {
+	t.Parallel()
+	iso := NewIsolateWithInspectorClient(&consoleAPIMessageRecorder{})
+	defer iso.Dispose()
+	context := iso.NewContext()
+	defer context.Dispose()
+	session := iso.inspector.Connect()
+	defer session.Dispose()
+
+	fatalIf(t, session.EnableDomain("Runtime"))
+	events := session.Subscribe("Runtime.consoleAPICalled")
+	_, err := context.RunScript(`console.warn("careful", 42)`, "")
+	fatalIf(t, err)
+
+	select {
+	case event := <-events:
+		var called struct {
+			Type string `json:"type"`
+			Args []struct {
+				Value interface{} `json:"value"`
+			} `json:"args"`
+		}
+		fatalIf(t, event.Decode(&called))
+		if called.Type != "warning" || len(called.Args) != 2 || called.Args[0].Value != "careful" {
+			t.Errorf("unexpected event %s: %s", event.Method, event.Params)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for Runtime.consoleAPICalled")
+	}
+
+	result, err := session.Send("Runtime.evaluate", map[string]interface{}{"expression": "6 * 7"})
+	fatalIf(t, err)
+	var evaluated struct {
+		Result struct {
+			Value int `json:"value"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(result, &evaluated); err != nil || evaluated.Result.Value != 42 {
+		t.Errorf("unexpected Runtime.evaluate result %s: %v", result, err)
+	}
+
+	_, err = session.Send("Nope.missing", nil)
+	var inspectorErr *v8.InspectorError
+	if !errors.As(err, &inspectorErr) {
+		t.Errorf("expected an InspectorError for an unknown method, got %v", err)
+	}
+
+	// Disposing is idempotent, and the deferred Dispose is a no-op.
+	session.Dispose()
+	session.Dispose()
+	if _, ok := <-events; ok {
+		t.Error("expected Dispose to close the event channel")
+	}
+	if _, err := session.Send("Runtime.evaluate", nil); err == nil {
+		t.Error("expected an error sending on a disposed session")
+	}
+	if _, ok := <-session.Subscribe("Runtime.consoleAPICalled"); ok {
+		t.Error("expected a closed channel subscribing to a disposed session")
+	}
+}
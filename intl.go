@@ -0,0 +1,47 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrIntlUnavailable is returned by RequireIntl when V8 was built without
+// ICU, so scripts have no `Intl` object.
+var ErrIntlUnavailable = errors.New("v8go: Intl is not available, V8 was built without ICU support")
+
+var (
+	intlOnce      sync.Once
+	intlAvailable bool
+)
+
+// HasIntl reports whether scripts can use the `Intl` APIs, such as
+// Intl.NumberFormat and Intl.DateTimeFormat, i.e. whether V8 was built with
+// ICU. The V8 libraries bundled with v8go embed the full ICU data, so no
+// setup is needed for them; a custom build may leave ICU out. The check runs
+// a script in a throwaway isolate the first time it is called.
+func HasIntl() bool {
+	intlOnce.Do(func() {
+		iso := NewIsolate()
+		defer iso.Dispose()
+		ctx := NewContext(iso)
+		defer ctx.Close()
+		val, err := ctx.RunScript(
+			`typeof Intl === "object" && typeof Intl.NumberFormat === "function"`, "")
+		intlAvailable = err == nil && val.Boolean()
+	})
+	return intlAvailable
+}
+
+// RequireIntl returns ErrIntlUnavailable if HasIntl is false, so that a
+// program whose scripts need `Intl` can fail at startup with a clear error,
+// rather than with a ReferenceError when a script first uses it.
+func RequireIntl() error {
+	if !HasIntl() {
+		return ErrIntlUnavailable
+	}
+	return nil
+}
@@ -13,6 +13,11 @@ import (
 func TestIntlSupport(t *testing.T) {
 	t.Parallel()
 
+	if !v8.HasIntl() {
+		t.Fatal("expected the bundled V8 to have Intl support")
+	}
+	fatalIf(t, v8.RequireIntl())
+
 	ctx := v8.NewContext(nil)
 	iso := ctx.Isolate()
 	defer iso.Dispose()
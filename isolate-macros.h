@@ -12,8 +12,28 @@ static inline m_ctx* isolateInternalContext(v8::Isolate* iso) {
   return static_cast<m_ctx*>(iso->GetData(0));
 }
 
+// IsolateLocker locks the isolate like v8::Locker. When the current thread
+// enters the isolate from Go, rather than from a callback, it also sets the
+// stack limit configured for the isolate relative to the current position.
+// This has to be done on each entry, as Go may run the isolate on any thread.
+class IsolateLocker {
+ public:
+  explicit IsolateLocker(v8::Isolate* iso)
+      : outermost_(!v8::Locker::IsLocked(iso)), locker_(iso) {
+    m_ctx* ctx = isolateInternalContext(iso);
+    if (outermost_ && ctx != nullptr && ctx->stack_limit_kb > 0) {
+      uintptr_t here = reinterpret_cast<uintptr_t>(&ctx);
+      iso->SetStackLimit(here - ctx->stack_limit_kb * 1024);
+    }
+  }
+
+ private:
+  bool outermost_;
+  v8::Locker locker_;
+};
+
 #define ISOLATE_SCOPE(iso)           \
-  Locker locker(iso);                \
+  IsolateLocker locker(iso);         \
   Isolate::Scope isolate_scope(iso); \
   HandleScope handle_scope(iso);
 
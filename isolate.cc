@@ -1,30 +1,82 @@
+#include "_cgo_export.h"
+
 #include "deps/include/v8-context.h"
 #include "deps/include/v8-initialization.h"
 #include "deps/include/v8-locker.h"
 #include "deps/include/v8-platform.h"
 
 #include "context.h"
+#include "isolate-macros.h"
 #include "isolate.h"
+#include "utils.h"
 #include "libplatform/libplatform.h"
 
+#include <atomic>
+#include <cstdio>
+#include <cstdlib>
+#include <map>
+#include <memory>
+#include <mutex>
+#include <string>
+
 using namespace v8;
 
-auto default_platform = platform::NewDefaultPlatform();
+std::unique_ptr<Platform> default_platform;
 ArrayBuffer::Allocator* default_allocator;
 
+// V8's stats callbacks don't say which isolate they are called for, and may
+// be called from background threads, so the stats of all isolates are kept
+// together.
+struct stats_histogram {
+  std::atomic<int64_t> count;
+  std::atomic<int64_t> sum;
+};
+
+static std::mutex stats_mutex;
+static std::map<std::string, std::unique_ptr<int>> stats_counters;
+static std::map<std::string, std::unique_ptr<stats_histogram>>
+    stats_histograms;
+
+static int* StatsLookupCounter(const char* name) {
+  std::lock_guard<std::mutex> lock(stats_mutex);
+  std::unique_ptr<int>& counter = stats_counters[name];
+  if (!counter) {
+    counter.reset(new int(0));
+  }
+  return counter.get();
+}
+
+static void* StatsCreateHistogram(const char* name,
+                                  int min,
+                                  int max,
+                                  size_t buckets) {
+  std::lock_guard<std::mutex> lock(stats_mutex);
+  std::unique_ptr<stats_histogram>& histogram = stats_histograms[name];
+  if (!histogram) {
+    histogram.reset(new stats_histogram{{0}, {0}});
+  }
+  return histogram.get();
+}
+
+static void StatsAddHistogramSample(void* histogram, int sample) {
+  stats_histogram* h = static_cast<stats_histogram*>(histogram);
+  h->count++;
+  h->sum += sample;
+}
+
 extern "C" {
 
 /********** Isolate **********/
 
-#define ISOLATE_SCOPE(iso)           \
-  Locker locker(iso);                \
-  Isolate::Scope isolate_scope(iso); \
-  HandleScope handle_scope(iso);
-
-void Init() {
+void Init(int thread_pool_size, int single_threaded) {
 #ifdef _WIN32
   V8::InitializeExternalStartupData(".");
 #endif
+  if (single_threaded) {
+    default_platform = platform::NewSingleThreadedDefaultPlatform();
+  } else {
+    default_platform = platform::NewDefaultPlatform(thread_pool_size);
+  }
   V8::InitializePlatform(default_platform.get());
   V8::Initialize();
 
@@ -32,15 +84,38 @@ void Init() {
   return;
 }
 
-IsolatePtr NewIsolate() {
+// V8 returns from a fatal error once its handler does, leaving the isolate
+// unusable, so the handlers report the error and abort the process as V8
+// does without one.
+static void FatalErrorHandler(const char* location, const char* message) {
+  goFatalError(const_cast<char*>(location), const_cast<char*>(message), 0, 0);
+  fprintf(stderr, "\n#\n# Fatal error in %s\n# %s\n#\n\n", location,
+          message);
+  abort();
+}
+
+static void OOMErrorHandler(const char* location, const OOMDetails& details) {
+  goFatalError(const_cast<char*>(location), const_cast<char*>(details.detail),
+               1, details.is_heap_oom);
+  fprintf(stderr, "\n#\n# Fatal %s out of memory: %s\n#\n\n",
+          details.is_heap_oom ? "JavaScript" : "process", location);
+  abort();
+}
+
+IsolatePtr NewIsolate(size_t max_heap_size) {
   Isolate::CreateParams params;
   params.array_buffer_allocator = default_allocator;
+  if (max_heap_size > 0) {
+    params.constraints.ConfigureDefaultsFromHeapSize(0, max_heap_size);
+  }
   Isolate* iso = Isolate::New(params);
-  Locker locker(iso);
+  IsolateLocker locker(iso);
   Isolate::Scope isolate_scope(iso);
   HandleScope handle_scope(iso);
 
   iso->SetCaptureStackTraceForUncaughtExceptions(true);
+  iso->SetFatalErrorHandler(FatalErrorHandler);
+  iso->SetOOMErrorHandler(OOMErrorHandler);
 
   // Create a Context for internal use
   m_ctx* ctx = new m_ctx;
@@ -54,6 +129,17 @@ IsolatePtr NewIsolate() {
 void IsolatePerformMicrotaskCheckpoint(IsolatePtr iso) {
   ISOLATE_SCOPE(iso)
   iso->PerformMicrotaskCheckpoint();
+  // Run pending foreground tasks, such as FinalizationRegistry cleanup, which
+  // V8 posts to the platform rather than the microtask queue.
+  while (platform::PumpMessageLoop(default_platform.get(), iso)) {
+  }
+}
+
+int IsolatePumpMessageLoop(IsolatePtr iso, int wait) {
+  ISOLATE_SCOPE(iso)
+  auto behavior = wait ? platform::MessageLoopBehavior::kWaitForWork
+                       : platform::MessageLoopBehavior::kDoNotWait;
+  return platform::PumpMessageLoop(default_platform.get(), iso, behavior);
 }
 
 void IsolateDispose(IsolatePtr iso) {
@@ -70,6 +156,49 @@ void IsolateTerminateExecution(IsolatePtr iso) {
   iso->TerminateExecution();
 }
 
+static void IsolateInterruptCallback(Isolate* iso, void* data) {
+  goInterruptCallback(static_cast<int>(reinterpret_cast<intptr_t>(data)));
+}
+
+void IsolateRequestInterrupt(IsolatePtr iso, int ref) {
+  iso->RequestInterrupt(IsolateInterruptCallback,
+                        reinterpret_cast<void*>(static_cast<intptr_t>(ref)));
+}
+
+static void IsolateMicrotasksCompletedCallback(Isolate* iso, void* data) {
+  goMicrotasksCompletedCallback(
+      static_cast<int>(reinterpret_cast<intptr_t>(data)));
+}
+
+static size_t IsolateNearHeapLimitCallback(void* data,
+                                           size_t current_heap_limit,
+                                           size_t initial_heap_limit) {
+  return goNearHeapLimitCallback(
+      static_cast<int>(reinterpret_cast<intptr_t>(data)), current_heap_limit,
+      initial_heap_limit);
+}
+
+void IsolateAddNearHeapLimitCallback(IsolatePtr iso, int ref) {
+  ISOLATE_SCOPE(iso)
+  iso->AddNearHeapLimitCallback(
+      IsolateNearHeapLimitCallback,
+      reinterpret_cast<void*>(static_cast<intptr_t>(ref)));
+}
+
+void IsolateAddMicrotasksCompletedCallback(IsolatePtr iso, int ref) {
+  ISOLATE_SCOPE(iso)
+  iso->AddMicrotasksCompletedCallback(
+      IsolateMicrotasksCompletedCallback,
+      reinterpret_cast<void*>(static_cast<intptr_t>(ref)));
+}
+
+void IsolateRemoveMicrotasksCompletedCallback(IsolatePtr iso, int ref) {
+  ISOLATE_SCOPE(iso)
+  iso->RemoveMicrotasksCompletedCallback(
+      IsolateMicrotasksCompletedCallback,
+      reinterpret_cast<void*>(static_cast<intptr_t>(ref)));
+}
+
 int IsolateIsExecutionTerminating(IsolatePtr iso) {
   return iso->IsExecutionTerminating();
 }
@@ -93,4 +222,78 @@ IsolateHStatistics IsolationGetHeapStatistics(IsolatePtr iso) {
                             hs.number_of_native_contexts(),
                             hs.number_of_detached_contexts()};
 }
+
+void IsolateMemoryPressureNotification(IsolatePtr iso, int level) {
+  // Safe to call from any thread; V8 schedules the GC work on the isolate's
+  // own thread if it is currently busy.
+  iso->MemoryPressureNotification(static_cast<MemoryPressureLevel>(level));
+}
+
+void IsolateLowMemoryNotification(IsolatePtr iso) {
+  ISOLATE_SCOPE(iso)
+  iso->LowMemoryNotification();
+}
+
+int64_t IsolateAdjustAmountOfExternalAllocatedMemory(IsolatePtr iso,
+                                                     int64_t change_in_bytes) {
+  ISOLATE_SCOPE(iso)
+  return iso->AdjustAmountOfExternalAllocatedMemory(change_in_bytes);
+}
+
+// Slot 0 holds the internal context, so embedder slots are offset by one.
+uint32_t IsolateNumberOfDataSlots() {
+  return Isolate::GetNumberOfDataSlots() - 1;
+}
+
+int IsolateSetData(IsolatePtr iso, uint32_t slot, void* data) {
+  if (slot >= IsolateNumberOfDataSlots()) {
+    return 0;
+  }
+  iso->SetData(slot + 1, data);
+  return 1;
+}
+
+void* IsolateGetData(IsolatePtr iso, uint32_t slot) {
+  if (slot >= IsolateNumberOfDataSlots()) {
+    return nullptr;
+  }
+  return iso->GetData(slot + 1);
+}
+
+/********** Stats **********/
+
+void IsolateEnableStats(IsolatePtr iso) {
+  iso->SetCounterFunction(StatsLookupCounter);
+  iso->SetCreateHistogramFunction(StatsCreateHistogram);
+  iso->SetAddHistogramSampleFunction(StatsAddHistogramSample);
+}
+
+RtnStats StatsGet(int reset) {
+  std::lock_guard<std::mutex> lock(stats_mutex);
+  RtnStats rtn = {};
+  int count = stats_counters.size() + stats_histograms.size() * 2;
+  if (count == 0) {
+    return rtn;
+  }
+  rtn.entries = (StatsEntry*)malloc(sizeof(StatsEntry) * count);
+  for (auto& it : stats_counters) {
+    rtn.entries[rtn.count++] = {CopyString(it.first), *it.second};
+    if (reset) {
+      *it.second = 0;
+    }
+  }
+  for (auto& it : stats_histograms) {
+    stats_histogram* h = it.second.get();
+    int64_t samples = reset ? h->count.exchange(0) : h->count.load();
+    int64_t sum = reset ? h->sum.exchange(0) : h->sum.load();
+    rtn.entries[rtn.count++] = {CopyString(it.first + ".count"), samples};
+    rtn.entries[rtn.count++] = {CopyString(it.first + ".sum"), sum};
+  }
+  return rtn;
+}
+
+void IsolateSetStackLimit(IsolatePtr iso, size_t limit_kb) {
+  INTERNAL_CONTEXT(iso);
+  ctx->stack_limit_kb = limit_kb;
+}
 }
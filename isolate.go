@@ -9,7 +9,11 @@ package v8go
 import "C"
 
 import (
+	"errors"
+	"fmt"
+	"sort"
 	"sync"
+	"time"
 	"unsafe"
 )
 
@@ -21,10 +25,76 @@ type Isolate struct {
 
 	cbMutex sync.RWMutex
 	cbSeq   int
+	cbFree  []int
 	cbs     map[int]FunctionCallbackWithError
+	acbs    map[int]accessCheck
 
 	null      *Value
 	undefined *Value
+
+	// predictable is set for isolates created with
+	// IsolateOptions.PredictableMode.
+	predictable *predictableMode
+
+	// readableStream is the class of the objects created by
+	// Context.NewReadableStream, created on first use.
+	readableStream *ObjectTemplate
+
+	// randomSource is the template of the functions installed by
+	// Context.SetRandomSource, created on first use.
+	randomSource *FunctionTemplate
+
+	// heapLimitErr is set when IsolateOptions.NearHeapLimit terminates
+	// execution, until a call returns the termination error.
+	heapLimitErr *HeapLimitError
+
+	// isoCbRefs are the references of the callbacks of the isolate in
+	// isoCbRegistry, to remove when it is disposed. It is guarded by
+	// isoCbMutex.
+	isoCbRefs map[int]struct{}
+
+	// abandoned holds the streamers that were garbage collected without being
+	// finished or aborted, to abort on the isolate's thread.
+	abandoned abandonedStreamers
+}
+
+// isoCbRegistry holds callbacks that V8 invokes with a reference as
+// opaque data, without a context to look them up in. Most are a func(); the
+// callbacks V8 passes arguments to have the signature their caller expects.
+var (
+	isoCbMutex    sync.Mutex
+	isoCbRegistry = make(map[int]interface{})
+	isoCbSeq      = 0
+)
+
+func (i *Isolate) registerIsolateCallback(cb interface{}) int {
+	isoCbMutex.Lock()
+	defer isoCbMutex.Unlock()
+	isoCbSeq++
+	isoCbRegistry[isoCbSeq] = cb
+	if i.isoCbRefs == nil {
+		i.isoCbRefs = make(map[int]struct{})
+	}
+	i.isoCbRefs[isoCbSeq] = struct{}{}
+	return isoCbSeq
+}
+
+func (i *Isolate) unregisterIsolateCallback(ref int) {
+	isoCbMutex.Lock()
+	defer isoCbMutex.Unlock()
+	delete(isoCbRegistry, ref)
+	delete(i.isoCbRefs, ref)
+}
+
+// unregisterIsolateCallbacks removes all the callbacks of the isolate, which
+// would otherwise keep it reachable from isoCbRegistry.
+func (i *Isolate) unregisterIsolateCallbacks() {
+	isoCbMutex.Lock()
+	defer isoCbMutex.Unlock()
+	for ref := range i.isoCbRefs {
+		delete(isoCbRegistry, ref)
+	}
+	i.isoCbRefs = nil
 }
 
 // HeapStatistics represents V8 isolate heap statistics
@@ -42,6 +112,16 @@ type HeapStatistics struct {
 	NumberOfDetachedContexts uint64
 }
 
+// MemoryPressureLevel is the level of memory pressure passed to
+// Isolate.MemoryPressureNotification.
+type MemoryPressureLevel int
+
+const (
+	MemoryPressureLevelNone MemoryPressureLevel = iota
+	MemoryPressureLevelModerate
+	MemoryPressureLevelCritical
+)
+
 // NewIsolate creates a new V8 isolate. Only one thread may access
 // a given isolate at a time, but different threads may access
 // different isolates simultaneously.
@@ -50,22 +130,169 @@ type HeapStatistics struct {
 // An *Isolate can be used as a v8go.ContextOption to create a new
 // Context, rather than creating a new default Isolate.
 func NewIsolate() *Isolate {
+	return newIsolate(0)
+}
+
+func newIsolate(maxHeapSize uint64) *Isolate {
 	initializeIfNecessary()
 	iso := &Isolate{
-		ptr: C.NewIsolate(),
-		cbs: make(map[int]FunctionCallbackWithError),
+		ptr:  C.NewIsolate(C.size_t(maxHeapSize)),
+		cbs:  make(map[int]FunctionCallbackWithError),
+		acbs: make(map[int]accessCheck),
 	}
 	iso.null = newValueNull(iso)
 	iso.undefined = newValueUndefined(iso)
 	return iso
 }
 
+// IsolateOptions configures an isolate created with NewIsolateWithOptions.
+type IsolateOptions struct {
+	// StackLimitKB is how much of the native stack, in KiB, JS may use before
+	// V8 throws a RangeError for exceeding the maximum call stack size. When
+	// zero, V8's default of slightly less than 1 MiB is used.
+	//
+	// V8 doesn't run on the goroutine stack but on the stack of the OS
+	// thread making the cgo call, which may be any thread Go runs the
+	// goroutine on. The limit must therefore leave room below the smallest
+	// thread stack of the process for the C and Go frames around V8: threads
+	// started by Go use the default pthread stack size, typically 8 MiB on
+	// Linux, while on macOS threads other than the main thread default to
+	// 512 KiB. The limit applies from where Go enters V8, so JS that calls Go
+	// callbacks that call back into JS shares one limit.
+	StackLimitKB int
+
+	// PredictableMode makes the output of scripts reproducible from run to
+	// run, e.g. for golden-file tests. In every context of the isolate,
+	// Math.random returns the same sequence, seeded with RandomSeed, and
+	// Date.now and `new Date()` read the time from Clock. Background threads
+	// are process-wide, so they are disabled with PlatformOptions.Predictable
+	// instead.
+	PredictableMode bool
+	// RandomSeed seeds Math.random in PredictableMode. Only the low 32 bits
+	// are used; when zero, a fixed default seed is used.
+	RandomSeed int64
+	// Clock returns the current time for Date in PredictableMode. When nil,
+	// the time is frozen at the Unix epoch.
+	Clock func() time.Time
+
+	// MaxHeapSize is the hard limit on the size of the JS heap, in bytes. When
+	// zero, V8 picks a limit based on the physical memory. Reaching the limit
+	// crashes the process with an out of memory FatalError, unless
+	// NearHeapLimit raises it.
+	MaxHeapSize uint64
+	// NearHeapLimit is called when the heap is close to its limit, and can
+	// terminate the script that is allocating instead of letting V8 crash,
+	// e.g. TerminateOnHeapLimit.
+	NearHeapLimit NearHeapLimitCallback
+}
+
+// NewIsolateWithOptions creates a new V8 isolate like NewIsolate, configured
+// with opts.
+func NewIsolateWithOptions(opts IsolateOptions) (*Isolate, error) {
+	if opts.StackLimitKB < 0 {
+		return nil, errors.New("v8go: stack limit must not be negative")
+	}
+	iso := newIsolate(opts.MaxHeapSize)
+	if opts.NearHeapLimit != nil {
+		iso.setNearHeapLimitCallback(opts.NearHeapLimit)
+	}
+	if opts.StackLimitKB > 0 {
+		C.IsolateSetStackLimit(iso.ptr, C.size_t(opts.StackLimitKB))
+	}
+	if opts.PredictableMode {
+		iso.predictable = newPredictableMode(iso, opts)
+	}
+	return iso, nil
+}
+
 // TerminateExecution terminates forcefully the current thread
 // of JavaScript execution in the given isolate.
 func (i *Isolate) TerminateExecution() {
 	C.IsolateTerminateExecution(i.ptr)
 }
 
+// RequestInterrupt requests that cb is called on the thread that is running
+// JavaScript in the isolate, at the next safe point of execution. It may be
+// called from any goroutine, and is the safe way to inspect or modify a busy
+// isolate; cb may use the isolate freely, but should return quickly as
+// JavaScript execution is paused while it runs.
+//
+// If the isolate does not execute any more JavaScript, cb is never called.
+func (i *Isolate) RequestInterrupt(cb func(*Isolate)) {
+	if cb == nil {
+		panic("nil interrupt callback not supported")
+	}
+	// The callback is removed from the registry when it runs.
+	var ref int
+	ref = i.registerIsolateCallback(func() {
+		i.unregisterIsolateCallback(ref)
+		cb(i)
+	})
+	C.IsolateRequestInterrupt(i.ptr, C.int(ref))
+}
+
+// PumpMessageLoop runs a single pending foreground task that V8 has posted to
+// the platform for the isolate, returning whether a task was run. If wait is
+// true and there is no pending task, it blocks until one is posted.
+//
+// V8 has no message loop of its own: tasks it posts for the isolate's thread
+// only run when the embedder pumps them. Applications that use APIs which
+// complete asynchronously, such as streaming compilation or compiling
+// WebAssembly in the background, must call PumpMessageLoop (or
+// RunAllForegroundTasks) from their event loop, otherwise the posted tasks
+// silently never run and those operations never complete. The same applies to
+// every task when V8 is initialized with PlatformOptions.SingleThreaded.
+// Context.PerformMicrotaskCheckpoint also runs the pending foreground tasks.
+func (i *Isolate) PumpMessageLoop(wait bool) bool {
+	var w int
+	if wait {
+		w = 1
+	}
+	return C.IsolatePumpMessageLoop(i.ptr, C.int(w)) == 1
+}
+
+// RunAllForegroundTasks runs pending foreground tasks for the isolate until
+// none are left, including any tasks posted by the tasks that ran, and returns
+// the number of tasks that were run. It does not wait for new tasks.
+func (i *Isolate) RunAllForegroundTasks() int {
+	n := 0
+	for i.PumpMessageLoop(false) {
+		n++
+	}
+	return n
+}
+
+// MicrotasksCompletedCallback identifies a callback added with
+// Isolate.AddMicrotasksCompletedCallback.
+type MicrotasksCompletedCallback struct {
+	ref int
+}
+
+// AddMicrotasksCompletedCallback adds a callback that is called every time the
+// microtask queue has been drained, either by Context.PerformMicrotaskCheckpoint
+// or automatically after a script has run. In an event loop this marks the end
+// of a tick. The callback may use the isolate, but any microtasks it enqueues
+// run at the next checkpoint.
+func (i *Isolate) AddMicrotasksCompletedCallback(cb func(*Isolate)) MicrotasksCompletedCallback {
+	if cb == nil {
+		panic("nil microtasks completed callback not supported")
+	}
+	ref := i.registerIsolateCallback(func() { cb(i) })
+	C.IsolateAddMicrotasksCompletedCallback(i.ptr, C.int(ref))
+	return MicrotasksCompletedCallback{ref}
+}
+
+// RemoveMicrotasksCompletedCallback removes a callback added with
+// AddMicrotasksCompletedCallback.
+func (i *Isolate) RemoveMicrotasksCompletedCallback(cb MicrotasksCompletedCallback) {
+	// Dispose has already removed the callbacks of the isolate.
+	if i.ptr == nil {
+		return
+	}
+	C.IsolateRemoveMicrotasksCompletedCallback(i.ptr, C.int(cb.ref))
+	i.unregisterIsolateCallback(cb.ref)
+}
+
 // IsExecutionTerminating returns whether V8 is currently terminating
 // Javascript execution. If true, there are still JavaScript frames
 // on the stack and the termination exception is still active.
@@ -77,24 +304,53 @@ type CompileOptions struct {
 	CachedData *CompilerCachedData
 
 	Mode CompileMode
+
+	// ProduceCompileHints records which functions are called while the script
+	// runs, so they can be retrieved with UnboundScript.CompileHints.
+	ProduceCompileHints bool
+	// CompileHints are positions from UnboundScript.CompileHints of the
+	// functions to compile eagerly, rather than on their first call.
+	CompileHints []int
 }
 
 // CompileUnboundScript will create an UnboundScript (i.e. context-indepdent)
 // using the provided source JavaScript, origin (a.k.a. filename), and options.
+// As for Context.RunScript, an empty origin is replaced with
+// AnonymousScriptOrigin.
 // If options contain a non-null CachedData, compilation of the script will use
-// that code cache.
+// that code cache. CachedData, Mode and the compile hint options are mutually
+// exclusive.
 // error will be of type `JSError` if not nil.
 func (i *Isolate) CompileUnboundScript(
 	source, origin string,
 	opts CompileOptions,
 ) (*UnboundScript, error) {
 	cSource := C.CString(source)
-	cOrigin := C.CString(origin)
+	cOrigin := C.CString(scriptOrigin(origin))
 	defer C.free(unsafe.Pointer(cSource))
 	defer C.free(unsafe.Pointer(cOrigin))
 
 	var cOptions C.CompileOptions
-	if opts.CachedData != nil {
+	if opts.ProduceCompileHints || len(opts.CompileHints) > 0 {
+		if opts.Mode != 0 || opts.CachedData != nil {
+			panic("On CompileOptions, compile hints can't be combined with Mode or CachedData")
+		}
+		if opts.ProduceCompileHints && len(opts.CompileHints) > 0 {
+			panic("On CompileOptions, ProduceCompileHints and CompileHints can't both be set")
+		}
+	}
+	if opts.ProduceCompileHints {
+		cOptions.compileOption = C.ScriptCompilerProduceCompileHints
+	} else if len(opts.CompileHints) > 0 {
+		hints := make([]C.int, len(opts.CompileHints))
+		for i, pos := range opts.CompileHints {
+			hints[i] = C.int(pos)
+		}
+		sort.Slice(hints, func(i, j int) bool { return hints[i] < hints[j] })
+		cOptions.compileOption = C.ScriptCompilerConsumeCompileHints
+		cOptions.compileHints = &hints[0]
+		cOptions.compileHintsCount = C.int(len(hints))
+	} else if opts.CachedData != nil {
 		if opts.Mode != 0 {
 			panic("On CompileOptions, Mode and CachedData can't both be set")
 		}
@@ -109,7 +365,7 @@ func (i *Isolate) CompileUnboundScript(
 
 	rtn := C.IsolateCompileUnboundScript(i.ptr, cSource, cOrigin, cOptions)
 	if rtn.ptr == nil {
-		return nil, newJSError(rtn.error)
+		return nil, i.takeHeapLimitError(newJSError(rtn.error))
 	}
 	if opts.CachedData != nil {
 		opts.CachedData.Rejected = int(rtn.cachedDataRejected) == 1
@@ -139,13 +395,67 @@ func (i *Isolate) GetHeapStatistics() HeapStatistics {
 	}
 }
 
+// MemoryPressureNotification tells V8 how much memory pressure the embedder
+// is under, so it can adjust its garbage collection schedule. Use this
+// during idle periods, e.g. between requests, to give V8 a chance to collect
+// garbage before the next burst of work. It may be called from any goroutine.
+//
+// This replaces Isolate::IdleNotificationDeadline, which is no longer
+// available in V8.
+func (i *Isolate) MemoryPressureNotification(level MemoryPressureLevel) {
+	C.IsolateMemoryPressureNotification(i.ptr, C.int(level))
+}
+
+// LowMemoryNotification performs a full, blocking garbage collection,
+// reclaiming as much memory as possible.
+func (i *Isolate) LowMemoryNotification() {
+	C.IsolateLowMemoryNotification(i.ptr)
+}
+
+// AdjustAmountOfExternalAllocatedMemory tells V8 how much memory is being kept
+// alive by JavaScript objects but allocated outside of the V8 heap, e.g. Go
+// buffers referenced from JS. V8 uses this to decide when to trigger a global
+// garbage collection. A positive change in bytes registers an allocation, a
+// negative change registers a release. Returns the adjusted value.
+func (i *Isolate) AdjustAmountOfExternalAllocatedMemory(changeInBytes int64) int64 {
+	return int64(C.IsolateAdjustAmountOfExternalAllocatedMemory(i.ptr, C.int64_t(changeInBytes)))
+}
+
+// NumberOfDataSlots returns the number of embedder data slots available to
+// Isolate.SetData.
+func NumberOfDataSlots() uint32 {
+	return uint32(C.IsolateNumberOfDataSlots())
+}
+
+// SetData associates ptr with the isolate in the given embedder data slot, so
+// it can be retrieved with GetData wherever only the isolate is at hand, e.g.
+// from native callbacks. An error is returned if slot is not less than
+// NumberOfDataSlots. One slot is used by v8go and is not counted.
+//
+// ptr is kept by V8, so under the cgo pointer passing rules it must not point
+// to Go memory; use memory allocated in C, e.g. with C.malloc, instead.
+func (i *Isolate) SetData(slot uint32, ptr unsafe.Pointer) error {
+	if C.IsolateSetData(i.ptr, C.uint32_t(slot), ptr) == 0 {
+		return fmt.Errorf("v8go: data slot %d out of range, must be less than %d", slot, NumberOfDataSlots())
+	}
+	return nil
+}
+
+// GetData returns the pointer stored in the given embedder data slot with
+// SetData, or nil if none was stored or slot is out of range.
+func (i *Isolate) GetData(slot uint32) unsafe.Pointer {
+	return C.IsolateGetData(i.ptr, C.uint32_t(slot))
+}
+
 // Dispose will dispose the Isolate VM; subsequent calls will panic.
 func (i *Isolate) Dispose() {
 	if i.ptr == nil {
 		return
 	}
+	i.abandoned.close()
 	C.IsolateDispose(i.ptr)
 	i.ptr = nil
+	i.unregisterIsolateCallbacks()
 }
 
 // ThrowException schedules an exception to be thrown when returning to
@@ -157,7 +467,7 @@ func (i *Isolate) ThrowException(value *Value) *Value {
 		panic("Isolate has been disposed")
 	}
 	return &Value{
-		ptr: C.IsolateThrowException(i.ptr, value.ptr),
+		ptr: C.IsolateThrowException(i.ptr, value.valuePtr()),
 	}
 }
 
@@ -172,15 +482,72 @@ func (i *Isolate) apply(opts *contextOptions) {
 
 func (i *Isolate) registerCallback(cb FunctionCallbackWithError) int {
 	i.cbMutex.Lock()
-	i.cbSeq++
-	ref := i.cbSeq
+	ref := i.nextCallbackRef()
 	i.cbs[ref] = cb
 	i.cbMutex.Unlock()
 	return ref
 }
 
+// nextCallbackRef returns a ref freed by unregisterCallback if there is one,
+// so that the refs stay dense. i.cbMutex must be held.
+func (i *Isolate) nextCallbackRef() int {
+	if n := len(i.cbFree); n > 0 {
+		ref := i.cbFree[n-1]
+		i.cbFree = i.cbFree[:n-1]
+		return ref
+	}
+	i.cbSeq++
+	return i.cbSeq
+}
+
+// unregisterCallback releases a callback registered with registerCallback or
+// registerAccessCheck, and makes its ref available for reuse. It must only be
+// called once nothing in V8 can invoke the callback anymore.
+func (i *Isolate) unregisterCallback(ref int) {
+	i.cbMutex.Lock()
+	delete(i.cbs, ref)
+	delete(i.acbs, ref)
+	i.cbFree = append(i.cbFree, ref)
+	i.cbMutex.Unlock()
+}
+
 func (i *Isolate) getCallback(ref int) FunctionCallbackWithError {
 	i.cbMutex.RLock()
 	defer i.cbMutex.RUnlock()
 	return i.cbs[ref]
 }
+
+func (i *Isolate) registerAccessCheck(ac accessCheck) int {
+	i.cbMutex.Lock()
+	ref := i.nextCallbackRef()
+	i.acbs[ref] = ac
+	i.cbMutex.Unlock()
+	return ref
+}
+
+func (i *Isolate) getAccessCheck(ref int) accessCheck {
+	i.cbMutex.RLock()
+	defer i.cbMutex.RUnlock()
+	return i.acbs[ref]
+}
+
+//export goInterruptCallback
+func goInterruptCallback(ref int) {
+	isoCbMutex.Lock()
+	cb, _ := isoCbRegistry[ref].(func())
+	delete(isoCbRegistry, ref)
+	isoCbMutex.Unlock()
+	if cb != nil {
+		cb()
+	}
+}
+
+//export goMicrotasksCompletedCallback
+func goMicrotasksCompletedCallback(ref int) {
+	isoCbMutex.Lock()
+	cb, _ := isoCbRegistry[ref].(func())
+	isoCbMutex.Unlock()
+	if cb != nil {
+		cb()
+	}
+}
@@ -24,10 +24,16 @@ typedef m_value* ValuePtr;
 extern const int ScriptCompilerNoCompileOptions;
 extern const int ScriptCompilerConsumeCodeCache;
 extern const int ScriptCompilerEagerCompile;
+extern const int ScriptCompilerProduceCompileHints;
+extern const int ScriptCompilerConsumeCompileHints;
 
 typedef struct {
   ScriptCompilerCachedData cachedData;
   int compileOption;
+  // The sorted positions of the functions to compile eagerly, for
+  // ScriptCompilerConsumeCompileHints.
+  const int* compileHints;
+  int compileHintsCount;
 } CompileOptions;
 
 typedef struct {
@@ -44,12 +50,40 @@ typedef struct {
   size_t number_of_detached_contexts;
 } IsolateHStatistics;
 
-extern IsolatePtr NewIsolate();
+extern IsolatePtr NewIsolate(size_t max_heap_size);
 extern void IsolatePerformMicrotaskCheckpoint(IsolatePtr ptr);
+extern int IsolatePumpMessageLoop(IsolatePtr ptr, int wait);
 extern void IsolateDispose(IsolatePtr ptr);
 extern void IsolateTerminateExecution(IsolatePtr ptr);
 extern int IsolateIsExecutionTerminating(IsolatePtr ptr);
+extern void IsolateRequestInterrupt(IsolatePtr ptr, int ref);
+extern void IsolateAddNearHeapLimitCallback(IsolatePtr ptr, int ref);
+extern void IsolateAddMicrotasksCompletedCallback(IsolatePtr ptr, int ref);
+extern void IsolateRemoveMicrotasksCompletedCallback(IsolatePtr ptr, int ref);
 extern IsolateHStatistics IsolationGetHeapStatistics(IsolatePtr ptr);
+extern void IsolateMemoryPressureNotification(IsolatePtr ptr, int level);
+extern void IsolateLowMemoryNotification(IsolatePtr ptr);
+extern int64_t IsolateAdjustAmountOfExternalAllocatedMemory(
+    IsolatePtr ptr,
+    int64_t change_in_bytes);
+
+extern uint32_t IsolateNumberOfDataSlots();
+extern int IsolateSetData(IsolatePtr ptr, uint32_t slot, void* data);
+extern void* IsolateGetData(IsolatePtr ptr, uint32_t slot);
+
+typedef struct {
+  const char* name;
+  int64_t value;
+} StatsEntry;
+
+typedef struct {
+  StatsEntry* entries;
+  int count;
+} RtnStats;
+
+extern void IsolateSetStackLimit(IsolatePtr ptr, size_t limit_kb);
+extern void IsolateEnableStats(IsolatePtr ptr);
+extern RtnStats StatsGet(int reset);
 
 extern ValuePtr IsolateThrowException(IsolatePtr iso, ValuePtr value);
 
@@ -6,10 +6,13 @@ package v8go_test
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
 	"strings"
 	"testing"
+	"time"
+	"unsafe"
 
 	v8 "github.com/lizc2003/v8go"
 )
@@ -52,6 +55,96 @@ func TestIsolateTerminateExecution(t *testing.T) {
 	}
 }
 
+func TestIsolateRequestInterrupt(t *testing.T) {
+	t.Parallel()
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	ctx := v8.NewContext(iso)
+	defer ctx.Close()
+
+	var interrupted *v8.Isolate
+	go iso.RequestInterrupt(func(i *v8.Isolate) {
+		interrupted = i
+		i.TerminateExecution()
+	})
+
+	_, e := ctx.RunScript(`while (true) { }`, "forever.js")
+	if e == nil || !strings.HasPrefix(e.Error(), "ExecutionTerminated") {
+		t.Errorf("unexpected error: %v", e)
+	}
+	if interrupted != iso {
+		t.Error("expected interrupt callback to receive the isolate")
+	}
+}
+
+func TestIsolateMicrotasksCompletedCallback(t *testing.T) {
+	t.Parallel()
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	ctx := v8.NewContext(iso)
+	defer ctx.Close()
+
+	var resolved []bool
+	cb := iso.AddMicrotasksCompletedCallback(func(i *v8.Isolate) {
+		val, err := ctx.Global().Get("resolved")
+		fatalIf(t, err)
+		resolved = append(resolved, val.Boolean())
+	})
+
+	_, err := ctx.RunScript(`var resolved = false; Promise.resolve().then(() => { resolved = true })`, "tick.js")
+	fatalIf(t, err)
+	ctx.PerformMicrotaskCheckpoint()
+	if len(resolved) == 0 || !resolved[len(resolved)-1] {
+		t.Fatalf("expected callback after the promise job ran, got %v", resolved)
+	}
+
+	n := len(resolved)
+	iso.RemoveMicrotasksCompletedCallback(cb)
+	_, err = ctx.RunScript(`Promise.resolve().then(() => {})`, "tick.js")
+	fatalIf(t, err)
+	ctx.PerformMicrotaskCheckpoint()
+	if len(resolved) != n {
+		t.Errorf("expected no calls after removal, got %d more", len(resolved)-n)
+	}
+}
+
+func TestIsolateDisposeRemovesCallbacks(t *testing.T) {
+	t.Parallel()
+	iso := v8.NewIsolate()
+
+	cb := iso.AddMicrotasksCompletedCallback(func(*v8.Isolate) {})
+	iso.AddMicrotasksCompletedCallback(func(*v8.Isolate) {})
+	iso.RequestInterrupt(func(*v8.Isolate) {})
+	if n := iso.IsolateCallbackCount(); n != 3 {
+		t.Fatalf("expected 3 registered callbacks, got %d", n)
+	}
+	iso.RemoveMicrotasksCompletedCallback(cb)
+	if n := iso.IsolateCallbackCount(); n != 2 {
+		t.Errorf("expected removing a callback to unregister it, got %d", n)
+	}
+
+	iso.Dispose()
+	if n := iso.IsolateCallbackCount(); n != 0 {
+		t.Errorf("expected Dispose to unregister the callbacks, got %d", n)
+	}
+	// Removing a callback of a disposed isolate is a no-op.
+	iso.RemoveMicrotasksCompletedCallback(cb)
+}
+
+func TestIsolatePumpMessageLoop(t *testing.T) {
+	t.Parallel()
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	iso.RunAllForegroundTasks()
+	if iso.PumpMessageLoop(false) {
+		t.Error("expected no pending foreground tasks")
+	}
+	if n := iso.RunAllForegroundTasks(); n != 0 {
+		t.Errorf("expected no tasks to run, got %d", n)
+	}
+}
+
 func TestIsolateCompileUnboundScript(t *testing.T) {
 	s := "function foo() { return 'bar'; }; foo()"
 
@@ -98,6 +191,29 @@ func TestIsolateCompileUnboundScript(t *testing.T) {
 	}
 }
 
+func TestIsolateCompileUnboundScript_EagerCodeCache(t *testing.T) {
+	t.Parallel()
+	s := "function outer() { function inner(a, b) { return a * b + a - b; } return inner; }"
+
+	// Separate isolates so the second compile can't hit the compilation cache.
+	i1 := v8.NewIsolate()
+	defer i1.Dispose()
+	i2 := v8.NewIsolate()
+	defer i2.Dispose()
+
+	lazy, err := i1.CompileUnboundScript(s, "script.js", v8.CompileOptions{})
+	fatalIf(t, err)
+	eager, err := i2.CompileUnboundScript(s, "script.js", v8.CompileOptions{Mode: v8.CompileModeEager})
+	fatalIf(t, err)
+
+	lazyCache := lazy.CreateCodeCache()
+	eagerCache := eager.CreateCodeCache()
+	if len(eagerCache.Bytes) <= len(lazyCache.Bytes) {
+		t.Errorf("expected eager code cache (%d bytes) to be larger than lazy (%d bytes)",
+			len(eagerCache.Bytes), len(lazyCache.Bytes))
+	}
+}
+
 func TestIsolateCompileUnboundScript_CachedDataRejected(t *testing.T) {
 	s := "function foo() { return 'bar'; }; foo()"
 	iso := v8.NewIsolate()
@@ -139,6 +255,49 @@ func TestIsolateCompileUnboundScript_InvalidOptions(t *testing.T) {
 	}
 }
 
+func TestIsolateCompileUnboundScript_CompileHints(t *testing.T) {
+	t.Parallel()
+	s := "function lazy() { return 'called'; }; function unused() {}; lazy()"
+
+	i1 := v8.NewIsolate()
+	defer i1.Dispose()
+	c1 := v8.NewContext(i1)
+	defer c1.Close()
+
+	us, err := i1.CompileUnboundScript(s, "script.js", v8.CompileOptions{ProduceCompileHints: true})
+	fatalIf(t, err)
+	_, err = us.Run(c1)
+	fatalIf(t, err)
+	hints := us.CompileHints()
+	if len(hints) == 0 {
+		t.Fatal("expected compile hints for the called function")
+	}
+
+	plain, err := i1.CompileUnboundScript(s, "plain.js", v8.CompileOptions{})
+	fatalIf(t, err)
+	if got := plain.CompileHints(); got != nil {
+		t.Errorf("expected no compile hints without ProduceCompileHints, got %v", got)
+	}
+
+	i2 := v8.NewIsolate()
+	defer i2.Dispose()
+	c2 := v8.NewContext(i2)
+	defer c2.Close()
+
+	us2, err := i2.CompileUnboundScript(s, "script.js", v8.CompileOptions{CompileHints: hints})
+	fatalIf(t, err)
+	val, err := us2.Run(c2)
+	fatalIf(t, err)
+	if val.String() != "called" {
+		t.Errorf("invalid value returned, expected called got %v", val)
+	}
+
+	opts := v8.CompileOptions{CompileHints: hints, Mode: v8.CompileModeEager}
+	if recoverPanic(func() { i2.CompileUnboundScript(s, "script.js", opts) }) == nil {
+		t.Error("expected panic combining compile hints with Mode")
+	}
+}
+
 func TestIsolateGetHeapStatistics(t *testing.T) {
 	t.Parallel()
 	iso := v8.NewIsolate()
@@ -159,6 +318,67 @@ func TestIsolateGetHeapStatistics(t *testing.T) {
 	}
 }
 
+func TestIsolateAdjustAmountOfExternalAllocatedMemory(t *testing.T) {
+	t.Parallel()
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	const size = 1 << 20
+	allocated := iso.AdjustAmountOfExternalAllocatedMemory(size)
+	released := iso.AdjustAmountOfExternalAllocatedMemory(-size)
+	if allocated-released != size {
+		t.Errorf("expected external memory to change by %d, got %d", size, allocated-released)
+	}
+}
+
+func TestIsolateData(t *testing.T) {
+	t.Parallel()
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	if v8.NumberOfDataSlots() == 0 {
+		t.Fatal("expected at least one embedder data slot")
+	}
+	if ptr := iso.GetData(0); ptr != nil {
+		t.Errorf("expected empty slot, got %v", ptr)
+	}
+
+	// The data must live outside Go memory, so borrow it from a BackingStore.
+	store, err := v8.NewBackingStore(iso, 8)
+	fatalIf(t, err)
+	defer store.Release()
+	ptr := unsafe.Pointer(&store.Bytes()[0])
+	fatalIf(t, iso.SetData(0, ptr))
+	if got := iso.GetData(0); got != ptr {
+		t.Errorf("expected GetData to return %v, got %v", ptr, got)
+	}
+
+	if err := iso.SetData(v8.NumberOfDataSlots(), ptr); err == nil {
+		t.Error("expected error for an out of range slot, got <nil>")
+	}
+}
+
+func TestIsolateMemoryNotifications(t *testing.T) {
+	t.Parallel()
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	ctx := v8.NewContext(iso)
+	defer ctx.Close()
+
+	_, err := ctx.RunScript("for (let i = 0; i < 100000; i++) { ({ i: i, s: 'garbage' + i }) }", "garbage.js")
+	fatalIf(t, err)
+
+	iso.MemoryPressureNotification(v8.MemoryPressureLevelModerate)
+	iso.MemoryPressureNotification(v8.MemoryPressureLevelNone)
+
+	before := iso.GetHeapStatistics().UsedHeapSize
+	iso.LowMemoryNotification()
+	after := iso.GetHeapStatistics().UsedHeapSize
+	if after > before {
+		t.Errorf("expected used heap size to not grow after a full GC, before %d, after %d", before, after)
+	}
+}
+
 func TestCallbackRegistry(t *testing.T) {
 	t.Parallel()
 
@@ -180,6 +400,127 @@ func TestCallbackRegistry(t *testing.T) {
 	}
 }
 
+func TestCallbackRegistryReleasesUnusedTemplates(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	cb := func(*v8.FunctionCallbackInfo) (*v8.Value, error) { return nil, nil }
+
+	// A template that a function was created from must keep its callback.
+	ctx := v8.NewContext(iso)
+	defer ctx.Close()
+	kept := v8.NewFunctionTemplateWithError(iso, cb)
+	fn := kept.GetFunction(ctx)
+	kept.Finalize()
+
+	before := iso.CallbackCount()
+	for i := 0; i < 1000; i++ {
+		v8.NewFunctionTemplateWithError(iso, cb).Finalize()
+	}
+	if count := iso.CallbackCount(); count != before {
+		t.Errorf("expected the callbacks of dropped templates to be released, got %d registered, want %d", count, before)
+	}
+
+	if _, err := fn.Call(v8.Undefined(iso)); err != nil {
+		t.Errorf("expected the function's callback to stay registered, got %v", err)
+	}
+}
+
+func TestCallbackRegistryKeepsEscapedTemplates(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	ctx := v8.NewContext(iso)
+	defer ctx.Close()
+	cb := func(*v8.FunctionCallbackInfo) (*v8.Value, error) { return nil, nil }
+
+	fnTests := []struct {
+		name   string
+		escape func(tmpl *v8.FunctionTemplate)
+	}{
+		{"GetFunction", func(tmpl *v8.FunctionTemplate) { tmpl.GetFunction(ctx) }},
+		{"InstanceTemplate", func(tmpl *v8.FunctionTemplate) { tmpl.InstanceTemplate() }},
+		{"PrototypeTemplate", func(tmpl *v8.FunctionTemplate) { tmpl.PrototypeTemplate() }},
+		{"Inherit", func(tmpl *v8.FunctionTemplate) {
+			v8.NewFunctionTemplateWithError(iso, cb).Inherit(tmpl)
+		}},
+		{"SetAccessorProperty getter", func(tmpl *v8.FunctionTemplate) {
+			v8.NewObjectTemplate(iso).SetAccessorProperty("x", tmpl, nil, v8.None)
+		}},
+		{"SetAccessorProperty setter", func(tmpl *v8.FunctionTemplate) {
+			v8.NewObjectTemplate(iso).SetAccessorProperty("x", nil, tmpl, v8.None)
+		}},
+		{"Set", func(tmpl *v8.FunctionTemplate) {
+			fatalIf(t, v8.NewObjectTemplate(iso).Set("f", tmpl))
+		}},
+		{"SetSymbol", func(tmpl *v8.FunctionTemplate) {
+			fatalIf(t, v8.NewObjectTemplate(iso).SetSymbol(v8.SymbolIterator(iso), tmpl))
+		}},
+	}
+	for _, tt := range fnTests {
+		tmpl := v8.NewFunctionTemplateWithError(iso, cb)
+		tt.escape(tmpl)
+		before := iso.CallbackCount()
+		tmpl.Finalize()
+		if count := iso.CallbackCount(); count != before {
+			t.Errorf("%s: expected the callback to stay registered, got %d registered, want %d", tt.name, count, before)
+		}
+	}
+
+	deny := func(*v8.Context, *v8.Object, *v8.Value) bool { return false }
+	objTests := []struct {
+		name   string
+		escape func(tmpl *v8.ObjectTemplate)
+	}{
+		{"NewInstance", func(tmpl *v8.ObjectTemplate) {
+			_, err := tmpl.NewInstance(ctx)
+			fatalIf(t, err)
+		}},
+		{"NewContext", func(tmpl *v8.ObjectTemplate) { v8.NewContext(iso, tmpl).Close() }},
+		{"Set", func(tmpl *v8.ObjectTemplate) {
+			fatalIf(t, v8.NewObjectTemplate(iso).Set("o", tmpl))
+		}},
+		{"SetSymbol", func(tmpl *v8.ObjectTemplate) {
+			fatalIf(t, v8.NewObjectTemplate(iso).SetSymbol(v8.SymbolIterator(iso), tmpl))
+		}},
+	}
+	for _, tt := range objTests {
+		tmpl := v8.NewObjectTemplate(iso)
+		tmpl.SetAccessCheckCallback(deny, nil)
+		tt.escape(tmpl)
+		before := iso.AccessCheckCount()
+		tmpl.Finalize()
+		if count := iso.AccessCheckCount(); count != before {
+			t.Errorf("%s: expected the access check to stay registered, got %d registered, want %d", tt.name, count, before)
+		}
+	}
+
+	// The instance and prototype templates of a function template escape
+	// along with it.
+	for _, tmpl := range []*v8.ObjectTemplate{
+		v8.NewFunctionTemplateWithError(iso, cb).InstanceTemplate(),
+		v8.NewFunctionTemplateWithError(iso, cb).PrototypeTemplate(),
+	} {
+		tmpl.SetAccessCheckCallback(deny, nil)
+		before := iso.AccessCheckCount()
+		tmpl.Finalize()
+		if count := iso.AccessCheckCount(); count != before {
+			t.Errorf("expected the access check of a function's object template to stay registered, got %d registered, want %d", count, before)
+		}
+	}
+
+	// An object template that never escaped releases its access check.
+	tmpl := v8.NewObjectTemplate(iso)
+	tmpl.SetAccessCheckCallback(deny, nil)
+	before := iso.AccessCheckCount()
+	tmpl.Finalize()
+	if count := iso.AccessCheckCount(); count != before-1 {
+		t.Errorf("expected the access check of an unused template to be released, got %d registered, want %d", count, before-1)
+	}
+}
+
 func TestIsolateDispose(t *testing.T) {
 	t.Parallel()
 
@@ -199,6 +540,30 @@ func TestIsolateDispose(t *testing.T) {
 	}
 }
 
+func TestIsolateContexts(t *testing.T) {
+	t.Parallel()
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	if got := iso.Contexts(); len(got) != 0 {
+		t.Fatalf("expected no contexts, got %d", len(got))
+	}
+	ctx1 := v8.NewContext(iso)
+	ctx2 := v8.NewContext(iso)
+	defer ctx2.Close()
+	other := v8.NewContext()
+	defer other.Isolate().Dispose()
+	defer other.Close()
+
+	if got := iso.Contexts(); len(got) != 2 || got[0] != ctx1 || got[1] != ctx2 {
+		t.Errorf("expected the two contexts of the isolate in creation order, got %v", got)
+	}
+	ctx1.Close()
+	if got := iso.Contexts(); len(got) != 1 || got[0] != ctx2 {
+		t.Errorf("expected only the open context, got %v", got)
+	}
+}
+
 func TestIsolateThrowException(t *testing.T) {
 	t.Parallel()
 	iso := v8.NewIsolate()
@@ -296,3 +661,156 @@ func makeObject() interface{} {
 		"b": "AAAABBBBAAAABBBBAAAABBBBAAAABBBBAAAABBBB",
 	}
 }
+
+func TestIsolateStackLimit(t *testing.T) {
+	t.Parallel()
+
+	depth := func(limitKB int) int64 {
+		iso, err := v8.NewIsolateWithOptions(v8.IsolateOptions{StackLimitKB: limitKB})
+		fatalIf(t, err)
+		defer iso.Dispose()
+		ctx := v8.NewContext(iso)
+		defer ctx.Close()
+
+		val, err := ctx.RunScript(`
+			let depth = 0;
+			function recurse() { depth++; recurse(); }
+			try { recurse(); } catch (e) { if (!(e instanceof RangeError)) throw e; }
+			depth`, "depth.js")
+		fatalIf(t, err)
+		return val.Integer()
+	}
+
+	small, large := depth(128), depth(448)
+	if small == 0 || large <= small {
+		t.Errorf("expected a larger stack limit to allow deeper recursion, got depth %d for 128 KiB and %d for 448 KiB", small, large)
+	}
+
+	if _, err := v8.NewIsolateWithOptions(v8.IsolateOptions{StackLimitKB: -1}); err == nil {
+		t.Error("expected error for a negative stack limit, got <nil>")
+	}
+}
+
+func TestIsolateNearHeapLimit(t *testing.T) {
+	t.Parallel()
+
+	const maxHeapSize = 64 << 20
+	var calls int
+	var initial uint64
+	terminate := v8.TerminateOnHeapLimit(0)
+	iso, err := v8.NewIsolateWithOptions(v8.IsolateOptions{
+		MaxHeapSize: maxHeapSize,
+		NearHeapLimit: func(iso *v8.Isolate, currentLimit, initialLimit uint64) (uint64, bool) {
+			calls++
+			initial = initialLimit
+			return terminate(iso, currentLimit, initialLimit)
+		},
+	})
+	fatalIf(t, err)
+	defer iso.Dispose()
+	ctx := v8.NewContext(iso)
+	defer ctx.Close()
+
+	const hog = `(function () {
+		const chunks = [];
+		for (;;) chunks.push(new Array(100000).fill(1));
+	})()`
+	_, err = ctx.RunScript(hog, "hog.js")
+	var herr *v8.HeapLimitError
+	if !errors.As(err, &herr) {
+		t.Fatalf("expected a *HeapLimitError, got %v", err)
+	}
+	if herr.Limit == 0 || herr.Err == nil {
+		t.Errorf("expected the limit and the termination error, got %+v", herr)
+	}
+	if calls == 0 || initial == 0 || initial > maxHeapSize {
+		t.Errorf("unexpected callback calls %d with initial limit %d", calls, initial)
+	}
+
+	val, err := ctx.RunScript("1 + 1", "")
+	fatalIf(t, err)
+	if val.Integer() != 2 {
+		t.Errorf("expected the isolate to be usable after the limit, got %v", val)
+	}
+
+	// Calls that don't return a value report the limit too.
+	if _, err := ctx.EvalJSON(hog); !errors.As(err, &herr) {
+		t.Errorf("expected a *HeapLimitError from EvalJSON, got %v", err)
+	}
+}
+
+func TestIsolateHeapLimitErrorOnlyWrapsTermination(t *testing.T) {
+	t.Parallel()
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	ctx.Isolate().SetHeapLimitError(1 << 20)
+	_, err := ctx.RunScript("let a = ;", "syntax.js")
+	var herr *v8.HeapLimitError
+	if err == nil || errors.As(err, &herr) {
+		t.Errorf("expected the syntax error alone, got %v", err)
+	}
+}
+
+func TestIsolatePredictableMode(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	run := func(seed int64) string {
+		iso, err := v8.NewIsolateWithOptions(v8.IsolateOptions{
+			PredictableMode: true,
+			RandomSeed:      seed,
+			Clock:           func() time.Time { return now },
+		})
+		fatalIf(t, err)
+		defer iso.Dispose()
+		ctx := v8.NewContext(iso)
+		defer ctx.Close()
+
+		val, err := ctx.RunScript(`
+			class Stamp extends Date {}
+			[
+				Math.random(), Math.random(),
+				Date.now(), new Date().toISOString(), new Date(0).getTime(),
+				new Date() instanceof Date, new Stamp() instanceof Date, new Stamp().getTime(),
+				new Date().constructor === Date, Date.name, typeof Date(),
+			].join("|")`, "predictable.js")
+		fatalIf(t, err)
+		return val.String()
+	}
+
+	first, second := run(42), run(42)
+	if first != second {
+		t.Errorf("expected the same output from runs with the same seed, got %q and %q", first, second)
+	}
+	if other := run(7); other == first {
+		t.Errorf("expected a different seed to change Math.random, got %q twice", first)
+	}
+	ms := now.UnixMilli()
+	want := fmt.Sprintf("|%d|2024-03-01T12:00:00.000Z|0|true|true|%d|true|Date|string", ms, ms)
+	if !strings.HasSuffix(first, want) {
+		t.Errorf("unexpected output %q, want suffix %q", first, want)
+	}
+
+	frozen, err := v8.NewIsolateWithOptions(v8.IsolateOptions{PredictableMode: true})
+	fatalIf(t, err)
+	defer frozen.Dispose()
+	ctx := v8.NewContext(frozen)
+	defer ctx.Close()
+	val, err := ctx.RunScript("Date.now()", "")
+	fatalIf(t, err)
+	if val.Integer() != 0 {
+		t.Errorf("expected the default clock to be frozen at the epoch, got %v", val)
+	}
+
+	// A global template that prevents replacing Date fails the context.
+	global := v8.NewObjectTemplate(frozen)
+	fatalIf(t, global.Set("Date", "fixed", v8.ReadOnly, v8.DontDelete))
+	if _, err := v8.NewContextWithError(frozen, global); err == nil {
+		t.Error("expected an error creating a predictable context with a read-only Date")
+	}
+	if recoverPanic(func() { v8.NewContext(frozen, global) }) == nil {
+		t.Error("expected NewContext to panic with a read-only Date")
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+import "errors"
+
+// Iterator drives the JavaScript iterator protocol from Go, e.g. over a Map,
+// Set, Array, generator or any other object implementing Symbol.iterator.
+type Iterator struct {
+	iter *Object
+	next *Function
+	done bool
+}
+
+// Iterate gets an Iterator for the value by calling its Symbol.iterator
+// method, equivalent to how a `for...of` loop starts in JS. Primitive
+// values such as strings are converted to objects first. An error is
+// returned if the value is not iterable or the method throws.
+func (v *Value) Iterate(ctx *Context) (*Iterator, error) {
+	obj, err := v.ToObject(ctx)
+	if err != nil {
+		return nil, err
+	}
+	method, err := obj.GetSymbol(SymbolIterator(obj.ctx.iso))
+	if err != nil {
+		return nil, err
+	}
+	if !method.IsFunction() {
+		return nil, errors.New("v8go: value is not iterable")
+	}
+	fn, _ := method.AsFunction()
+	it, err := fn.Call(obj)
+	if err != nil {
+		return nil, err
+	}
+	iter, err := it.AsObject()
+	if err != nil {
+		return nil, errors.New("v8go: Symbol.iterator did not return an object")
+	}
+	next, err := iter.Get("next")
+	if err != nil {
+		return nil, err
+	}
+	nextFn, err := next.AsFunction()
+	if err != nil {
+		return nil, errors.New("v8go: iterator has no next method")
+	}
+	return &Iterator{iter: iter, next: nextFn}, nil
+}
+
+// Next advances the iterator. It returns the next value and true, or nil and
+// false once the iterator is done. Any exception thrown by the iterator is
+// returned as an error.
+func (it *Iterator) Next() (*Value, bool, error) {
+	if it.done {
+		return nil, false, nil
+	}
+	res, err := it.next.Call(it.iter)
+	if err != nil {
+		return nil, false, err
+	}
+	result, err := res.AsObject()
+	if err != nil {
+		return nil, false, errors.New("v8go: iterator result is not an object")
+	}
+	done, err := result.Get("done")
+	if err != nil {
+		return nil, false, err
+	}
+	if done.Boolean() {
+		it.done = true
+		return nil, false, nil
+	}
+	val, err := result.Get("value")
+	if err != nil {
+		return nil, false, err
+	}
+	return val, true, nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"reflect"
+	"testing"
+
+	v8 "github.com/lizc2003/v8go"
+)
+
+func TestValueIterate(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	tests := [...]struct {
+		source string
+		want   []string
+	}{
+		{"[1, 2, 3]", []string{"1", "2", "3"}},
+		{"new Set(['a', 'b'])", []string{"a", "b"}},
+		{"new Map([['k', 'v']])", []string{"k,v"}},
+		{"(function* () { yield 'x'; yield 'y' })()", []string{"x", "y"}},
+		{"'hi'", []string{"h", "i"}},
+	}
+	for _, tt := range tests {
+		val, err := ctx.RunScript(tt.source, "iterate.js")
+		fatalIf(t, err)
+		it, err := val.Iterate(ctx)
+		fatalIf(t, err)
+
+		var got []string
+		for {
+			v, ok, err := it.Next()
+			fatalIf(t, err)
+			if !ok {
+				break
+			}
+			got = append(got, v.String())
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.source, tt.want, got)
+		}
+	}
+
+	val, err := ctx.RunScript("({})", "iterate.js")
+	fatalIf(t, err)
+	if _, err := val.Iterate(ctx); err == nil {
+		t.Error("expected error for non-iterable value")
+	}
+
+	val, err = ctx.RunScript("(function* () { throw new Error('boom') })()", "iterate.js")
+	fatalIf(t, err)
+	it, err := val.Iterate(ctx)
+	fatalIf(t, err)
+	if _, _, err := it.Next(); err == nil {
+		t.Error("expected error from throwing iterator")
+	}
+}
@@ -36,7 +36,7 @@ func JSONStringify(ctx *Context, val Valuer) (string, error) {
 		ctxPtr = ctx.ptr
 	}
 
-	str := C.JSONStringify(ctxPtr, val.value().ptr)
+	str := C.JSONStringify(ctxPtr, val.value().valuePtr())
 	defer C.free(unsafe.Pointer(str))
 	return C.GoString(str), nil
 }
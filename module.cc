@@ -0,0 +1,284 @@
+#include "_cgo_export.h"
+
+#include "module.h"
+#include "context-macros.h"
+#include "deps/include/v8-data.h"
+#include "deps/include/v8-primitive.h"
+#include "deps/include/v8-promise.h"
+#include "deps/include/v8-script.h"
+#include "isolate-macros.h"
+#include "utils.h"
+#include "value.h"
+
+using namespace v8;
+
+static m_module* tracked_module(m_ctx* ctx, Local<Module> local_mod) {
+  m_module* mod = new m_module;
+  mod->ptr.Reset(ctx->iso, local_mod);
+  ctx->modules.emplace(local_mod->GetIdentityHash(), mod);
+  return mod;
+}
+
+static m_module* lookup_module(m_ctx* ctx, Local<Module> local_mod) {
+  auto range = ctx->modules.equal_range(local_mod->GetIdentityHash());
+  for (auto it = range.first; it != range.second; ++it) {
+    if (it->second->ptr == local_mod) {
+      return it->second;
+    }
+  }
+  return nullptr;
+}
+
+static int context_ref(Local<Context> local_ctx) {
+  return local_ctx->GetEmbedderData(1).As<Integer>()->Value();
+}
+
+// V8 calls this for each import of a module being instantiated; it hands the
+// import over to the Go resolver passed to Module.Instantiate.
+static MaybeLocal<Module> ResolveModule(Local<Context> local_ctx,
+                                        Local<String> specifier,
+                                        Local<FixedArray> import_attributes,
+                                        Local<Module> referrer) {
+  Isolate* iso = local_ctx->GetIsolate();
+  int ctx_ref = context_ref(local_ctx);
+  m_ctx* ctx = goContext(ctx_ref);
+
+  String::Utf8Value spec(iso, specifier);
+
+  // The attributes are given as key, value and source offset triples.
+  std::vector<std::string> attributes;
+  for (int i = 0; i + 1 < import_attributes->Length(); i += 3) {
+    String::Utf8Value key(iso,
+                          import_attributes->Get(local_ctx, i).As<String>());
+    String::Utf8Value value(
+        iso, import_attributes->Get(local_ctx, i + 1).As<String>());
+    attributes.push_back(std::string(*key, key.length()));
+    attributes.push_back(std::string(*value, value.length()));
+  }
+  std::vector<const char*> attribute_ptrs;
+  for (const std::string& s : attributes) {
+    attribute_ptrs.push_back(s.c_str());
+  }
+
+  goResolveModule_return rtn =
+      goResolveModule(ctx_ref, const_cast<char*>(*spec),
+                      const_cast<char**>(attribute_ptrs.data()),
+                      attribute_ptrs.size(), lookup_module(ctx, referrer));
+  if (rtn.r1 != nullptr) {
+    iso->ThrowException(rtn.r1->ptr.Get(iso));
+    return MaybeLocal<Module>();
+  }
+  return rtn.r0->ptr.Get(iso);
+}
+
+static MaybeLocal<Value> EvaluateSyntheticModule(Local<Context> local_ctx,
+                                                 Local<Module> local_mod) {
+  Isolate* iso = local_ctx->GetIsolate();
+  m_ctx* ctx = goContext(context_ref(local_ctx));
+
+  m_module* mod = lookup_module(ctx, local_mod);
+  if (mod != nullptr) {
+    for (auto& e : mod->exports) {
+      Local<String> name;
+      if (!String::NewFromUtf8(iso, e.first.data(), NewStringType::kNormal,
+                               e.first.length())
+               .ToLocal(&name)) {
+        return MaybeLocal<Value>();
+      }
+      if (local_mod->SetSyntheticModuleExport(iso, name, e.second.Get(iso))
+              .IsNothing()) {
+        return MaybeLocal<Value>();
+      }
+    }
+  }
+
+  Local<Promise::Resolver> resolver;
+  if (!Promise::Resolver::New(local_ctx).ToLocal(&resolver) ||
+      resolver->Resolve(local_ctx, Undefined(iso)).IsNothing()) {
+    return MaybeLocal<Value>();
+  }
+  return resolver->GetPromise();
+}
+
+RtnModule CompileModule(ContextPtr ctx,
+                        const char* source,
+                        const char* origin) {
+  LOCAL_CONTEXT(ctx);
+
+  RtnModule rtn = {};
+
+  MaybeLocal<String> maybeSrc =
+      String::NewFromUtf8(iso, source, NewStringType::kNormal);
+  MaybeLocal<String> maybeOgn =
+      String::NewFromUtf8(iso, origin, NewStringType::kNormal);
+  Local<String> src, ogn;
+  if (!maybeSrc.ToLocal(&src) || !maybeOgn.ToLocal(&ogn)) {
+    rtn.error = ExceptionError(try_catch, iso, local_ctx);
+    return rtn;
+  }
+
+  ScriptOrigin script_origin(ogn, 0, 0, false, -1, Local<Value>(), false,
+                             false, true);
+  ScriptCompiler::Source script_source(src, script_origin);
+  Local<Module> local_mod;
+  if (!ScriptCompiler::CompileModule(iso, &script_source)
+           .ToLocal(&local_mod)) {
+    rtn.error = ExceptionError(try_catch, iso, local_ctx);
+    return rtn;
+  }
+  rtn.ptr = tracked_module(ctx, local_mod);
+  return rtn;
+}
+
+RtnModule NewSyntheticModule(ContextPtr ctx,
+                             const char* name,
+                             const char** export_names,
+                             ValuePtr* export_values,
+                             int exports_count) {
+  LOCAL_CONTEXT(ctx);
+
+  RtnModule rtn = {};
+
+  Local<String> module_name;
+  if (!String::NewFromUtf8(iso, name, NewStringType::kNormal)
+           .ToLocal(&module_name)) {
+    rtn.error = ExceptionError(try_catch, iso, local_ctx);
+    return rtn;
+  }
+  std::vector<Local<String>> names(exports_count);
+  for (int i = 0; i < exports_count; i++) {
+    if (!String::NewFromUtf8(iso, export_names[i], NewStringType::kNormal)
+             .ToLocal(&names[i])) {
+      rtn.error = ExceptionError(try_catch, iso, local_ctx);
+      return rtn;
+    }
+  }
+
+  Local<Module> local_mod = Module::CreateSyntheticModule(
+      iso, module_name,
+      MemorySpan<const Local<String>>(names.data(), names.size()),
+      EvaluateSyntheticModule);
+  m_module* mod = tracked_module(ctx, local_mod);
+  for (int i = 0; i < exports_count; i++) {
+    mod->exports.emplace_back(
+        export_names[i], Global<Value>(iso, export_values[i]->ptr.Get(iso)));
+  }
+  rtn.ptr = mod;
+  return rtn;
+}
+
+RtnError ModuleInstantiate(ContextPtr ctx, ModulePtr mod) {
+  LOCAL_CONTEXT(ctx);
+
+  RtnError rtn = {};
+
+  Local<Module> local_mod = mod->ptr.Get(iso);
+  if (local_mod->InstantiateModule(local_ctx, ResolveModule).IsNothing()) {
+    return ExceptionError(try_catch, iso, local_ctx);
+  }
+  return rtn;
+}
+
+RtnValue ModuleEvaluate(ContextPtr ctx, ModulePtr mod) {
+  LOCAL_CONTEXT(ctx);
+
+  RtnValue rtn = {};
+
+  Local<Module> local_mod = mod->ptr.Get(iso);
+  Local<Value> result;
+  if (!local_mod->Evaluate(local_ctx).ToLocal(&result)) {
+    rtn.error = ExceptionError(try_catch, iso, local_ctx);
+    return rtn;
+  }
+  // Evaluation always returns a promise. Without top-level await it is
+  // already settled, so report a failure the same way as for scripts.
+  if (!local_mod->IsGraphAsync() &&
+      local_mod->GetStatus() == Module::kErrored) {
+    iso->ThrowException(local_mod->GetException());
+    rtn.error = ExceptionError(try_catch, iso, local_ctx);
+    return rtn;
+  }
+  m_value* val = new m_value;
+  val->id = 0;
+  val->iso = iso;
+  val->ctx = ctx;
+  val->ptr = Global<Value>(iso, result);
+
+  rtn.value = tracked_value(ctx, val);
+  return rtn;
+}
+
+RtnValue ModuleGetNamespace(ContextPtr ctx, ModulePtr mod) {
+  LOCAL_CONTEXT(ctx);
+
+  RtnValue rtn = {};
+
+  Local<Module> local_mod = mod->ptr.Get(iso);
+  if (local_mod->GetStatus() < Module::kInstantiated) {
+    rtn.error.msg = CopyString("module is not instantiated");
+    return rtn;
+  }
+  m_value* val = new m_value;
+  val->id = 0;
+  val->iso = iso;
+  val->ctx = ctx;
+  val->ptr = Global<Value>(iso, local_mod->GetModuleNamespace());
+
+  rtn.value = tracked_value(ctx, val);
+  return rtn;
+}
+
+int ModuleGetStatus(ContextPtr ctx, ModulePtr mod) {
+  LOCAL_CONTEXT(ctx);
+  return mod->ptr.Get(iso)->GetStatus();
+}
+
+RtnModuleRequests ModuleGetRequests(ContextPtr ctx, ModulePtr mod) {
+  LOCAL_CONTEXT(ctx);
+
+  RtnModuleRequests rtn = {};
+
+  Local<Module> local_mod = mod->ptr.Get(iso);
+  if (local_mod->IsSyntheticModule()) {
+    return rtn;
+  }
+  Local<FixedArray> requests = local_mod->GetModuleRequests();
+  rtn.count = requests->Length();
+  if (rtn.count == 0) {
+    return rtn;
+  }
+  rtn.requests =
+      (ModuleRequestInfo*)malloc(sizeof(ModuleRequestInfo) * rtn.count);
+  for (int i = 0; i < rtn.count; i++) {
+    Local<ModuleRequest> request =
+        requests->Get(local_ctx, i).As<ModuleRequest>();
+    String::Utf8Value specifier(iso, request->GetSpecifier());
+
+    // The attributes are given as key, value and source offset triples.
+    Local<FixedArray> attributes = request->GetImportAttributes();
+    int count = attributes->Length() / 3 * 2;
+    const char** kv = (const char**)malloc(sizeof(const char*) * count);
+    for (int j = 0; j < count / 2; j++) {
+      String::Utf8Value key(iso,
+                            attributes->Get(local_ctx, j * 3).As<String>());
+      String::Utf8Value value(
+          iso, attributes->Get(local_ctx, j * 3 + 1).As<String>());
+      kv[j * 2] = CopyString(key);
+      kv[j * 2 + 1] = CopyString(value);
+    }
+    rtn.requests[i] = {CopyString(specifier), kv, count};
+  }
+  return rtn;
+}
+
+void ModuleRequestsFree(RtnModuleRequests rtn) {
+  for (int i = 0; i < rtn.count; i++) {
+    ModuleRequestInfo& request = rtn.requests[i];
+    free((void*)request.specifier);
+    for (int j = 0; j < request.attributes_count; j++) {
+      free((void*)request.attributes[j]);
+    }
+    free((void*)request.attributes);
+  }
+  free(rtn.requests);
+}
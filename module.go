@@ -0,0 +1,305 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+// #include <stdlib.h>
+// #include "module.h"
+import "C"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unsafe"
+)
+
+// Module is an ECMAScript module, compiled from source with
+// Context.CompileModule or created from Go values with
+// Context.NewSyntheticModule. A module belongs to the context it was created
+// in and is freed when the context is closed.
+type Module struct {
+	ptr C.ModulePtr
+	ctx *Context
+}
+
+// ModuleStatus is the stage a module has reached in its lifecycle.
+type ModuleStatus int
+
+const (
+	ModuleUninstantiated ModuleStatus = iota
+	ModuleInstantiating
+	ModuleInstantiated
+	ModuleEvaluating
+	ModuleEvaluated
+	ModuleErrored
+)
+
+// ModuleRequest is an import of a module: the specifier of the imported
+// module and its import attributes, e.g. `{"type": "json"}` for
+// `import data from "./data.json" with { type: "json" }`. Attributes is nil
+// if the import has none.
+type ModuleRequest struct {
+	Specifier  string
+	Attributes map[string]string
+}
+
+// ModuleResolver returns the module to link for an import made by referrer.
+// As hosts are expected to reject import attributes they don't support, a
+// resolver should return an error for attributes it doesn't know, rather
+// than ignore them. A returned error is thrown to JS as in a FunctionCallback
+// and fails the instantiation.
+type ModuleResolver func(ctx *Context, req ModuleRequest, referrer *Module) (*Module, error)
+
+// CompileModule compiles source as an ECMAScript module. The module must be
+// instantiated and evaluated before its exports can be used.
+// If the source has a syntax error, the error will be of type `JSError`.
+func (c *Context) CompileModule(source, origin string) (*Module, error) {
+	cSource := C.CString(source)
+	cOrigin := C.CString(scriptOrigin(origin))
+	defer C.free(unsafe.Pointer(cSource))
+	defer C.free(unsafe.Pointer(cOrigin))
+
+	rtn := C.CompileModule(c.ptr, cSource, cOrigin)
+	return moduleResult(c, rtn)
+}
+
+// NewSyntheticModule creates a module whose exports are the given values,
+// which is how modules that are not JS source, such as JSON modules, are
+// made available to imports. Use "default" as the name of the default export.
+// The name of the module is only used for debugging.
+func (c *Context) NewSyntheticModule(name string, exports map[string]Valuer) (*Module, error) {
+	nv, err := newNamedValues(exports, "export")
+	if err != nil {
+		return nil, err
+	}
+	defer nv.free()
+
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	rtn := C.NewSyntheticModule(c.ptr, cName, nv.namesPtr(), nv.valuesPtr(), nv.count())
+	return moduleResult(c, rtn)
+}
+
+// NewJSONModule creates a synthetic module whose default export is source
+// parsed as JSON, as imported by `import data from "./data.json" with { type:
+// "json" }`. If source is not valid JSON, the error will be of type `JSError`.
+func (c *Context) NewJSONModule(name, source string) (*Module, error) {
+	val, err := JSONParse(c, source)
+	if err != nil {
+		return nil, err
+	}
+	return c.NewSyntheticModule(name, map[string]Valuer{"default": val})
+}
+
+// EnableJSONModules resolves imports of JSON modules in the context with
+// NewJSONModule, using load to read their source, so the ModuleResolver
+// passed to Module.Instantiate doesn't have to handle them. An import is of a
+// JSON module if it has the `type: "json"` attribute or, without a type
+// attribute, if its specifier ends in ".json". Each specifier is loaded once
+// per context. An error returned by load or a JSON syntax error fails the
+// instantiation of the importing module.
+func (c *Context) EnableJSONModules(load func(specifier string) ([]byte, error)) error {
+	if load == nil {
+		return errors.New("v8go: load is required")
+	}
+	c.jsonModuleLoader = load
+	c.jsonModules = make(map[string]*Module)
+	return nil
+}
+
+func moduleResult(ctx *Context, rtn C.RtnModule) (*Module, error) {
+	if rtn.ptr == nil {
+		return nil, ctx.resultError(rtn.error)
+	}
+	return &Module{ptr: rtn.ptr, ctx: ctx}, nil
+}
+
+// Instantiate links the module and, recursively, the modules it imports.
+// resolve is called once for each import of a module that isn't linked yet,
+// except for JSON modules when EnableJSONModules is used, and may be nil if
+// there are no other imports. The modules it returns must belong to the same
+// context.
+func (m *Module) Instantiate(resolve ModuleResolver) error {
+	m.ctx.moduleResolver = resolve
+	defer func() { m.ctx.moduleResolver = nil }()
+
+	rtn := C.ModuleInstantiate(m.ctx.ptr, m.ptr)
+	if rtn.msg != nil {
+		return m.ctx.resultError(rtn)
+	}
+	return nil
+}
+
+// Evaluate runs the module and the modules it imports, which must have been
+// instantiated, and returns the promise of their evaluation. The promise is
+// only pending if the module graph uses top-level await; the caller must then
+// perform microtask checkpoints, and pump the message loop for APIs that
+// complete in tasks, until it settles, or use EvaluateAndWait. If evaluation
+// fails without top-level await, the error is returned and will be of type
+// `JSError`; with top-level await, the promise is rejected instead.
+func (m *Module) Evaluate() (*Promise, error) {
+	rtn := C.ModuleEvaluate(m.ctx.ptr, m.ptr)
+	val, err := valueResult(m.ctx, rtn)
+	if err != nil {
+		return nil, err
+	}
+	return val.AsPromise()
+}
+
+// EvaluateAndWait evaluates the module like Evaluate and then performs
+// microtask checkpoints, which also run pending foreground tasks, until the
+// promise of the evaluation settles. It returns the module namespace once the
+// module is evaluated, or the rejection as an *Exception if evaluation fails.
+//
+// A module may await a promise that only the host settles, e.g. a
+// PromiseResolver resolved from another goroutine, in which case
+// EvaluateAndWait keeps polling until goCtx is done and then returns its
+// error. Use a context with a deadline unless the awaited work is known to
+// complete.
+func (m *Module) EvaluateAndWait(goCtx context.Context) (*Object, error) {
+	p, err := m.Evaluate()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		m.ctx.PerformMicrotaskCheckpoint()
+		switch p.State() {
+		case Fulfilled:
+			return m.Namespace()
+		case Rejected:
+			return nil, &Exception{p.Result()}
+		}
+		select {
+		case <-goCtx.Done():
+			return nil, goCtx.Err()
+		case <-time.After(moduleEvaluatePollInterval):
+		}
+	}
+}
+
+// moduleEvaluatePollInterval is how long EvaluateAndWait waits for the host
+// to settle a promise awaited by a module before checking it again.
+const moduleEvaluatePollInterval = time.Millisecond
+
+// Namespace returns the module namespace object, which holds the exports of
+// the module. It returns an error if the module is not instantiated.
+func (m *Module) Namespace() (*Object, error) {
+	rtn := C.ModuleGetNamespace(m.ctx.ptr, m.ptr)
+	return objectResult(m.ctx, rtn)
+}
+
+// Status returns the stage the module has reached in its lifecycle.
+func (m *Module) Status() ModuleStatus {
+	return ModuleStatus(C.ModuleGetStatus(m.ctx.ptr, m.ptr))
+}
+
+// Requests returns the imports of the module in source order, which lets a
+// host fetch the imported modules before instantiating it. A synthetic module
+// has no imports.
+func (m *Module) Requests() []ModuleRequest {
+	rtn := C.ModuleGetRequests(m.ctx.ptr, m.ptr)
+	if rtn.count == 0 {
+		return nil
+	}
+	defer C.ModuleRequestsFree(rtn)
+
+	crequests := unsafe.Slice(rtn.requests, rtn.count)
+	requests := make([]ModuleRequest, len(crequests))
+	for i, r := range crequests {
+		requests[i] = ModuleRequest{
+			Specifier:  C.GoString(r.specifier),
+			Attributes: importAttributes(r.attributes, int(r.attributes_count)),
+		}
+	}
+	return requests
+}
+
+func importAttributes(kv **C.char, count int) map[string]string {
+	if count == 0 {
+		return nil
+	}
+	strs := unsafe.Slice(kv, count)
+	attrs := make(map[string]string, count/2)
+	for i := 0; i+1 < count; i += 2 {
+		attrs[C.GoString(strs[i])] = C.GoString(strs[i+1])
+	}
+	return attrs
+}
+
+//export goResolveModule
+func goResolveModule(
+	ctxref int,
+	specifier *C.char,
+	attributes **C.char,
+	attributesCount int,
+	referrer C.ModulePtr,
+) (rmod C.ModulePtr, rerr C.ValuePtr) {
+	ctx := getContext(ctxref)
+	req := ModuleRequest{
+		Specifier:  C.GoString(specifier),
+		Attributes: importAttributes(attributes, attributesCount),
+	}
+
+	var ref *Module
+	if referrer != nil {
+		ref = &Module{ptr: referrer, ctx: ctx}
+	}
+	mod, err := ctx.resolveModule(req, ref)
+	if err != nil {
+		if verr, ok := err.(ValueError); ok {
+			return nil, verr.value().ptr
+		}
+		errv, err := NewErrorValue(ctx, ErrorKindGeneric, err.Error())
+		if err != nil {
+			panic(err)
+		}
+		return nil, errv.ptr
+	}
+	return mod.ptr, nil
+}
+
+func (c *Context) resolveModule(req ModuleRequest, referrer *Module) (*Module, error) {
+	if c.jsonModuleLoader != nil && isJSONModule(req) {
+		return c.resolveJSONModule(req)
+	}
+	if c.moduleResolver == nil {
+		return nil, fmt.Errorf("cannot resolve module %q: no module resolver", req.Specifier)
+	}
+	mod, err := c.moduleResolver(c, req, referrer)
+	if err != nil {
+		return nil, err
+	}
+	if mod == nil {
+		return nil, fmt.Errorf("cannot resolve module %q", req.Specifier)
+	}
+	if mod.ctx != c {
+		return nil, fmt.Errorf("cannot resolve module %q: module belongs to a different context", req.Specifier)
+	}
+	return mod, nil
+}
+
+func (c *Context) resolveJSONModule(req ModuleRequest) (*Module, error) {
+	if mod, ok := c.jsonModules[req.Specifier]; ok {
+		return mod, nil
+	}
+	src, err := c.jsonModuleLoader(req.Specifier)
+	if err != nil {
+		return nil, err
+	}
+	mod, err := c.NewJSONModule(req.Specifier, string(src))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON module %q: %w", req.Specifier, err)
+	}
+	c.jsonModules[req.Specifier] = mod
+	return mod, nil
+}
+
+func isJSONModule(req ModuleRequest) bool {
+	if typ, ok := req.Attributes["type"]; ok {
+		return typ == "json"
+	}
+	return strings.HasSuffix(req.Specifier, ".json")
+}
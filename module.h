@@ -0,0 +1,72 @@
+#ifndef V8GO_MODULE_H
+#define V8GO_MODULE_H
+
+#include "errors.h"
+
+#ifdef __cplusplus
+
+#include "deps/include/v8-persistent-handle.h"
+
+#include <string>
+#include <utility>
+#include <vector>
+
+namespace v8 {
+class Module;
+class String;
+class Value;
+}  // namespace v8
+
+struct m_module {
+  v8::Global<v8::Module> ptr;
+  // The export names and values of a synthetic module, set on the module
+  // when it is evaluated.
+  std::vector<std::pair<std::string, v8::Global<v8::Value>>> exports;
+};
+
+extern "C" {
+#endif
+
+typedef struct m_ctx m_ctx;
+typedef m_ctx* ContextPtr;
+
+typedef struct m_module m_module;
+typedef m_module* ModulePtr;
+
+typedef struct {
+  ModulePtr ptr;
+  RtnError error;
+} RtnModule;
+
+typedef struct {
+  const char* specifier;
+  // Import attributes as key, value pairs.
+  const char** attributes;
+  int attributes_count;
+} ModuleRequestInfo;
+
+typedef struct {
+  ModuleRequestInfo* requests;
+  int count;
+} RtnModuleRequests;
+
+extern RtnModule CompileModule(ContextPtr ctx_ptr,
+                               const char* source,
+                               const char* origin);
+extern RtnModule NewSyntheticModule(ContextPtr ctx_ptr,
+                                    const char* name,
+                                    const char** export_names,
+                                    ValuePtr* export_values,
+                                    int exports_count);
+extern RtnError ModuleInstantiate(ContextPtr ctx_ptr, ModulePtr mod_ptr);
+extern RtnValue ModuleEvaluate(ContextPtr ctx_ptr, ModulePtr mod_ptr);
+extern RtnValue ModuleGetNamespace(ContextPtr ctx_ptr, ModulePtr mod_ptr);
+extern int ModuleGetStatus(ContextPtr ctx_ptr, ModulePtr mod_ptr);
+extern RtnModuleRequests ModuleGetRequests(ContextPtr ctx_ptr,
+                                           ModulePtr mod_ptr);
+extern void ModuleRequestsFree(RtnModuleRequests requests);
+
+#ifdef __cplusplus
+}  // extern "C"
+#endif
+#endif
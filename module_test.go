@@ -0,0 +1,254 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	v8 "github.com/lizc2003/v8go"
+)
+
+func TestModuleEvaluate(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	mod, err := ctx.CompileModule(`export const answer = 6 * 7;`, "answer.mjs")
+	fatalIf(t, err)
+	if got := mod.Status(); got != v8.ModuleUninstantiated {
+		t.Errorf("expected uninstantiated module, got status %v", got)
+	}
+	if _, err := mod.Namespace(); err == nil {
+		t.Error("expected error getting the namespace of an uninstantiated module, got <nil>")
+	}
+
+	fatalIf(t, mod.Instantiate(nil))
+	p, err := mod.Evaluate()
+	fatalIf(t, err)
+	if p.State() != v8.Fulfilled {
+		t.Errorf("expected the evaluation promise to be fulfilled, got state %v", p.State())
+	}
+	if got := mod.Status(); got != v8.ModuleEvaluated {
+		t.Errorf("expected evaluated module, got status %v", got)
+	}
+
+	ns, err := mod.Namespace()
+	fatalIf(t, err)
+	answer, err := ns.Get("answer")
+	fatalIf(t, err)
+	if answer.Integer() != 42 {
+		t.Errorf("expected answer to be 42, got %v", answer)
+	}
+}
+
+func TestModuleImportAttributes(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	sources := map[string]string{
+		"config.json": `{"base": 40}`,
+		"math.mjs":    `export function add(a, b) { return a + b; }`,
+	}
+	resolve := func(ctx *v8.Context, req v8.ModuleRequest, referrer *v8.Module) (*v8.Module, error) {
+		src, ok := sources[req.Specifier]
+		if !ok {
+			return nil, fmt.Errorf("module %q not found", req.Specifier)
+		}
+		switch typ := req.Attributes["type"]; typ {
+		case "json":
+			val, err := v8.JSONParse(ctx, src)
+			if err != nil {
+				return nil, err
+			}
+			return ctx.NewSyntheticModule(req.Specifier, map[string]v8.Valuer{"default": val})
+		case "":
+			return ctx.CompileModule(src, req.Specifier)
+		default:
+			return nil, v8.NewTypeError(ctx.Isolate(), fmt.Sprintf("unsupported module type %q", typ))
+		}
+	}
+
+	mod, err := ctx.CompileModule(`
+		import config from "config.json" with { type: "json" };
+		import { add } from "math.mjs";
+		export const result = add(config.base, 2);
+	`, "main.mjs")
+	fatalIf(t, err)
+
+	want := []v8.ModuleRequest{
+		{Specifier: "config.json", Attributes: map[string]string{"type": "json"}},
+		{Specifier: "math.mjs"},
+	}
+	if got := mod.Requests(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got requests %+v, want %+v", got, want)
+	}
+
+	fatalIf(t, mod.Instantiate(resolve))
+	_, err = mod.Evaluate()
+	fatalIf(t, err)
+	ns, err := mod.Namespace()
+	fatalIf(t, err)
+	result, err := ns.Get("result")
+	fatalIf(t, err)
+	if result.Integer() != 42 {
+		t.Errorf("expected result to be 42, got %v", result)
+	}
+
+	mod, err = ctx.CompileModule(`import css from "config.json" with { type: "css" };`, "styles.mjs")
+	fatalIf(t, err)
+	err = mod.Instantiate(resolve)
+	if err == nil || !strings.Contains(err.Error(), `TypeError: unsupported module type "css"`) {
+		t.Errorf("expected unsupported module type error, got %v", err)
+	}
+}
+
+func TestModuleEvaluateAndWait(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	mod, err := ctx.CompileModule(`
+		const base = await Promise.resolve(40);
+		export const answer = base + await new Promise(resolve => resolve(2));
+	`, "tla.mjs")
+	fatalIf(t, err)
+	fatalIf(t, mod.Instantiate(nil))
+	ns, err := mod.EvaluateAndWait(context.Background())
+	fatalIf(t, err)
+	answer, err := ns.Get("answer")
+	fatalIf(t, err)
+	if answer.Integer() != 42 {
+		t.Errorf("expected answer to be 42, got %v", answer)
+	}
+
+	mod, err = ctx.CompileModule(`await null; throw new Error("async boom");`, "rejects.mjs")
+	fatalIf(t, err)
+	fatalIf(t, mod.Instantiate(nil))
+	_, err = mod.EvaluateAndWait(context.Background())
+	var exc *v8.Exception
+	if !errors.As(err, &exc) || !strings.Contains(err.Error(), "async boom") {
+		t.Errorf("expected rejection, got %v", err)
+	}
+
+	mod, err = ctx.CompileModule(`await new Promise(() => {});`, "never.mjs")
+	fatalIf(t, err)
+	fatalIf(t, mod.Instantiate(nil))
+	goCtx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := mod.EvaluateAndWait(goCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestModuleErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	if _, err := ctx.CompileModule(`export const = 1;`, "syntax.mjs"); err == nil {
+		t.Error("expected syntax error, got <nil>")
+	}
+
+	mod, err := ctx.CompileModule(`import "missing.mjs";`, "main.mjs")
+	fatalIf(t, err)
+	if err := mod.Instantiate(nil); err == nil || !strings.Contains(err.Error(), "missing.mjs") {
+		t.Errorf("expected error resolving without a resolver, got %v", err)
+	}
+
+	mod, err = ctx.CompileModule(`throw new Error("boom");`, "throws.mjs")
+	fatalIf(t, err)
+	fatalIf(t, mod.Instantiate(nil))
+	if _, err := mod.Evaluate(); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("expected evaluation error, got %v", err)
+	}
+	if got := mod.Status(); got != v8.ModuleErrored {
+		t.Errorf("expected errored module, got status %v", got)
+	}
+}
+
+func TestContextEnableJSONModules(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	files := map[string]string{
+		"config.json":  `{"name": "app", "port": 8080}`,
+		"data":         `[1, 2, 3]`,
+		"invalid.json": `{"name": `,
+	}
+	loads := 0
+	fatalIf(t, ctx.EnableJSONModules(func(specifier string) ([]byte, error) {
+		loads++
+		src, ok := files[specifier]
+		if !ok {
+			return nil, fmt.Errorf("file %q not found", specifier)
+		}
+		return []byte(src), nil
+	}))
+
+	mod, err := ctx.CompileModule(`
+		import config from "config.json";
+		import again from "config.json" with { type: "json" };
+		import data from "data" with { type: "json" };
+		export const result = config.name + ":" + config.port + ":" + data.length + ":" + (config === again);
+	`, "main.mjs")
+	fatalIf(t, err)
+	fatalIf(t, mod.Instantiate(nil))
+	_, err = mod.Evaluate()
+	fatalIf(t, err)
+	ns, err := mod.Namespace()
+	fatalIf(t, err)
+	result, err := ns.Get("result")
+	fatalIf(t, err)
+	if got, want := result.String(), "app:8080:3:true"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if loads != 2 {
+		t.Errorf("expected each JSON module to be loaded once, got %d loads", loads)
+	}
+
+	mod, err = ctx.CompileModule(`import bad from "invalid.json";`, "bad.mjs")
+	fatalIf(t, err)
+	err = mod.Instantiate(nil)
+	if err == nil || !strings.Contains(err.Error(), `invalid JSON module "invalid.json": SyntaxError`) {
+		t.Errorf("expected JSON syntax error, got %v", err)
+	}
+
+	mod, err = ctx.CompileModule(`import missing from "missing.json";`, "missing.mjs")
+	fatalIf(t, err)
+	if err := mod.Instantiate(nil); err == nil || !strings.Contains(err.Error(), `file "missing.json" not found`) {
+		t.Errorf("expected load error, got %v", err)
+	}
+
+	// Another type attribute makes an import with a ".json" specifier not a
+	// JSON module, so it is left to the ModuleResolver, and the resolution
+	// error is thrown as an Error.
+	mod, err = ctx.CompileModule(`import config from "config.json" with { type: "css" };`, "plain.mjs")
+	fatalIf(t, err)
+	if err := mod.Instantiate(nil); err == nil || !strings.Contains(err.Error(), `Error: cannot resolve module "config.json": no module resolver`) {
+		t.Errorf("expected resolution error, got %v", err)
+	}
+
+	if err := ctx.EnableJSONModules(nil); err == nil {
+		t.Error("expected error with a nil loader, got <nil>")
+	}
+}
@@ -1,4 +1,5 @@
 #include "object.h"
+#include "deps/include/v8-container.h"
 #include "deps/include/v8-object.h"
 #include "isolate-macros.h"
 #include "utils.h"
@@ -74,6 +75,96 @@ RtnValue ObjectGet(ValuePtr ptr, const char* key) {
   return rtn;
 }
 
+RtnValue ObjectGetRealNamedProperty(ValuePtr ptr, const char* key) {
+  LOCAL_OBJECT(ptr);
+  RtnValue rtn = {};
+
+  Local<String> key_val;
+  if (!String::NewFromUtf8(iso, key, NewStringType::kNormal)
+           .ToLocal(&key_val)) {
+    rtn.error = ExceptionError(try_catch, iso, local_ctx);
+    return rtn;
+  }
+  Local<Value> result;
+  if (!obj->GetRealNamedProperty(local_ctx, key_val).ToLocal(&result)) {
+    if (try_catch.HasCaught()) {
+      rtn.error = ExceptionError(try_catch, iso, local_ctx);
+      return rtn;
+    }
+    // There is no real property with that name.
+    result = Undefined(iso);
+  }
+  m_value* new_val = new m_value;
+  new_val->id = 0;
+  new_val->iso = iso;
+  new_val->ctx = ctx;
+  new_val->ptr = Global<Value>(iso, result);
+
+  rtn.value = tracked_value(ctx, new_val);
+  return rtn;
+}
+
+RtnPropertyAttributes ObjectGetRealNamedPropertyAttributes(ValuePtr ptr,
+                                                           const char* key) {
+  LOCAL_OBJECT(ptr);
+  RtnPropertyAttributes rtn = {};
+
+  Local<String> key_val;
+  if (!String::NewFromUtf8(iso, key, NewStringType::kNormal)
+           .ToLocal(&key_val)) {
+    rtn.error = ExceptionError(try_catch, iso, local_ctx);
+    return rtn;
+  }
+  Maybe<PropertyAttribute> attributes =
+      obj->GetRealNamedPropertyAttributes(local_ctx, key_val);
+  if (try_catch.HasCaught()) {
+    rtn.error = ExceptionError(try_catch, iso, local_ctx);
+    return rtn;
+  }
+  if (attributes.IsJust()) {
+    rtn.attributes = attributes.FromJust();
+    rtn.found = 1;
+  }
+  return rtn;
+}
+
+static RtnPropertyAttributes PropertyAttributesResult(
+    Isolate* iso,
+    Local<Context> local_ctx,
+    TryCatch& try_catch,
+    Local<Object> obj,
+    Local<Value> key) {
+  RtnPropertyAttributes rtn = {};
+  PropertyAttribute attributes;
+  if (!obj->GetPropertyAttributes(local_ctx, key).To(&attributes)) {
+    rtn.error = ExceptionError(try_catch, iso, local_ctx);
+    return rtn;
+  }
+  rtn.attributes = attributes;
+  rtn.found = 1;
+  return rtn;
+}
+
+RtnPropertyAttributes ObjectGetPropertyAttributes(ValuePtr ptr,
+                                                  const char* key) {
+  LOCAL_OBJECT(ptr);
+  Local<String> key_val;
+  if (!String::NewFromUtf8(iso, key, NewStringType::kNormal)
+           .ToLocal(&key_val)) {
+    RtnPropertyAttributes rtn = {};
+    rtn.error = ExceptionError(try_catch, iso, local_ctx);
+    return rtn;
+  }
+  return PropertyAttributesResult(iso, local_ctx, try_catch, obj, key_val);
+}
+
+RtnPropertyAttributes ObjectGetPropertyAttributesIdx(ValuePtr ptr,
+                                                     uint32_t idx) {
+  LOCAL_OBJECT(ptr);
+  return PropertyAttributesResult(iso, local_ctx, try_catch, obj,
+                                  Integer::NewFromUnsigned(iso, idx));
+}
+
 RtnValue ObjectGetAnyKey(ValuePtr ptr, ValuePtr key) {
   LOCAL_OBJECT(ptr);
   RtnValue rtn = {};
@@ -173,3 +264,113 @@ int ObjectDeleteIdx(ValuePtr ptr, uint32_t idx) {
   LOCAL_OBJECT(ptr);
   return obj->Delete(local_ctx, idx).ToChecked();
 }
+
+const char* ObjectGetConstructorName(ValuePtr ptr) {
+  LOCAL_OBJECT(ptr);
+  String::Utf8Value name(iso, obj->GetConstructorName());
+  return CopyString(name);
+}
+
+ValuePtr ObjectClone(ValuePtr ptr) {
+  LOCAL_OBJECT(ptr);
+  m_value* new_val = new m_value;
+  new_val->id = 0;
+  new_val->iso = iso;
+  new_val->ctx = ctx;
+  new_val->ptr = Global<Value>(iso, obj->Clone());
+  return tracked_value(ctx, new_val);
+}
+
+RtnValue ObjectPreviewEntries(ValuePtr ptr, int* is_key_value) {
+  LOCAL_OBJECT(ptr);
+  RtnValue rtn = {};
+
+  bool key_value = false;
+  Local<Array> entries;
+  if (!obj->PreviewEntries(&key_value).ToLocal(&entries)) {
+    if (try_catch.HasCaught()) {
+      rtn.error = ExceptionError(try_catch, iso, local_ctx);
+    } else {
+      rtn.error.msg = CopyString("object has no entries to preview");
+    }
+    return rtn;
+  }
+  *is_key_value = key_value;
+
+  m_value* new_val = new m_value;
+  new_val->id = 0;
+  new_val->iso = iso;
+  new_val->ctx = ctx;
+  new_val->ptr = Global<Value>(iso, entries);
+  rtn.value = tracked_value(ctx, new_val);
+  return rtn;
+}
+
+RtnValue ObjectGetOwnPropertyNames(ValuePtr ptr) {
+  LOCAL_OBJECT(ptr);
+  RtnValue rtn = {};
+
+  Local<Array> names;
+  if (!obj->GetOwnPropertyNames(
+              local_ctx,
+              static_cast<PropertyFilter>(ONLY_ENUMERABLE | SKIP_SYMBOLS),
+              KeyConversionMode::kConvertToString)
+           .ToLocal(&names)) {
+    rtn.error = ExceptionError(try_catch, iso, local_ctx);
+    return rtn;
+  }
+
+  m_value* new_val = new m_value;
+  new_val->id = 0;
+  new_val->iso = iso;
+  new_val->ctx = ctx;
+  new_val->ptr = Global<Value>(iso, names);
+
+  rtn.value = tracked_value(ctx, new_val);
+  return rtn;
+}
+
+RtnValue ObjectGetPrototype(ValuePtr ptr) {
+  LOCAL_OBJECT(ptr);
+  RtnValue rtn = {};
+
+  m_value* new_val = new m_value;
+  new_val->id = 0;
+  new_val->iso = iso;
+  new_val->ctx = ctx;
+  new_val->ptr = Global<Value>(iso, obj->GetPrototype());
+
+  rtn.value = tracked_value(ctx, new_val);
+  return rtn;
+}
+
+RtnError ObjectSetPrototype(ValuePtr ptr, ValuePtr proto_ptr) {
+  LOCAL_OBJECT(ptr);
+  RtnError rtn = {};
+
+  Local<Value> proto = proto_ptr->ptr.Get(iso);
+  Maybe<bool> set = obj->SetPrototype(local_ctx, proto);
+  if (set.IsNothing()) {
+    return ExceptionError(try_catch, iso, local_ctx);
+  }
+  if (!set.FromJust()) {
+    rtn.msg = CopyString("TypeError: Cannot set the prototype of object");
+  }
+  return rtn;
+}
+
+RtnError ObjectSetIntegrityLevel(ValuePtr ptr, int level) {
+  LOCAL_OBJECT(ptr);
+  RtnError rtn = {};
+
+  Maybe<bool> set =
+      obj->SetIntegrityLevel(local_ctx, static_cast<IntegrityLevel>(level));
+  if (set.IsNothing()) {
+    return ExceptionError(try_catch, iso, local_ctx);
+  }
+  if (!set.FromJust()) {
+    rtn.msg =
+        CopyString("TypeError: Cannot change the integrity level of object");
+  }
+  return rtn;
+}
@@ -5,9 +5,11 @@
 package v8go
 
 // #include <stdlib.h>
+// #include "context.h"
 // #include "object.h"
 import "C"
 import (
+	"errors"
 	"fmt"
 	"math/big"
 	"unsafe"
@@ -18,11 +20,24 @@ type Object struct {
 	*Value
 }
 
+// IntegrityLevel is the level of protection set by Object.SetIntegrityLevel.
+type IntegrityLevel int
+
+// These values match v8::IntegrityLevel.
+const (
+	// IntegrityLevelFrozen makes all properties read-only and
+	// non-configurable, like `Object.freeze(obj)`.
+	IntegrityLevelFrozen IntegrityLevel = iota
+	// IntegrityLevelSealed makes all properties non-configurable, like
+	// `Object.seal(obj)`.
+	IntegrityLevelSealed
+)
+
 func (o *Object) MethodCall(methodName string, args ...Valuer) (*Value, error) {
 	ckey := C.CString(methodName)
 	defer C.free(unsafe.Pointer(ckey))
 
-	getRtn := C.ObjectGet(o.ptr, ckey)
+	getRtn := C.ObjectGet(o.valuePtr(), ckey)
 	prop, err := valueResult(o.ctx, getRtn)
 	if err != nil {
 		return nil, err
@@ -60,7 +75,7 @@ func (o *Object) Set(key string, val interface{}) error {
 
 	ckey := C.CString(key)
 	defer C.free(unsafe.Pointer(ckey))
-	C.ObjectSet(o.ptr, ckey, value.ptr)
+	C.ObjectSet(o.valuePtr(), ckey, value.valuePtr())
 	return nil
 }
 
@@ -74,7 +89,7 @@ func (o *Object) SetSymbol(key *Symbol, val interface{}) error {
 		return err
 	}
 
-	C.ObjectSetAnyKey(o.ptr, key.ptr, value.ptr)
+	C.ObjectSetAnyKey(o.valuePtr(), key.valuePtr(), value.valuePtr())
 	return nil
 }
 
@@ -88,7 +103,7 @@ func (o *Object) SetIdx(idx uint32, val interface{}) error {
 		return err
 	}
 
-	C.ObjectSetIdx(o.ptr, C.uint32_t(idx), value.ptr)
+	C.ObjectSetIdx(o.valuePtr(), C.uint32_t(idx), value.valuePtr())
 
 	return nil
 }
@@ -102,7 +117,7 @@ func (o *Object) SetInternalField(idx uint32, val interface{}) error {
 		return err
 	}
 
-	inserted := C.ObjectSetInternalField(o.ptr, C.int(idx), value.ptr)
+	inserted := C.ObjectSetInternalField(o.valuePtr(), C.int(idx), value.valuePtr())
 
 	if inserted == 0 {
 		panic(fmt.Errorf("index out of range [%v] with length %v", idx, o.InternalFieldCount()))
@@ -113,7 +128,7 @@ func (o *Object) SetInternalField(idx uint32, val interface{}) error {
 
 // InternalFieldCount returns the number of internal fields this Object has.
 func (o *Object) InternalFieldCount() uint32 {
-	count := C.ObjectInternalFieldCount(o.ptr)
+	count := C.ObjectInternalFieldCount(o.valuePtr())
 	return uint32(count)
 }
 
@@ -122,13 +137,86 @@ func (o *Object) Get(key string) (*Value, error) {
 	ckey := C.CString(key)
 	defer C.free(unsafe.Pointer(ckey))
 
-	rtn := C.ObjectGet(o.ptr, ckey)
+	rtn := C.ObjectGet(o.valuePtr(), ckey)
+	return valueResult(o.ctx, rtn)
+}
+
+// GetRealNamedProperty gets the property key of the object, or of its
+// prototype chain, without calling interceptors, i.e. the named property
+// handlers of an ObjectTemplate, so it reads the property the object really
+// has even if an interceptor would report a different one. Accessor
+// properties defined in JS are not data properties, and reading them still
+// calls their getter; check GetRealNamedPropertyAttributes or the property
+// descriptor first if a getter must not run. It returns undefined if there is
+// no such property.
+//
+// This corresponds to Object::GetRealNamedProperty in the C++ API.
+func (o *Object) GetRealNamedProperty(key string) (*Value, error) {
+	ckey := C.CString(key)
+	defer C.free(unsafe.Pointer(ckey))
+
+	rtn := C.ObjectGetRealNamedProperty(o.valuePtr(), ckey)
+	return valueResult(o.ctx, rtn)
+}
+
+// GetRealNamedPropertyAttributes returns the attributes of the property key
+// of the object, or of its prototype chain, without calling interceptors, and
+// whether there is such a property at all, which tells a missing property
+// from one holding undefined. Reading the attributes never calls a getter.
+//
+// This corresponds to Object::GetRealNamedPropertyAttributes in the C++ API.
+func (o *Object) GetRealNamedPropertyAttributes(key string) (attributes PropertyAttribute, ok bool, err error) {
+	ckey := C.CString(key)
+	defer C.free(unsafe.Pointer(ckey))
+
+	rtn := C.ObjectGetRealNamedPropertyAttributes(o.valuePtr(), ckey)
+	if rtn.error.msg != nil {
+		return None, false, o.ctx.resultError(rtn.error)
+	}
+	return PropertyAttribute(rtn.attributes), rtn.found != 0, nil
+}
+
+// GetPropertyAttributes returns the attributes of the property key of the
+// object, or of its prototype chain, e.g. to check that it isn't ReadOnly
+// before setting it. This is cheaper than reading the whole property
+// descriptor. A missing property has the attributes None; use Has to tell it
+// apart from a writable, enumerable and configurable one. Unlike
+// GetRealNamedPropertyAttributes, interceptors and proxy traps are called.
+//
+// This corresponds to Object::GetPropertyAttributes in the C++ API.
+func (o *Object) GetPropertyAttributes(key string) (PropertyAttribute, error) {
+	ckey := C.CString(key)
+	defer C.free(unsafe.Pointer(ckey))
+
+	rtn := C.ObjectGetPropertyAttributes(o.valuePtr(), ckey)
+	if rtn.error.msg != nil {
+		return None, o.ctx.resultError(rtn.error)
+	}
+	return PropertyAttribute(rtn.attributes), nil
+}
+
+// GetPropertyAttributesIdx returns the attributes of the element at index idx,
+// like GetPropertyAttributes.
+func (o *Object) GetPropertyAttributesIdx(idx uint32) (PropertyAttribute, error) {
+	rtn := C.ObjectGetPropertyAttributesIdx(o.valuePtr(), C.uint32_t(idx))
+	if rtn.error.msg != nil {
+		return None, o.ctx.resultError(rtn.error)
+	}
+	return PropertyAttribute(rtn.attributes), nil
+}
+
+// GetKey tries to get a Value for a given Object property key, which may be
+// any value such as a string, number or symbol. When reading the same property
+// from many objects, pass a key created once with NewPropertyKey; this avoids
+// converting and looking up the key name on every call.
+func (o *Object) GetKey(key Valuer) (*Value, error) {
+	rtn := C.ObjectGetAnyKey(o.valuePtr(), key.value().valuePtr())
 	return valueResult(o.ctx, rtn)
 }
 
 // GetSymbol tries to get a Value for a given Object property key.
 func (o *Object) GetSymbol(key *Symbol) (*Value, error) {
-	rtn := C.ObjectGetAnyKey(o.ptr, key.ptr)
+	rtn := C.ObjectGetAnyKey(o.valuePtr(), key.valuePtr())
 	return valueResult(o.ctx, rtn)
 }
 
@@ -137,16 +225,16 @@ func (o *Object) GetSymbol(key *Symbol) (*Value, error) {
 // Panics if given an out of range index, or the field contains a Data other
 // than a Value.
 func (o *Object) GetInternalField(idx uint32) *Value {
-	rtn := C.ObjectGetInternalField(o.ptr, C.int(idx))
+	rtn := C.ObjectGetInternalField(o.valuePtr(), C.int(idx))
 	if rtn.value == nil {
-		panic(newJSError(rtn.error))
+		panic(o.ctx.resultError(rtn.error))
 	}
-	return &Value{rtn.value, o.ctx}
+	return &Value{ptr: rtn.value, ctx: o.ctx}
 }
 
 // GetIdx tries to get a Value at a give Object index.
 func (o *Object) GetIdx(idx uint32) (*Value, error) {
-	rtn := C.ObjectGetIdx(o.ptr, C.uint32_t(idx))
+	rtn := C.ObjectGetIdx(o.valuePtr(), C.uint32_t(idx))
 	return valueResult(o.ctx, rtn)
 }
 
@@ -155,33 +243,178 @@ func (o *Object) GetIdx(idx uint32) (*Value, error) {
 func (o *Object) Has(key string) bool {
 	ckey := C.CString(key)
 	defer C.free(unsafe.Pointer(ckey))
-	return C.ObjectHas(o.ptr, ckey) != 0
+	return C.ObjectHas(o.valuePtr(), ckey) != 0
 }
 
 // HasSymbol calls the abstract operation HasProperty(O, P) described in ECMA-262, 7.3.10.
 // Returns true, if the object has the property, either own or on the prototype chain.
 func (o *Object) HasSymbol(key *Symbol) bool {
-	return C.ObjectHasAnyKey(o.ptr, key.ptr) != 0
+	return C.ObjectHasAnyKey(o.valuePtr(), key.valuePtr()) != 0
 }
 
 // HasIdx returns true if the object has a value at the given index.
 func (o *Object) HasIdx(idx uint32) bool {
-	return C.ObjectHasIdx(o.ptr, C.uint32_t(idx)) != 0
+	return C.ObjectHasIdx(o.valuePtr(), C.uint32_t(idx)) != 0
 }
 
 // Delete returns true if successful in deleting a named property on the object.
 func (o *Object) Delete(key string) bool {
 	ckey := C.CString(key)
 	defer C.free(unsafe.Pointer(ckey))
-	return C.ObjectDelete(o.ptr, ckey) != 0
+	return C.ObjectDelete(o.valuePtr(), ckey) != 0
 }
 
 // DeleteSymbol returns true if successful in deleting a named property on the object.
 func (o *Object) DeleteSymbol(key *Symbol) bool {
-	return C.ObjectDeleteAnyKey(o.ptr, key.ptr) != 0
+	return C.ObjectDeleteAnyKey(o.valuePtr(), key.valuePtr()) != 0
 }
 
 // DeleteIdx returns true if successful in deleting a value at a given index of the object.
 func (o *Object) DeleteIdx(idx uint32) bool {
-	return C.ObjectDeleteIdx(o.ptr, C.uint32_t(idx)) != 0
+	return C.ObjectDeleteIdx(o.valuePtr(), C.uint32_t(idx)) != 0
+}
+
+// GetConstructorName returns the name of the function invoked as a
+// constructor for this object, e.g. "Date" or the name of a user defined
+// class.
+func (o *Object) GetConstructorName() string {
+	s := C.ObjectGetConstructorName(o.valuePtr())
+	defer C.free(unsafe.Pointer(s))
+	return C.GoString(s)
+}
+
+// Clone returns a shallow copy of the object: own properties are copied, but
+// values that are objects are shared with the original.
+func (o *Object) Clone() *Object {
+	return &Object{&Value{ptr: C.ObjectClone(o.valuePtr()), ctx: o.ctx}}
+}
+
+// StructuredClone returns a deep copy of the object using the HTML structured
+// clone algorithm, the same as `structuredClone(obj)` in a browser. Dates,
+// RegExps, Maps, Sets, ArrayBuffers, typed arrays and cyclic references are
+// preserved. An error is returned if the object contains values that can't be
+// cloned, such as functions or symbols.
+func (o *Object) StructuredClone() (*Value, error) {
+	return structuredClone(o.ctx, o.Value, nil)
+}
+
+// PreviewEntries returns the entries of a Map, Set, WeakMap or WeakSet, or the
+// remaining entries of a Map or Set iterator, without consuming the iterator.
+// This is how DevTools previews collections. If isKeyValue is true, entries
+// holds each key followed by its value; otherwise it holds only the keys of a
+// Map iterator, or the values otherwise. An error is returned for any other
+// kind of object.
+func (o *Object) PreviewEntries() (entries []*Value, isKeyValue bool, err error) {
+	var cIsKeyValue C.int
+	rtn := C.ObjectPreviewEntries(o.valuePtr(), &cIsKeyValue)
+	arr, err := objectResult(o.ctx, rtn)
+	if err != nil {
+		return nil, false, err
+	}
+	defer arr.Release()
+	entries, err = (&Array{arr}).Slice()
+	if err != nil {
+		return nil, false, err
+	}
+	return entries, cIsKeyValue != 0, nil
+}
+
+// SetIntegrityLevel freezes or seals the object, which also prevents new
+// properties from being added to it.
+func (o *Object) SetIntegrityLevel(level IntegrityLevel) error {
+	rtn := C.ObjectSetIntegrityLevel(o.valuePtr(), C.int(level))
+	if rtn.msg != nil {
+		return o.ctx.resultError(rtn)
+	}
+	return nil
+}
+
+// GetOwnPropertyNames returns the names of the own enumerable properties of
+// the object, like `Object.keys(obj)`, with integer indices converted to
+// strings. Symbol-keyed properties are not included.
+func (o *Object) GetOwnPropertyNames() ([]string, error) {
+	rtn := C.ObjectGetOwnPropertyNames(o.valuePtr())
+	val, err := valueResult(o.ctx, rtn)
+	if err != nil {
+		return nil, err
+	}
+	defer val.Release()
+	arr, err := val.AsArray()
+	if err != nil {
+		return nil, err
+	}
+	keys, err := arr.Slice()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		names[i] = key.String()
+	}
+	releaseValues(keys)
+	return names, nil
+}
+
+// GetPrototype returns the prototype of the object, like
+// `Object.getPrototypeOf(obj)`, which is null at the end of the prototype
+// chain.
+func (o *Object) GetPrototype() (*Value, error) {
+	rtn := C.ObjectGetPrototype(o.valuePtr())
+	return valueResult(o.ctx, rtn)
+}
+
+// SetPrototype sets the prototype of the object, like
+// `Object.setPrototypeOf(obj, proto)`. proto must be an object or null; e.g.
+// an instance of an ObjectTemplate created with ObjectTemplate.NewInstance
+// gives the object the native methods of the template. An error is returned if
+// the prototype can't be changed, e.g. because the object isn't extensible or
+// the prototype chain would contain a cycle.
+func (o *Object) SetPrototype(proto Valuer) error {
+	if proto == nil {
+		return errors.New("v8go: prototype must be an object or null")
+	}
+	p := proto.value()
+	if !p.IsObject() && !p.IsNull() {
+		return errors.New("v8go: prototype must be an object or null")
+	}
+	rtn := C.ObjectSetPrototype(o.valuePtr(), p.valuePtr())
+	if rtn.msg != nil {
+		return o.ctx.resultError(rtn)
+	}
+	return nil
+}
+
+// IsExtensible reports whether new properties can be added to the object,
+// like `Object.isExtensible(obj)`. An error is returned if the object is a
+// Proxy whose isExtensible trap throws.
+func (o *Object) IsExtensible() (bool, error) {
+	val, err := o.callObjectBuiltin(C.INTRINSIC_OBJECT_IS_EXTENSIBLE)
+	if err != nil {
+		return false, err
+	}
+	defer val.Release()
+	return val.Boolean(), nil
+}
+
+// PreventExtensions prevents new properties from being added to the object,
+// like `Object.preventExtensions(obj)`.
+func (o *Object) PreventExtensions() error {
+	val, err := o.callObjectBuiltin(C.INTRINSIC_OBJECT_PREVENT_EXTENSIONS)
+	if err != nil {
+		return err
+	}
+	val.Release()
+	return nil
+}
+
+// callObjectBuiltin calls a static method of the Object constructor with o as
+// its argument, as V8 has no API for these operations. The method is the
+// intrinsic of the context, so scripts can't intercept the call by replacing
+// Object or its methods.
+func (o *Object) callObjectBuiltin(method C.IntrinsicIndex) (*Value, error) {
+	fn, err := o.ctx.intrinsic(method)
+	if err != nil {
+		return nil, err
+	}
+	return fn.Call(Undefined(o.ctx.iso), o)
 }
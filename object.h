@@ -10,6 +10,12 @@
 extern "C" {
 #endif
 
+typedef struct {
+  int attributes;
+  int found;
+  RtnError error;
+} RtnPropertyAttributes;
+
 extern void ObjectSet(ValuePtr ptr, const char* key, ValuePtr val_ptr);
 extern void ObjectSetAnyKey(ValuePtr ptr, ValuePtr key, ValuePtr val_ptr);
 extern void ObjectSetIdx(ValuePtr ptr, uint32_t idx, ValuePtr val_ptr);
@@ -19,6 +25,14 @@ extern int ObjectInternalFieldCount(ValuePtr ptr);
 extern RtnValue ObjectGet(ValuePtr ptr, const char* key);
 extern RtnValue ObjectGetAnyKey(ValuePtr ptr, ValuePtr key);
 extern RtnValue ObjectGetIdx(ValuePtr ptr, uint32_t idx);
+extern RtnValue ObjectGetRealNamedProperty(ValuePtr ptr, const char* key);
+extern RtnPropertyAttributes ObjectGetRealNamedPropertyAttributes(
+    ValuePtr ptr,
+    const char* key);
+extern RtnPropertyAttributes ObjectGetPropertyAttributes(ValuePtr ptr,
+                                                         const char* key);
+extern RtnPropertyAttributes ObjectGetPropertyAttributesIdx(ValuePtr ptr,
+                                                            uint32_t idx);
 extern RtnValue ObjectGetInternalField(ValuePtr ptr, int idx);
 int ObjectHas(ValuePtr ptr, const char* key);
 int ObjectHasAnyKey(ValuePtr ptr, ValuePtr key);
@@ -26,6 +40,13 @@ int ObjectHasIdx(ValuePtr ptr, uint32_t idx);
 int ObjectDelete(ValuePtr ptr, const char* key);
 int ObjectDeleteAnyKey(ValuePtr ptr, ValuePtr key);
 int ObjectDeleteIdx(ValuePtr ptr, uint32_t idx);
+const char* ObjectGetConstructorName(ValuePtr ptr);
+extern ValuePtr ObjectClone(ValuePtr ptr);
+extern RtnValue ObjectPreviewEntries(ValuePtr ptr, int* is_key_value);
+extern RtnError ObjectSetIntegrityLevel(ValuePtr ptr, int level);
+extern RtnValue ObjectGetOwnPropertyNames(ValuePtr ptr);
+extern RtnValue ObjectGetPrototype(ValuePtr ptr);
+extern RtnError ObjectSetPrototype(ValuePtr ptr, ValuePtr proto_ptr);
 
 #ifdef __cplusplus
 }  // extern "C"
@@ -1,15 +1,19 @@
+#include "_cgo_export.h"
+
 #include "object_template.h"
 #include "context.h"
 #include "deps/include/v8-context.h"
+#include "deps/include/v8-exception.h"
 #include "deps/include/v8-isolate.h"
 #include "deps/include/v8-locker.h"
 #include "deps/include/v8-template.h"
 #include "template-macros.h"
+#include "value.h"
 
 using namespace v8;
 
 TemplatePtr NewObjectTemplate(v8Isolate* iso) {
-  Locker locker(iso);
+  IsolateLocker locker(iso);
   Isolate::Scope isolate_scope(iso);
   HandleScope handle_scope(iso);
 
@@ -57,6 +61,13 @@ int ObjectTemplateInternalFieldCount(TemplatePtr ptr) {
   return obj_tmpl->InternalFieldCount();
 }
 
+void ObjectTemplateMarkAsUndetectable(TemplatePtr ptr) {
+  LOCAL_TEMPLATE(ptr);
+
+  Local<ObjectTemplate> obj_tmpl = tmpl.As<ObjectTemplate>();
+  obj_tmpl->MarkAsUndetectable();
+}
+
 void ObjectTemplateSetAccessorProperty(TemplatePtr ptr,
                                        const char* key,
                                        TemplatePtr get,
@@ -77,3 +88,145 @@ void ObjectTemplateSetAccessorProperty(TemplatePtr ptr,
   return obj_tmpl->SetAccessorProperty(key_val, get_tmpl, set_tmpl,
                                        (PropertyAttribute)attributes);
 }
+
+// Calls the Go callback registered as the integer data of a property
+// callback like a function, with the object the property belongs to as the
+// receiver and value, if not empty, as its only argument. Returns false if the
+// callback threw or the context was closed, and sets result to its return
+// value otherwise.
+static bool ObjectTemplatePropertyCallback(Isolate* iso,
+                                           Local<Object> self,
+                                           Local<Value> data,
+                                           Local<Value> value,
+                                           Local<Value>* result) {
+  Local<Context> local_ctx = iso->GetCurrentContext();
+  int ctx_ref = local_ctx->GetEmbedderData(1).As<Integer>()->Value();
+  m_ctx* ctx = goContext(ctx_ref);
+  if (ctx == nullptr) {
+    // The context was closed, so there's no callback to call.
+    return false;
+  }
+  int callback_ref = data.As<Integer>()->Value();
+
+  ValuePtr this_and_args[2];
+  int args_count = 0;
+  m_value* _this = new m_value;
+  _this->id = 0;
+  _this->iso = iso;
+  _this->ctx = ctx;
+  _this->ptr = Global<Value>(iso, self);
+  this_and_args[0] = tracked_value(ctx, _this);
+  if (!value.IsEmpty()) {
+    m_value* arg = new m_value;
+    arg->id = 0;
+    arg->iso = iso;
+    arg->ctx = ctx;
+    arg->ptr = Global<Value>(iso, value);
+    this_and_args[1] = tracked_value(ctx, arg);
+    args_count = 1;
+  }
+
+  goFunctionCallback_return retval =
+      goFunctionCallback(ctx_ref, callback_ref, this_and_args, args_count);
+  if (retval.r1 != nullptr) {
+    iso->ThrowException(retval.r1->ptr.Get(iso));
+    return false;
+  }
+  if (retval.r0 != nullptr) {
+    *result = retval.r0->ptr.Get(iso);
+  } else {
+    *result = Undefined(iso);
+  }
+  return true;
+}
+
+static void ObjectTemplateDataGetter(Local<Name> property,
+                                     const PropertyCallbackInfo<Value>& info) {
+  // The getter is called like a function without arguments, with the object
+  // the property was read from as the receiver.
+  Local<Value> result;
+  if (ObjectTemplatePropertyCallback(info.GetIsolate(), info.This(),
+                                     info.Data(), Local<Value>(), &result)) {
+    info.GetReturnValue().Set(result);
+  }
+}
+
+void ObjectTemplateSetLazyDataProperty(TemplatePtr ptr,
+                                       const char* key,
+                                       int callback_ref) {
+  LOCAL_TEMPLATE(ptr);
+
+  Local<String> key_val =
+      String::NewFromUtf8(iso, key, NewStringType::kNormal).ToLocalChecked();
+  Local<ObjectTemplate> obj_tmpl = tmpl.As<ObjectTemplate>();
+  obj_tmpl->SetLazyDataProperty(key_val, ObjectTemplateDataGetter,
+                                Integer::New(iso, callback_ref));
+}
+
+// Defined in function_template.cc; calls the Go callback registered as the
+// integer data of the call.
+void FunctionTemplateCallback(const FunctionCallbackInfo<Value>& info);
+
+void ObjectTemplateSetCallAsFunctionHandler(TemplatePtr ptr,
+                                            int callback_ref) {
+  LOCAL_TEMPLATE(ptr);
+
+  Local<ObjectTemplate> obj_tmpl = tmpl.As<ObjectTemplate>();
+  obj_tmpl->SetCallAsFunctionHandler(FunctionTemplateCallback,
+                                     Integer::New(iso, callback_ref));
+}
+
+static bool ObjectTemplateAccessCheck(Local<Context> accessing_context,
+                                      Local<Object> accessed_object,
+                                      Local<Value> data) {
+  Isolate* iso = accessing_context->GetIsolate();
+  int ctx_ref = accessing_context->GetEmbedderData(1).As<Integer>()->Value();
+  m_ctx* ctx = goContext(ctx_ref);
+  if (ctx == nullptr) {
+    // The accessing context was closed, so there's nothing to allow access.
+    return false;
+  }
+  int callback_ref = data.As<Integer>()->Value();
+
+  m_value* accessed = new m_value;
+  accessed->id = 0;
+  accessed->iso = iso;
+  accessed->ctx = ctx;
+  accessed->ptr = Global<Value>(iso, accessed_object);
+
+  return goAccessCheckCallback(ctx_ref, callback_ref,
+                               tracked_value(ctx, accessed)) != 0;
+}
+
+void ObjectTemplateSetAccessCheckCallback(TemplatePtr ptr, int callback_ref) {
+  LOCAL_TEMPLATE(ptr);
+
+  Local<ObjectTemplate> obj_tmpl = tmpl.As<ObjectTemplate>();
+  obj_tmpl->SetAccessCheckCallback(ObjectTemplateAccessCheck,
+                                   Integer::New(iso, callback_ref));
+}
+
+static void ObjectTemplateNativeDataSetter(
+    Local<Name> property,
+    Local<Value> value,
+    const PropertyCallbackInfo<void>& info) {
+  Local<Value> result;
+  ObjectTemplatePropertyCallback(info.GetIsolate(), info.This(), info.Data(),
+                                 value, &result);
+}
+
+void ObjectTemplateSetNativeDataProperty(TemplatePtr ptr,
+                                         const char* key,
+                                         int callback_ref,
+                                         int has_setter,
+                                         int attributes) {
+  LOCAL_TEMPLATE(ptr);
+
+  Local<String> key_val =
+      String::NewFromUtf8(iso, key, NewStringType::kNormal).ToLocalChecked();
+  Local<ObjectTemplate> obj_tmpl = tmpl.As<ObjectTemplate>();
+  obj_tmpl->SetNativeDataProperty(
+      key_val, ObjectTemplateDataGetter,
+      has_setter ? ObjectTemplateNativeDataSetter : nullptr,
+      Integer::New(iso, callback_ref), (PropertyAttribute)attributes);
+}
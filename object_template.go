@@ -22,13 +22,26 @@ const (
 	// None.
 	None PropertyAttribute = 0
 	// ReadOnly, ie. not writable.
-	ReadOnly PropertyAttribute = 1 << iota
+	ReadOnly PropertyAttribute = 1 << (iota - 1)
 	// DontEnum, ie. not enumerable.
 	DontEnum
 	// DontDelete, ie. not configurable.
 	DontDelete
 )
 
+// AccessCheckCallback is called when an object created from an ObjectTemplate
+// with an access check is accessed from a context that is not allowed access
+// by security token. accessing is the context the access originates from,
+// accessed is the object being accessed and data is the value given to
+// SetAccessCheckCallback. Returning false denies the access, which throws a
+// TypeError in the accessing context.
+type AccessCheckCallback func(accessing *Context, accessed *Object, data *Value) bool
+
+type accessCheck struct {
+	cb   AccessCheckCallback
+	data *Value
+}
+
 // ObjectTemplate is used to create objects at runtime.
 // Properties added to an ObjectTemplate are added to each object created from the ObjectTemplate.
 type ObjectTemplate struct {
@@ -50,12 +63,20 @@ func NewObjectTemplate(iso *Isolate) *ObjectTemplate {
 	return &ObjectTemplate{tmpl}
 }
 
-// NewInstance creates a new Object based on the template.
+// NewInstance creates a new Object based on the template, with the
+// properties, accessors and interceptors configured on it, e.g. to set as a
+// global or return from a function. This corresponds to
+// ObjectTemplate::NewInstance in the C++ API. ctx must belong to the isolate
+// of the template.
 func (o *ObjectTemplate) NewInstance(ctx *Context) (*Object, error) {
 	if ctx == nil {
 		return nil, errors.New("v8go: Context cannot be <nil>")
 	}
+	if ctx.iso != o.iso {
+		return nil, errors.New("v8go: Context belongs to a different isolate than the template")
+	}
 
+	o.escaped = true
 	rtn := C.ObjectTemplateNewInstance(o.ptr, ctx.ptr)
 	runtime.KeepAlive(o)
 	return objectResult(ctx, rtn)
@@ -67,6 +88,45 @@ func (o *ObjectTemplate) SetInternalFieldCount(fieldCount uint32) {
 	C.ObjectTemplateSetInternalFieldCount(o.ptr, C.int(fieldCount))
 }
 
+// MarkAsUndetectable marks objects created from the template as undetectable,
+// like `document.all` in browsers: they are falsy, `typeof` returns
+// "undefined" for them and they compare loosely equal to null and undefined,
+// but their properties can still be accessed as on other objects. It must be
+// called before the template is used to create an object.
+//
+// V8 requires undetectable objects to be callable, so an error is returned if
+// SetCallAsFunctionHandler has not been called on the template.
+//
+// This corresponds to ObjectTemplate::MarkAsUndetectable in the C++ API.
+func (o *ObjectTemplate) MarkAsUndetectable() error {
+	if o.cbref == 0 {
+		return errors.New("v8go: an undetectable ObjectTemplate requires a call as function handler")
+	}
+	C.ObjectTemplateMarkAsUndetectable(o.ptr)
+	return nil
+}
+
+// SetCallAsFunctionHandler makes objects created from the template callable,
+// calling callback with the object as the receiver and the arguments of the
+// call, e.g. for `document.all("id")`. Calling the object with `new` also
+// calls callback.
+//
+// This corresponds to ObjectTemplate::SetCallAsFunctionHandler in the C++
+// API.
+func (o *ObjectTemplate) SetCallAsFunctionHandler(callback FunctionCallbackWithError) {
+	if callback == nil {
+		panic("nil FunctionCallbackWithError argument not supported")
+	}
+	cbref := o.iso.registerCallback(callback)
+	C.ObjectTemplateSetCallAsFunctionHandler(o.ptr, C.int(cbref))
+	// As for SetAccessCheckCallback, the replaced handler can only still be
+	// invoked through objects created from the template.
+	if o.cbref != 0 && !o.escaped {
+		o.iso.unregisterCallback(o.cbref)
+	}
+	o.cbref = cbref
+}
+
 // SetAccessorProperty creates a named accessor property, i.e., a property that
 // is implemented as a function call. Arguments get and set represents the
 // getter and setter, and can both be nil.
@@ -88,14 +148,103 @@ func (o *ObjectTemplate) SetAccessorProperty(
 		setter C.TemplatePtr
 	)
 	if get != nil {
+		get.escaped = true
 		getter = get.ptr
 	}
 	if set != nil {
+		set.escaped = true
 		setter = set.ptr
 	}
 	C.ObjectTemplateSetAccessorProperty(o.ptr, ckey, getter, setter, C.int(attributes))
 }
 
+// SetLazyDataProperty creates a data property whose value is computed by
+// getter the first time the property is read on an object created from the
+// template. V8 then replaces the property with the returned value, so getter
+// runs at most once per object, and never for objects whose property is not
+// read. This suits expensive values that most scripts don't use, such as a
+// large lookup table. getter is called without arguments, with the object as
+// the receiver. The property is writable, enumerable and configurable.
+//
+// This corresponds to Template::SetLazyDataProperty in the C++ API.
+func (o *ObjectTemplate) SetLazyDataProperty(key string, getter FunctionCallback) {
+	if getter == nil {
+		panic("nil FunctionCallback argument not supported")
+	}
+	ckey := C.CString(key)
+	defer C.free(unsafe.Pointer(ckey))
+	cbref := o.iso.registerCallback(func(info *FunctionCallbackInfo) (*Value, error) {
+		return getter(info), nil
+	})
+	C.ObjectTemplateSetLazyDataProperty(o.ptr, ckey, C.int(cbref))
+}
+
+// SetNativeDataProperty creates a property that looks like a data property to
+// scripts, e.g. to Object.getOwnPropertyDescriptor, but whose value is
+// computed by getter on every read. setter, which may be nil, is called with
+// the assigned value as its only argument, and its return value is ignored;
+// without a setter, assignments are ignored, or throw in strict mode if
+// attributes include [ReadOnly]. Both are called with the object as the
+// receiver. Unlike SetAccessorProperty, no JS functions are created for the
+// callbacks, and V8 can call them directly from optimized code, which suits
+// frequently read native state.
+//
+// This corresponds to Template::SetNativeDataProperty in the C++ API.
+func (o *ObjectTemplate) SetNativeDataProperty(
+	key string,
+	getter FunctionCallback,
+	setter FunctionCallback,
+	attributes PropertyAttribute,
+) {
+	if getter == nil {
+		panic("nil FunctionCallback argument not supported")
+	}
+	ckey := C.CString(key)
+	defer C.free(unsafe.Pointer(ckey))
+	// The getter and setter share a callback, told apart by the value
+	// argument that only the setter receives.
+	cbref := o.iso.registerCallback(func(info *FunctionCallbackInfo) (*Value, error) {
+		if len(info.Args()) == 0 {
+			return getter(info), nil
+		}
+		setter(info)
+		return nil, nil
+	})
+	var hasSetter C.int
+	if setter != nil {
+		hasSetter = 1
+	}
+	C.ObjectTemplateSetNativeDataProperty(o.ptr, ckey, C.int(cbref), hasSetter, C.int(attributes))
+}
+
+// SetAccessCheckCallback sets a callback that decides whether objects created
+// from this template may be accessed from another context. data is passed to
+// every invocation of the callback and may be nil, in which case the callback
+// receives undefined.
+//
+// Objects with an access check are slower to access, and their global
+// proxies are only checked when the security tokens of the accessing and
+// accessed contexts differ; see [Context.SetSecurityToken].
+//
+// This corresponds to ObjectTemplate::SetAccessCheckCallback in the C++ API.
+func (o *ObjectTemplate) SetAccessCheckCallback(cb AccessCheckCallback, data Valuer) {
+	if cb == nil {
+		panic("nil AccessCheckCallback argument not supported")
+	}
+	ac := accessCheck{cb: cb, data: o.iso.undefined}
+	if data != nil {
+		ac.data = data.value()
+	}
+	cbref := o.iso.registerAccessCheck(ac)
+	C.ObjectTemplateSetAccessCheckCallback(o.ptr, C.int(cbref))
+	// The template no longer refers to the callback it replaces, which can
+	// only still be invoked through objects created from the template.
+	if o.acbref != 0 && !o.escaped {
+		o.iso.unregisterCallback(o.acbref)
+	}
+	o.acbref = cbref
+}
+
 // InternalFieldCount returns the number of internal fields that instances of this
 // template will have.
 func (o *ObjectTemplate) InternalFieldCount() uint32 {
@@ -103,5 +252,22 @@ func (o *ObjectTemplate) InternalFieldCount() uint32 {
 }
 
 func (o *ObjectTemplate) apply(opts *contextOptions) {
+	o.escaped = true
 	opts.gTmpl = o
 }
+
+//export goAccessCheckCallback
+func goAccessCheckCallback(ctxref int, cbref int, accessed C.ValuePtr) C.int {
+	ctx := getContext(ctxref)
+	if ctx == nil {
+		return 0
+	}
+	ac := ctx.iso.getAccessCheck(cbref)
+	if ac.cb == nil {
+		return 0
+	}
+	if ac.cb(ctx, &Object{&Value{ptr: accessed, ctx: ctx}}, ac.data) {
+		return 1
+	}
+	return 0
+}
@@ -27,11 +27,24 @@ extern RtnValue ObjectTemplateNewInstance(m_template* ptr, m_ctx* ctx_ptr);
 extern void ObjectTemplateSetInternalFieldCount(m_template* ptr,
                                                 int field_count);
 extern int ObjectTemplateInternalFieldCount(m_template* ptr);
+extern void ObjectTemplateMarkAsUndetectable(m_template* ptr);
 extern void ObjectTemplateSetAccessorProperty(m_template* ptr,
                                               const char* key,
                                               m_template* get,
                                               m_template* set,
                                               int attributes);
+extern void ObjectTemplateSetLazyDataProperty(m_template* ptr,
+                                              const char* key,
+                                              int callback_ref);
+extern void ObjectTemplateSetNativeDataProperty(m_template* ptr,
+                                                const char* key,
+                                                int callback_ref,
+                                                int has_setter,
+                                                int attributes);
+extern void ObjectTemplateSetCallAsFunctionHandler(m_template* ptr,
+                                                   int callback_ref);
+extern void ObjectTemplateSetAccessCheckCallback(m_template* ptr,
+                                                 int callback_ref);
 
 #ifdef __cplusplus
 }
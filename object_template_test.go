@@ -161,6 +161,13 @@ func TestObjectTemplateNewInstance(t *testing.T) {
 	if foo, _ := obj.Get("foo"); foo.String() != "bar" {
 		t.Errorf("unexpected value for object property: %v", foo)
 	}
+
+	other := v8.NewContext()
+	defer other.Isolate().Dispose()
+	defer other.Close()
+	if _, err := tmpl.NewInstance(other); err == nil {
+		t.Error("expected error for a context of another isolate, got <nil>")
+	}
 }
 
 func TestObjectTemplateSetAccessorProperty_OnlyGetter(t *testing.T) {
@@ -231,6 +238,210 @@ func TestObjectTemplateSetAccessorProperty_GetterAnSetter(t *testing.T) {
 	}
 }
 
+func TestObjectTemplateSetLazyDataProperty(t *testing.T) {
+	t.Parallel()
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	calls := 0
+	global := v8.NewObjectTemplate(iso)
+	global.SetLazyDataProperty("table", func(info *v8.FunctionCallbackInfo) *v8.Value {
+		calls++
+		obj, _ := v8.JSONParse(info.Context(), `{"a": 1, "b": 2}`)
+		return obj
+	})
+	ctx := v8.NewContext(iso, global)
+	defer ctx.Close()
+
+	if _, err := ctx.RunScript(`1 + 1`, ""); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 0 {
+		t.Fatalf("expected getter not to run before the property is read, got %d calls", calls)
+	}
+
+	val, err := ctx.RunScript(`table.a + table.b + (table === globalThis.table ? 0 : 100)`, "")
+	fatalIf(t, err)
+	if val.Integer() != 3 {
+		t.Errorf("expected 3, got %v", val)
+	}
+	if calls != 1 {
+		t.Errorf("expected getter to run once, got %d calls", calls)
+	}
+
+	val, err = ctx.RunScript(`table = 42; table`, "")
+	fatalIf(t, err)
+	if val.Integer() != 42 || calls != 1 {
+		t.Errorf("expected the property to be writable without calling the getter, got %v after %d calls", val, calls)
+	}
+}
+
+func TestObjectTemplateSetNativeDataProperty(t *testing.T) {
+	t.Parallel()
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	var counter int32
+	global := v8.NewObjectTemplate(iso)
+	global.SetNativeDataProperty("counter", func(info *v8.FunctionCallbackInfo) *v8.Value {
+		counter++
+		val, _ := v8.NewValue(iso, counter)
+		return val
+	}, func(info *v8.FunctionCallbackInfo) *v8.Value {
+		counter = info.Args()[0].Int32()
+		return nil
+	}, v8.DontEnum)
+	global.SetNativeDataProperty("version", func(info *v8.FunctionCallbackInfo) *v8.Value {
+		val, _ := v8.NewValue(iso, "1.0")
+		return val
+	}, nil, v8.None)
+	ctx := v8.NewContext(iso, global)
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`[counter, counter, counter].join(",")`, "")
+	fatalIf(t, err)
+	if got, want := val.String(), "1,2,3"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	val, err = ctx.RunScript(`counter = 10; counter`, "")
+	fatalIf(t, err)
+	if val.Int32() != 11 || counter != 11 {
+		t.Errorf("expected the setter to set the counter to 10, got %v and %d", val, counter)
+	}
+
+	val, err = ctx.RunScript(`
+		const d = Object.getOwnPropertyDescriptor(globalThis, "version");
+		version = "2.0";
+		[version, "value" in d, d.enumerable, Object.keys(globalThis).includes("counter")].join(",")
+	`, "")
+	fatalIf(t, err)
+	if got, want := val.String(), "1.0,true,true,false"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestObjectTemplateMarkAsUndetectable(t *testing.T) {
+	t.Parallel()
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	all := v8.NewObjectTemplate(iso)
+	if err := all.MarkAsUndetectable(); err == nil {
+		t.Fatal("expected an error marking a template without a call handler")
+	}
+	all.SetCallAsFunctionHandler(func(info *v8.FunctionCallbackInfo) (*v8.Value, error) {
+		return v8.NewValue(iso, "item "+info.Args()[0].String())
+	})
+	fatalIf(t, all.MarkAsUndetectable())
+	fatalIf(t, all.Set("length", int32(3)))
+	global := v8.NewObjectTemplate(iso)
+	fatalIf(t, global.Set("all", all))
+	ctx := v8.NewContext(iso, global)
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`[typeof all, !!all, all == null, all === undefined, all.length, all("a")].join(",")`, "")
+	fatalIf(t, err)
+	if got, want := val.String(), "undefined,false,true,false,3,item a"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestObjectTemplateSetWithAttributes(t *testing.T) {
+	t.Parallel()
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	tmpl := v8.NewObjectTemplate(iso)
+	method := v8.NewFunctionTemplate(iso, func(info *v8.FunctionCallbackInfo) *v8.Value { return nil })
+	fatalIf(t, tmpl.Set("method", method, v8.DontEnum))
+	fatalIf(t, tmpl.Set("constant", "fixed", v8.ReadOnly, v8.DontDelete))
+	fatalIf(t, tmpl.Set("plain", "value"))
+
+	ctx := v8.NewContext(iso)
+	defer ctx.Close()
+	obj, err := tmpl.NewInstance(ctx)
+	fatalIf(t, err)
+	fatalIf(t, ctx.Global().Set("obj", obj))
+
+	val, err := ctx.RunScript(`
+		const d = (k) => Object.getOwnPropertyDescriptor(obj, k);
+		[
+			Object.keys(obj).join(),
+			d("method").enumerable, d("method").writable,
+			d("constant").writable, d("constant").configurable, d("constant").enumerable,
+		].join()`, "")
+	fatalIf(t, err)
+	if got, want := val.String(), "constant,plain,false,true,false,false,true"; got != want {
+		t.Errorf("unexpected property attributes: got %q, want %q", got, want)
+	}
+}
+
+func TestObjectTemplateSetAccessCheckCallback(t *testing.T) {
+	t.Parallel()
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	data, err := v8.NewValue(iso, "tenant1")
+	fatalIf(t, err)
+
+	allow := false
+	var gotData string
+	global := v8.NewObjectTemplate(iso)
+	global.SetAccessCheckCallback(func(accessing *v8.Context, accessed *v8.Object, data *v8.Value) bool {
+		gotData = data.String()
+		return allow
+	}, data)
+
+	shared := v8.NewContext(iso, global)
+	defer shared.Close()
+	_, err = shared.RunScript(`var secret = 42`, "shared.js")
+	fatalIf(t, err)
+
+	tenant := v8.NewContext(iso)
+	defer tenant.Close()
+	fatalIf(t, tenant.Global().Set("shared", shared.Global()))
+
+	if _, err := tenant.RunScript(`shared.secret`, "tenant.js"); err == nil {
+		t.Error("expected access to be denied")
+	}
+	if gotData != "tenant1" {
+		t.Errorf("unexpected callback data: %q", gotData)
+	}
+
+	allow = true
+	val, err := tenant.RunScript(`shared.secret`, "tenant.js")
+	fatalIf(t, err)
+	if val.Int32() != 42 {
+		t.Errorf("unexpected value: %v", val)
+	}
+}
+
+func TestObjectTemplateReplaceAccessCheckCallback(t *testing.T) {
+	t.Parallel()
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	deny := func(*v8.Context, *v8.Object, *v8.Value) bool { return false }
+	tmpl := v8.NewObjectTemplate(iso)
+	tmpl.SetAccessCheckCallback(deny, nil)
+	tmpl.SetAccessCheckCallback(deny, nil)
+	if got := iso.AccessCheckCount(); got != 1 {
+		t.Errorf("expected the replaced access check to be released, got %d registered", got)
+	}
+
+	// Once an object was created from the template, the access check it was
+	// created with must stay registered.
+	ctx := v8.NewContext(iso)
+	defer ctx.Close()
+	_, err := tmpl.NewInstance(ctx)
+	fatalIf(t, err)
+	tmpl.SetAccessCheckCallback(deny, nil)
+	if got := iso.AccessCheckCount(); got != 2 {
+		t.Errorf("expected both access checks to be registered, got %d", got)
+	}
+}
+
 func TestObjectTemplate_garbageCollection(t *testing.T) {
 	t.Parallel()
 
@@ -6,6 +6,7 @@ package v8go_test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	v8 "github.com/lizc2003/v8go"
@@ -169,6 +170,81 @@ func TestObjectGet(t *testing.T) {
 	}
 }
 
+func TestObjectGetKey(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	key, err := v8.NewPropertyKey(ctx.Isolate(), "id")
+	fatalIf(t, err)
+	if !key.IsString() || key.String() != "id" {
+		t.Errorf("unexpected property key: %v", key)
+	}
+
+	val, err := ctx.RunScript(`[{id: 1}, {id: 2, other: true}, {}]`, "")
+	fatalIf(t, err)
+	obj, err := val.AsObject()
+	fatalIf(t, err)
+	var ids []string
+	for i := uint32(0); i < 3; i++ {
+		elem, err := obj.GetIdx(i)
+		fatalIf(t, err)
+		elemObj, err := elem.AsObject()
+		fatalIf(t, err)
+		id, err := elemObj.GetKey(key)
+		fatalIf(t, err)
+		ids = append(ids, id.String())
+	}
+	if got := strings.Join(ids, ","); got != "1,2,undefined" {
+		t.Errorf("unexpected ids: %s", got)
+	}
+
+	idx, err := v8.NewValue(ctx.Isolate(), int32(1))
+	fatalIf(t, err)
+	second, err := obj.GetKey(idx)
+	fatalIf(t, err)
+	if !second.IsObject() {
+		t.Errorf("expected numeric key to read an element, got %v", second)
+	}
+}
+
+func benchmarkObjectGet(b *testing.B, get func(obj *v8.Object, key *v8.Value) (*v8.Value, error)) {
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, _ := ctx.RunScript(`Array.from({length: 1000}, (_, i) => ({id: i, name: "n" + i}))`, "")
+	arr, _ := val.AsArray()
+	elems, _ := arr.Slice()
+	objs := make([]*v8.Object, len(elems))
+	for i, e := range elems {
+		objs[i], _ = e.AsObject()
+	}
+	key, _ := v8.NewPropertyKey(ctx.Isolate(), "id")
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for _, obj := range objs {
+			v, _ := get(obj, key)
+			v.Release()
+		}
+	}
+}
+
+func BenchmarkObjectGet(b *testing.B) {
+	benchmarkObjectGet(b, func(obj *v8.Object, _ *v8.Value) (*v8.Value, error) {
+		return obj.Get("id")
+	})
+}
+
+func BenchmarkObjectGetKey(b *testing.B) {
+	benchmarkObjectGet(b, func(obj *v8.Object, key *v8.Value) (*v8.Value, error) {
+		return obj.GetKey(key)
+	})
+}
+
 func TestObjectHas(t *testing.T) {
 	t.Parallel()
 
@@ -225,6 +301,167 @@ func TestObjectDelete(t *testing.T) {
 
 }
 
+func TestObjectGetConstructorName(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	tests := [...]struct {
+		source string
+		name   string
+	}{
+		{"new Date()", "Date"},
+		{"({})", "Object"},
+		{"[]", "Array"},
+		{"class MyClass {}; new MyClass()", "MyClass"},
+	}
+	for _, tt := range tests {
+		val, err := ctx.RunScript(tt.source, "")
+		fatalIf(t, err)
+		obj, err := val.AsObject()
+		fatalIf(t, err)
+		if got := obj.GetConstructorName(); got != tt.name {
+			t.Errorf("%s: expected constructor name %q, got %q", tt.source, tt.name, got)
+		}
+	}
+}
+
+func TestObjectClone(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`var orig = {a: 1, nested: {b: 2}}; orig`, "")
+	fatalIf(t, err)
+	obj, err := val.AsObject()
+	fatalIf(t, err)
+
+	fatalIf(t, ctx.Global().Set("copy", obj.Clone()))
+	res, err := ctx.RunScript(`copy.a = 3; copy.nested.b = 4; [orig.a, orig.nested.b, copy !== orig].join()`, "")
+	fatalIf(t, err)
+	if res.String() != "1,4,true" {
+		t.Errorf("unexpected shallow clone result: %s", res)
+	}
+}
+
+func TestObjectStructuredClone(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`
+		var orig = {
+			date: new Date(0),
+			map: new Map([["k", new Set([1, 2])]]),
+			bytes: new Uint8Array([1, 2, 3]),
+			nested: {b: 2},
+		};
+		orig.self = orig;
+		orig`, "")
+	fatalIf(t, err)
+	obj, err := val.AsObject()
+	fatalIf(t, err)
+
+	clone, err := obj.StructuredClone()
+	fatalIf(t, err)
+	fatalIf(t, ctx.Global().Set("copy", clone))
+
+	res, err := ctx.RunScript(`
+		copy.nested.b = 3;
+		[
+			copy !== orig,
+			copy.self === copy,
+			copy.date instanceof Date && copy.date.getTime() === 0,
+			copy.map.get("k").has(2),
+			copy.bytes instanceof Uint8Array && copy.bytes[2] === 3,
+			orig.nested.b === 2,
+		].every(Boolean)`, "")
+	fatalIf(t, err)
+	if !res.Boolean() {
+		t.Error("structured clone did not produce an independent deep copy")
+	}
+
+	val, err = ctx.RunScript(`({fn: function() {}})`, "")
+	fatalIf(t, err)
+	obj, err = val.AsObject()
+	fatalIf(t, err)
+	if _, err := obj.StructuredClone(); err == nil {
+		t.Error("expected error cloning an object with a function")
+	}
+}
+
+func TestObjectPreviewEntries(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	tests := [...]struct {
+		source     string
+		want       string
+		isKeyValue bool
+	}{
+		{`new Map([["a", 1], ["b", 2]])`, "a,1,b,2", true},
+		{`new Set(["x", "y"])`, "x,y", false},
+		{`const it = new Map([["a", 1], ["b", 2]]).keys(); it.next(); it`, "b", false},
+	}
+	for _, tt := range tests {
+		val, err := ctx.RunScript(tt.source, "")
+		fatalIf(t, err)
+		obj, err := val.AsObject()
+		fatalIf(t, err)
+		entries, isKeyValue, err := obj.PreviewEntries()
+		fatalIf(t, err)
+		strs := make([]string, len(entries))
+		for i, e := range entries {
+			strs[i] = e.String()
+		}
+		if got := strings.Join(strs, ","); got != tt.want || isKeyValue != tt.isKeyValue {
+			t.Errorf("%s: got (%q, %v), want (%q, %v)", tt.source, got, isKeyValue, tt.want, tt.isKeyValue)
+		}
+	}
+
+	// Previewing does not consume the iterator.
+	val, err := ctx.RunScript(`it.next().value`, "")
+	fatalIf(t, err)
+	if val.String() != "b" {
+		t.Errorf("expected the iterator to be unconsumed, got next value %q", val)
+	}
+
+	obj, err := ctx.RunScript(`({})`, "")
+	fatalIf(t, err)
+	if _, _, err := obj.Object().PreviewEntries(); err == nil {
+		t.Error("expected error previewing a plain object")
+	}
+}
+
+func TestObjectPreviewEntriesRelease(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`new Set([1, 2, 3])`, "")
+	fatalIf(t, err)
+	obj, err := val.AsObject()
+	fatalIf(t, err)
+
+	before := ctx.RetainedValueCount()
+	entries, _, err := obj.PreviewEntries()
+	fatalIf(t, err)
+	if n := ctx.RetainedValueCount(); n != before+len(entries) {
+		t.Errorf("expected only the entries to be retained, got %d retained values, want %d", n, before+len(entries))
+	}
+}
+
 func ExampleObject_global() {
 	iso := v8.NewIsolate()
 	defer iso.Dispose()
@@ -245,3 +482,248 @@ func ExampleObject_global() {
 	// Output:
 	// foo
 }
+
+func TestObjectGetRealNamedProperty(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`
+		const base = { inherited: "from proto" };
+		const obj = Object.create(base);
+		obj.plain = 1;
+		obj.empty = undefined;
+		Object.defineProperty(obj, "fixed", { value: 2, writable: false, enumerable: false });
+		obj`, "")
+	fatalIf(t, err)
+	obj, _ := val.AsObject()
+
+	plain, err := obj.GetRealNamedProperty("plain")
+	fatalIf(t, err)
+	inherited, err := obj.GetRealNamedProperty("inherited")
+	fatalIf(t, err)
+	if plain.Integer() != 1 || inherited.String() != "from proto" {
+		t.Errorf("unexpected values %v and %v", plain, inherited)
+	}
+
+	tests := [...]struct {
+		key   string
+		attrs v8.PropertyAttribute
+		ok    bool
+	}{
+		{"plain", v8.None, true},
+		{"empty", v8.None, true},
+		{"fixed", v8.ReadOnly | v8.DontEnum | v8.DontDelete, true},
+		{"inherited", v8.None, true},
+		{"missing", v8.None, false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		attrs, ok, err := obj.GetRealNamedPropertyAttributes(tt.key)
+		fatalIf(t, err)
+		if attrs != tt.attrs || ok != tt.ok {
+			t.Errorf("%s: got attributes %v, %v, want %v, %v", tt.key, attrs, ok, tt.attrs, tt.ok)
+		}
+	}
+
+	missing, err := obj.GetRealNamedProperty("missing")
+	fatalIf(t, err)
+	if !missing.IsUndefined() {
+		t.Errorf("expected undefined for a missing property, got %v", missing)
+	}
+}
+
+func TestObjectSetPrototype(t *testing.T) {
+	t.Parallel()
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	ctx := v8.NewContext(iso)
+	defer ctx.Close()
+
+	greet := v8.NewFunctionTemplate(iso, func(info *v8.FunctionCallbackInfo) *v8.Value {
+		name, _ := info.This().Get("name")
+		v, _ := v8.NewValue(iso, "hello, "+name.String())
+		return v
+	})
+	tmpl := v8.NewObjectTemplate(iso)
+	fatalIf(t, tmpl.Set("greet", greet))
+	proto, err := tmpl.NewInstance(ctx)
+	fatalIf(t, err)
+
+	val, err := ctx.RunScript(`({ name: "gopher" })`, "")
+	fatalIf(t, err)
+	obj, _ := val.AsObject()
+	fatalIf(t, obj.SetPrototype(proto))
+	fatalIf(t, ctx.Global().Set("obj", obj))
+
+	got, err := ctx.RunScript(`obj.greet()`, "")
+	fatalIf(t, err)
+	if got.String() != "hello, gopher" {
+		t.Errorf("unexpected result %q", got)
+	}
+	p, err := obj.GetPrototype()
+	fatalIf(t, err)
+	if !p.SameValue(proto.Value) {
+		t.Errorf("expected GetPrototype to return the template instance, got %v", p)
+	}
+
+	fatalIf(t, obj.SetPrototype(v8.Null(iso)))
+	if p, _ := obj.GetPrototype(); !p.IsNull() {
+		t.Errorf("expected a null prototype, got %v", p)
+	}
+
+	num, _ := v8.NewValue(iso, int32(1))
+	if err := obj.SetPrototype(num); err == nil {
+		t.Error("expected error for a number prototype, got <nil>")
+	}
+	fatalIf(t, proto.SetPrototype(obj))
+	if err := obj.SetPrototype(proto); err == nil {
+		t.Error("expected error for a cyclic prototype chain, got <nil>")
+	}
+	frozen, err := ctx.RunScript(`Object.freeze({})`, "")
+	fatalIf(t, err)
+	frozenObj, _ := frozen.AsObject()
+	if err := frozenObj.SetPrototype(proto); err == nil {
+		t.Error("expected error for a non-extensible object, got <nil>")
+	}
+}
+
+func TestObjectGetPropertyAttributes(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`
+		const obj = Object.create({ inherited: 1 });
+		obj.plain = 1;
+		Object.defineProperty(obj, "fixed", { value: 2, writable: false, enumerable: true, configurable: true });
+		Object.defineProperty(obj, "hidden", { get() { throw new Error("getter called"); } });
+		obj`, "")
+	fatalIf(t, err)
+	obj, _ := val.AsObject()
+
+	tests := [...]struct {
+		key   string
+		attrs v8.PropertyAttribute
+	}{
+		{"plain", v8.None},
+		{"fixed", v8.ReadOnly},
+		{"hidden", v8.DontEnum | v8.DontDelete},
+		{"inherited", v8.None},
+		{"missing", v8.None},
+	}
+	for _, tt := range tests {
+		attrs, err := obj.GetPropertyAttributes(tt.key)
+		fatalIf(t, err)
+		if attrs != tt.attrs {
+			t.Errorf("%s: got attributes %v, want %v", tt.key, attrs, tt.attrs)
+		}
+	}
+
+	val, err = ctx.RunScript(`Object.freeze([1, 2])`, "")
+	fatalIf(t, err)
+	arr, _ := val.AsObject()
+	attrs, err := arr.GetPropertyAttributesIdx(1)
+	fatalIf(t, err)
+	if want := v8.ReadOnly | v8.DontDelete; attrs != want {
+		t.Errorf("got attributes %v for a frozen element, want %v", attrs, want)
+	}
+
+	val, err = ctx.RunScript(`new Proxy({}, { getOwnPropertyDescriptor() { throw new Error("trap") } })`, "")
+	fatalIf(t, err)
+	proxy, _ := val.AsObject()
+	if _, err := proxy.GetPropertyAttributes("x"); err == nil {
+		t.Error("expected the error thrown by the proxy trap, got <nil>")
+	}
+}
+
+func TestObjectIntegrity(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	newObject := func() *v8.Object {
+		val, err := ctx.RunScript(`({a: 1})`, "obj.js")
+		fatalIf(t, err)
+		obj, err := val.AsObject()
+		fatalIf(t, err)
+		return obj
+	}
+	isExtensible := func(obj *v8.Object) bool {
+		ok, err := obj.IsExtensible()
+		fatalIf(t, err)
+		return ok
+	}
+
+	obj := newObject()
+	if !isExtensible(obj) {
+		t.Error("expected a new object to be extensible")
+	}
+	fatalIf(t, obj.PreventExtensions())
+	if isExtensible(obj) {
+		t.Error("expected object not to be extensible after PreventExtensions")
+	}
+
+	obj = newObject()
+	fatalIf(t, obj.SetIntegrityLevel(v8.IntegrityLevelFrozen))
+	fatalIf(t, ctx.Global().Set("frozen", obj))
+	val, err := ctx.RunScript(`Object.isFrozen(frozen) && !Object.isExtensible(frozen)`, "frozen.js")
+	fatalIf(t, err)
+	if !val.Boolean() {
+		t.Error("expected object to be frozen")
+	}
+
+	obj = newObject()
+	fatalIf(t, obj.SetIntegrityLevel(v8.IntegrityLevelSealed))
+	fatalIf(t, ctx.Global().Set("sealed", obj))
+	val, err = ctx.RunScript(`Object.isSealed(sealed) && !Object.isFrozen(sealed)`, "sealed.js")
+	fatalIf(t, err)
+	if !val.Boolean() {
+		t.Error("expected object to be sealed but not frozen")
+	}
+
+	val, err = ctx.RunScript(`new Proxy({}, {isExtensible() { throw new Error("trap") }})`, "proxy.js")
+	fatalIf(t, err)
+	proxy, err := val.AsObject()
+	fatalIf(t, err)
+	if _, err := proxy.IsExtensible(); err == nil {
+		t.Error("expected error from the isExtensible trap, got <nil>")
+	}
+}
+
+func TestObjectIntegrityIgnoresReplacedBuiltins(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`({a: 1})`, "obj.js")
+	fatalIf(t, err)
+	obj, err := val.AsObject()
+	fatalIf(t, err)
+	// The built-ins are read the first time v8go needs one.
+	if _, err := obj.IsExtensible(); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = ctx.RunScript(`
+		Object.isExtensible = () => true;
+		Object.preventExtensions = (o) => o;
+		globalThis.Object = null;
+	`, "replace.js")
+	fatalIf(t, err)
+
+	fatalIf(t, obj.PreventExtensions())
+	ok, err := obj.IsExtensible()
+	fatalIf(t, err)
+	if ok {
+		t.Error("expected the object not to be extensible despite the replaced built-ins")
+	}
+}
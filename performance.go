@@ -0,0 +1,56 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+import (
+	"errors"
+	"time"
+)
+
+// EnablePerformanceAPI installs a global `performance` object in the context,
+// as provided by browsers and Node.js. Its `now()` method returns the time in
+// fractional milliseconds since EnablePerformanceAPI was called, measured with
+// Go's monotonic clock, so it is unaffected by changes to the wall clock.
+// `performance.timeOrigin` is the Unix time in milliseconds at that point.
+func (c *Context) EnablePerformanceAPI() error {
+	start := time.Now()
+	return c.enablePerformanceAPI(start, func() time.Duration {
+		return time.Since(start)
+	})
+}
+
+// EnablePerformanceAPIWithClock is like EnablePerformanceAPI, but `now()`
+// returns the duration reported by clock, e.g. a fake clock that a test
+// advances explicitly to make timing deterministic.
+func (c *Context) EnablePerformanceAPIWithClock(clock func() time.Duration) error {
+	if clock == nil {
+		return errors.New("v8go: clock is required")
+	}
+	return c.enablePerformanceAPI(time.Now(), clock)
+}
+
+func (c *Context) enablePerformanceAPI(origin time.Time, clock func() time.Duration) error {
+	iso := c.iso
+	now := NewFunctionTemplateWithError(iso, func(info *FunctionCallbackInfo) (*Value, error) {
+		return NewValue(iso, durationToMillis(clock()))
+	})
+
+	perf := NewObjectTemplate(iso)
+	if err := perf.Set("now", now); err != nil {
+		return err
+	}
+	if err := perf.Set("timeOrigin", float64(origin.UnixNano())/float64(time.Millisecond), ReadOnly); err != nil {
+		return err
+	}
+	obj, err := perf.NewInstance(c)
+	if err != nil {
+		return err
+	}
+	return c.Global().Set("performance", obj)
+}
+
+func durationToMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
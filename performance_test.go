@@ -0,0 +1,60 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"testing"
+	"time"
+
+	v8 "github.com/lizc2003/v8go"
+)
+
+func TestContextEnablePerformanceAPI(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	before := time.Now()
+	fatalIf(t, ctx.EnablePerformanceAPI())
+
+	val, err := ctx.RunScript(`
+		const a = performance.now();
+		const b = performance.now();
+		a >= 0 && b >= a`, "perf.js")
+	fatalIf(t, err)
+	if !val.Boolean() {
+		t.Error("expected performance.now() to be non-negative and monotonic")
+	}
+
+	val, err = ctx.RunScript(`performance.timeOrigin`, "perf.js")
+	fatalIf(t, err)
+	if origin := val.Number(); origin < float64(before.UnixMilli()) {
+		t.Errorf("expected timeOrigin after %d, got %f", before.UnixMilli(), origin)
+	}
+}
+
+func TestContextEnablePerformanceAPIWithClock(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	var elapsed time.Duration
+	fatalIf(t, ctx.EnablePerformanceAPIWithClock(func() time.Duration { return elapsed }))
+
+	elapsed = 1500 * time.Microsecond
+	val, err := ctx.RunScript(`performance.now()`, "perf.js")
+	fatalIf(t, err)
+	if got := val.Number(); got != 1.5 {
+		t.Errorf("expected performance.now() to be 1.5, got %v", got)
+	}
+
+	if err := ctx.EnablePerformanceAPIWithClock(nil); err == nil {
+		t.Error("expected error with a nil clock, got <nil>")
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+import (
+	"fmt"
+	"time"
+)
+
+// defaultRandomSeed seeds Math.random in predictable isolates when
+// IsolateOptions.RandomSeed is zero.
+const defaultRandomSeed = 0x5eed
+
+// predictableShim replaces Date and Math.random in a new context of a
+// predictable isolate. It is called with the native clock, returning
+// milliseconds since the Unix epoch, and the 32-bit seed of Math.random,
+// which is a mulberry32 generator.
+const predictableShim = `(function (now, seed) {
+	"use strict";
+	const OriginalDate = Date;
+	function PredictableDate(...args) {
+		if (new.target === undefined) {
+			return OriginalDate.prototype.toString.call(new OriginalDate(now()));
+		}
+		if (args.length === 0) {
+			args = [now()];
+		}
+		return Reflect.construct(OriginalDate, args, new.target);
+	}
+	Object.defineProperties(PredictableDate, {
+		name: { value: "Date" },
+		length: { value: OriginalDate.length },
+		prototype: { value: OriginalDate.prototype, writable: false },
+		parse: { value: OriginalDate.parse, writable: true, configurable: true },
+		UTC: { value: OriginalDate.UTC, writable: true, configurable: true },
+		now: { value: { now() { return now(); } }.now, writable: true, configurable: true },
+	});
+	Object.defineProperty(OriginalDate.prototype, "constructor", {
+		value: PredictableDate, writable: true, configurable: true,
+	});
+	globalThis.Date = PredictableDate;
+
+	let state = seed >>> 0;
+	Object.defineProperty(Math, "random", {
+		value: function random() {
+			state = (state + 0x6d2b79f5) | 0;
+			let t = Math.imul(state ^ (state >>> 15), 1 | state);
+			t = (t + Math.imul(t ^ (t >>> 7), 61 | t)) ^ t;
+			return ((t ^ (t >>> 14)) >>> 0) / 4294967296;
+		},
+		writable: true, configurable: true,
+	});
+})`
+
+// predictableMode holds the state of an isolate created with
+// IsolateOptions.PredictableMode.
+type predictableMode struct {
+	seed  uint32
+	clock func() time.Time
+	now   *FunctionTemplate
+}
+
+func newPredictableMode(iso *Isolate, opts IsolateOptions) *predictableMode {
+	p := &predictableMode{
+		seed:  uint32(opts.RandomSeed),
+		clock: opts.Clock,
+	}
+	if p.seed == 0 {
+		p.seed = defaultRandomSeed
+	}
+	if p.clock == nil {
+		p.clock = func() time.Time { return time.Unix(0, 0) }
+	}
+	p.now = NewFunctionTemplate(iso, func(info *FunctionCallbackInfo) *Value {
+		ms := float64(p.clock().UnixNano()) / float64(time.Millisecond)
+		v, _ := NewValue(iso, ms)
+		return v
+	})
+	return p
+}
+
+// install replaces Date and Math.random in ctx with their predictable
+// versions. It fails if the global template of ctx prevents replacing them.
+func (p *predictableMode) install(ctx *Context) error {
+	seed, err := NewValue(ctx.iso, p.seed)
+	if err != nil {
+		return err
+	}
+	now := p.now.GetFunction(ctx)
+	if err := ctx.runShim(predictableShim, "v8go:predictable", now.Value, seed); err != nil {
+		return fmt.Errorf("v8go: cannot make the context predictable: %w", err)
+	}
+	return nil
+}
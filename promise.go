@@ -52,8 +52,8 @@ func NewPromiseResolver(ctx *Context) (*PromiseResolver, error) {
 // on multiple calls.
 func (r *PromiseResolver) GetPromise() *Promise {
 	if r.prom == nil {
-		ptr := C.PromiseResolverGetPromise(r.ptr)
-		val := &Value{ptr, r.ctx}
+		ptr := C.PromiseResolverGetPromise(r.valuePtr())
+		val := &Value{ptr: ptr, ctx: r.ctx}
 		r.prom = &Promise{&Object{val}}
 	}
 	return r.prom
@@ -62,26 +62,37 @@ func (r *PromiseResolver) GetPromise() *Promise {
 // Resolve invokes the Promise resolve state with the given value.
 // The Promise state will transition from Pending to Fulfilled.
 func (r *PromiseResolver) Resolve(val Valuer) bool {
-	return C.PromiseResolverResolve(r.ptr, val.value().ptr) != 0
+	return C.PromiseResolverResolve(r.valuePtr(), val.value().valuePtr()) != 0
 }
 
 // Reject invokes the Promise reject state with the given value.
 // The Promise state will transition from Pending to Rejected.
 func (r *PromiseResolver) Reject(err *Value) bool {
-	return C.PromiseResolverReject(r.ptr, err.ptr) != 0
+	return C.PromiseResolverReject(r.valuePtr(), err.valuePtr()) != 0
+}
+
+// RejectWithObject rejects the Promise with a new Error carrying the given
+// fields, so the JS side receives a structured error rather than a string.
+// See NewErrorObject for how the fields are applied.
+func (r *PromiseResolver) RejectWithObject(fields map[string]Valuer) (bool, error) {
+	errObj, err := NewErrorObject(r.ctx, fields)
+	if err != nil {
+		return false, err
+	}
+	return r.Reject(errObj.Value), nil
 }
 
 // State returns the current state of the Promise.
 func (p *Promise) State() PromiseState {
-	return PromiseState(C.PromiseState(p.ptr))
+	return PromiseState(C.PromiseState(p.valuePtr()))
 }
 
 // Result is the value result of the Promise. The Promise must
 // NOT be in a Pending state, otherwise may panic. Call promise.State()
 // to validate state before calling for the result.
 func (p *Promise) Result() *Value {
-	ptr := C.PromiseResult(p.ptr)
-	val := &Value{ptr, p.ctx}
+	ptr := C.PromiseResult(p.valuePtr())
+	val := &Value{ptr: ptr, ctx: p.ctx}
 	return val
 }
 
@@ -110,11 +121,11 @@ func (p *Promise) ThenWithError(cbs ...FunctionCallbackWithError) *Promise {
 	switch len(cbs) {
 	case 1:
 		cbID := p.ctx.iso.registerCallback(cbs[0])
-		rtn = C.PromiseThen(p.ptr, C.int(cbID))
+		rtn = C.PromiseThen(p.valuePtr(), C.int(cbID))
 	case 2:
 		cbID1 := p.ctx.iso.registerCallback(cbs[0])
 		cbID2 := p.ctx.iso.registerCallback(cbs[1])
-		rtn = C.PromiseThen2(p.ptr, C.int(cbID1), C.int(cbID2))
+		rtn = C.PromiseThen2(p.valuePtr(), C.int(cbID1), C.int(cbID2))
 
 	default:
 		panic("1 or 2 callbacks required")
@@ -136,10 +147,44 @@ func (p *Promise) Catch(cb FunctionCallback) *Promise {
 
 func (p *Promise) CatchWithError(cb FunctionCallbackWithError) *Promise {
 	cbID := p.ctx.iso.registerCallback(cb)
-	rtn := C.PromiseCatch(p.ptr, C.int(cbID))
+	rtn := C.PromiseCatch(p.valuePtr(), C.int(cbID))
 	obj, err := objectResult(p.ctx, rtn)
 	if err != nil {
 		panic(err) // TODO: Return error
 	}
 	return &Promise{obj}
 }
+
+// ThenFunction attaches JS functions, e.g. created from a FunctionTemplate, as
+// the handlers of the promise; either may be nil, but not both. It returns the
+// derived promise, which resolves with the result of the handler that runs.
+// This is equivalent to `p.then(onFulfilled, onRejected)` in JS.
+// See Then for when the handlers are invoked.
+func (p *Promise) ThenFunction(onFulfilled, onRejected *Function) (*Promise, error) {
+	if onFulfilled == nil && onRejected == nil {
+		return nil, errors.New("v8go: onFulfilled or onRejected is required")
+	}
+	var fulfilledPtr, rejectedPtr C.ValuePtr
+	if onFulfilled != nil {
+		fulfilledPtr = onFulfilled.valuePtr()
+	}
+	if onRejected != nil {
+		rejectedPtr = onRejected.valuePtr()
+	}
+	rtn := C.PromiseThenFunctions(p.valuePtr(), fulfilledPtr, rejectedPtr)
+	obj, err := objectResult(p.ctx, rtn)
+	if err != nil {
+		return nil, err
+	}
+	return &Promise{obj}, nil
+}
+
+// CatchFunction attaches a JS function as the rejection handler of the
+// promise. This is equivalent to `p.catch(onRejected)` in JS.
+// See Then for when the handler is invoked.
+func (p *Promise) CatchFunction(onRejected *Function) (*Promise, error) {
+	if onRejected == nil {
+		return nil, errors.New("v8go: onRejected is required")
+	}
+	return p.ThenFunction(nil, onRejected)
+}
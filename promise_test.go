@@ -111,6 +111,40 @@ func TestPromiseRejected(t *testing.T) {
 	}
 }
 
+func TestPromiseRejectWithObject(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	ctx := v8.NewContext(iso)
+	defer ctx.Close()
+
+	res, _ := v8.NewPromiseResolver(ctx)
+	msg, _ := v8.NewValue(iso, "request failed")
+	code, _ := v8.NewValue(iso, int32(503))
+	ok, err := res.RejectWithObject(map[string]v8.Valuer{"message": msg, "code": code})
+	fatalIf(t, err)
+	if !ok {
+		t.Fatal("expected the promise to be rejected")
+	}
+
+	prom := res.GetPromise()
+	if s := prom.State(); s != v8.Rejected {
+		t.Fatalf("unexpected state for Promise, want Rejected (2) got: %v", s)
+	}
+	reason, err := prom.Result().AsObject()
+	fatalIf(t, err)
+	if !reason.IsNativeError() {
+		t.Errorf("expected rejection reason to be an Error, got %q", reason.DetailString())
+	}
+	if got, _ := reason.Get("message"); got.String() != "request failed" {
+		t.Errorf("unexpected message: %q", got)
+	}
+	if got, _ := reason.Get("code"); got.Int32() != 503 {
+		t.Errorf("unexpected code: %q", got)
+	}
+}
+
 func TestPromiseThenCanThrow(t *testing.T) {
 	t.Parallel()
 
@@ -141,6 +175,58 @@ func TestPromiseThenCanThrow(t *testing.T) {
 	}
 }
 
+func TestPromiseThenFunction(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	ctx := v8.NewContext(iso)
+	defer ctx.Close()
+
+	exclaim, err := ctx.RunScript(`(v) => v + "!"`, "")
+	fatalIf(t, err)
+	onFulfilled, _ := exclaim.AsFunction()
+
+	var caught string
+	onRejected := v8.NewFunctionTemplate(iso, func(info *v8.FunctionCallbackInfo) *v8.Value {
+		caught = info.Args()[0].String()
+		v, _ := v8.NewValue(iso, "recovered")
+		return v
+	}).GetFunction(ctx)
+
+	res1, _ := v8.NewPromiseResolver(ctx)
+	prom1, err := res1.GetPromise().ThenFunction(onFulfilled, onRejected)
+	fatalIf(t, err)
+	val, _ := v8.NewValue(iso, "done")
+	res1.Resolve(val)
+	ctx.PerformMicrotaskCheckpoint()
+	if s := prom1.State(); s != v8.Fulfilled {
+		t.Fatalf("unexpected state for Promise, want Fulfilled got: %v", s)
+	}
+	if got := prom1.Result().String(); got != "done!" {
+		t.Errorf("unexpected result: got %q, want %q", got, "done!")
+	}
+
+	res2, _ := v8.NewPromiseResolver(ctx)
+	prom2, err := res2.GetPromise().CatchFunction(onRejected)
+	fatalIf(t, err)
+	chained, err := prom2.ThenFunction(onFulfilled, nil)
+	fatalIf(t, err)
+	reason, _ := v8.NewValue(iso, "failed")
+	res2.Reject(reason)
+	ctx.PerformMicrotaskCheckpoint()
+	if caught != "failed" {
+		t.Errorf("expected the rejection handler to be called with %q, got %q", "failed", caught)
+	}
+	if got := chained.Result().String(); got != "recovered!" {
+		t.Errorf("unexpected chained result: got %q, want %q", got, "recovered!")
+	}
+
+	if _, err := prom1.ThenFunction(nil, nil); err == nil {
+		t.Error("expected error with no handlers, got <nil>")
+	}
+}
+
 func TestPromiseThenPanic(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,59 @@
+#include "proxy.h"
+#include "context-macros.h"
+#include "deps/include/v8-proxy.h"
+#include "isolate-macros.h"
+#include "value-macros.h"
+
+using namespace v8;
+
+RtnValue NewProxy(ContextPtr ctx, ValuePtr target, ValuePtr handler) {
+  LOCAL_CONTEXT(ctx);
+  RtnValue rtn = {};
+
+  Local<Object> local_target = target->ptr.Get(iso).As<Object>();
+  Local<Object> local_handler = handler->ptr.Get(iso).As<Object>();
+  Local<Proxy> proxy;
+  if (!Proxy::New(local_ctx, local_target, local_handler).ToLocal(&proxy)) {
+    rtn.error = ExceptionError(try_catch, iso, local_ctx);
+    return rtn;
+  }
+  m_value* val = new m_value;
+  val->id = 0;
+  val->iso = iso;
+  val->ctx = ctx;
+  val->ptr = Global<Value>(iso, proxy);
+  rtn.value = tracked_value(ctx, val);
+  return rtn;
+}
+
+ValuePtr ProxyGetTarget(ValuePtr ptr) {
+  LOCAL_VALUE(ptr);
+  Local<Proxy> proxy = value.As<Proxy>();
+  m_value* val = new m_value;
+  val->id = 0;
+  val->iso = iso;
+  val->ctx = ctx;
+  val->ptr = Global<Value>(iso, proxy->GetTarget());
+  return tracked_value(ctx, val);
+}
+
+ValuePtr ProxyGetHandler(ValuePtr ptr) {
+  LOCAL_VALUE(ptr);
+  Local<Proxy> proxy = value.As<Proxy>();
+  m_value* val = new m_value;
+  val->id = 0;
+  val->iso = iso;
+  val->ctx = ctx;
+  val->ptr = Global<Value>(iso, proxy->GetHandler());
+  return tracked_value(ctx, val);
+}
+
+int ProxyIsRevoked(ValuePtr ptr) {
+  LOCAL_VALUE(ptr);
+  return value.As<Proxy>()->IsRevoked();
+}
+
+void ProxyRevoke(ValuePtr ptr) {
+  LOCAL_VALUE(ptr);
+  value.As<Proxy>()->Revoke();
+}
@@ -0,0 +1,54 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+// #include "proxy.h"
+import "C"
+import "errors"
+
+// Proxy is a JavaScript Proxy object, which intercepts operations on a target
+// object using the traps defined on a handler object.
+type Proxy struct {
+	*Object
+}
+
+// NewProxy creates a Proxy for target in the given context; this is
+// equivalent to `new Proxy(target, handler)` in JS.
+func NewProxy(ctx *Context, target, handler *Object) (*Proxy, error) {
+	if ctx == nil {
+		return nil, errors.New("v8go: Context is required")
+	}
+	if target == nil || handler == nil {
+		return nil, errors.New("v8go: target and handler are required")
+	}
+	rtn := C.NewProxy(ctx.ptr, target.valuePtr(), handler.valuePtr())
+	obj, err := objectResult(ctx, rtn)
+	if err != nil {
+		return nil, err
+	}
+	return &Proxy{obj}, nil
+}
+
+// GetTarget returns the target object of the proxy, or null if the proxy has
+// been revoked.
+func (p *Proxy) GetTarget() *Value {
+	return &Value{ptr: C.ProxyGetTarget(p.valuePtr()), ctx: p.ctx}
+}
+
+// GetHandler returns the handler object of the proxy, or null if the proxy
+// has been revoked.
+func (p *Proxy) GetHandler() *Value {
+	return &Value{ptr: C.ProxyGetHandler(p.valuePtr()), ctx: p.ctx}
+}
+
+// IsRevoked returns true if the proxy has been revoked.
+func (p *Proxy) IsRevoked() bool {
+	return C.ProxyIsRevoked(p.valuePtr()) != 0
+}
+
+// Revoke revokes the proxy; any further operation on it throws a TypeError.
+func (p *Proxy) Revoke() {
+	C.ProxyRevoke(p.valuePtr())
+}
@@ -0,0 +1,24 @@
+#ifndef V8GO_PROXY_H
+#define V8GO_PROXY_H
+
+#include "errors.h"
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+typedef struct m_ctx m_ctx;
+typedef m_ctx* ContextPtr;
+
+extern RtnValue NewProxy(ContextPtr ctx_ptr,
+                         ValuePtr target_ptr,
+                         ValuePtr handler_ptr);
+extern ValuePtr ProxyGetTarget(ValuePtr ptr);
+extern ValuePtr ProxyGetHandler(ValuePtr ptr);
+int ProxyIsRevoked(ValuePtr ptr);
+void ProxyRevoke(ValuePtr ptr);
+
+#ifdef __cplusplus
+}  // extern "C"
+#endif
+#endif
@@ -0,0 +1,76 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"testing"
+
+	v8 "github.com/lizc2003/v8go"
+)
+
+func TestProxy(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript("({ answer: 42 })", "")
+	fatalIf(t, err)
+	target, _ := val.AsObject()
+	val, err = ctx.RunScript("({ get: (target, prop) => prop in target ? target[prop] : 'missing ' + prop })", "")
+	fatalIf(t, err)
+	handler, _ := val.AsObject()
+
+	proxy, err := v8.NewProxy(ctx, target, handler)
+	fatalIf(t, err)
+	if !proxy.IsProxy() {
+		t.Error("expected value to be a Proxy")
+	}
+	fatalIf(t, ctx.Global().Set("proxy", proxy))
+	val, err = ctx.RunScript("proxy.answer + ', ' + proxy.question", "")
+	fatalIf(t, err)
+	if got, want := val.String(), "42, missing question"; got != want {
+		t.Errorf("unexpected result: got %q, want %q", got, want)
+	}
+
+	if !proxy.GetTarget().SameValue(target.Value) {
+		t.Error("unexpected proxy target")
+	}
+	if !proxy.GetHandler().SameValue(handler.Value) {
+		t.Error("unexpected proxy handler")
+	}
+	if proxy.IsRevoked() {
+		t.Error("expected proxy not to be revoked")
+	}
+	proxy.Revoke()
+	if !proxy.IsRevoked() {
+		t.Error("expected proxy to be revoked")
+	}
+	if _, err := ctx.RunScript("proxy.answer", ""); err == nil {
+		t.Error("expected an error accessing a revoked proxy")
+	}
+}
+
+func TestValueAsProxy(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript("new Proxy([], {})", "")
+	fatalIf(t, err)
+	proxy, err := val.AsProxy()
+	fatalIf(t, err)
+	if !proxy.GetTarget().IsArray() {
+		t.Error("expected the proxy target to be an array")
+	}
+
+	val, _ = ctx.RunScript("({})", "")
+	if _, err := val.AsProxy(); err == nil {
+		t.Error("expected an error casting a plain object to a Proxy")
+	}
+}
@@ -0,0 +1,224 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+import (
+	"errors"
+	"io"
+	"runtime/cgo"
+)
+
+// readableStreamChunkSize is the most bytes a read() of a stream created by
+// NewReadableStream returns.
+const readableStreamChunkSize = 64 * 1024
+
+// NewReadableStream creates an object that lets scripts consume r in chunks,
+// without buffering all of it first. Its read() method returns a promise of
+// `{ value, done }`, where value is a Uint8Array of the bytes read, like the
+// reader of a web ReadableStream; once r is exhausted, the promise resolves to
+// `{ value: undefined, done: true }`, and a read error rejects it. The object
+// is also an async iterator, so scripts can consume it with
+// `for await (const chunk of stream)`. cancel() stops the stream and closes r
+// if it is an io.Closer.
+//
+// The stream holds on to r until it is read to the end, fails or is
+// cancelled, so scripts that stop reading early should cancel it.
+//
+// r is read on the thread running the isolate when read() is called, so a
+// slow reader blocks the isolate.
+func (c *Context) NewReadableStream(r io.Reader) (*Object, error) {
+	if r == nil {
+		return nil, errors.New("v8go: nil reader")
+	}
+	obj, err := c.iso.readableStreamTemplate().NewInstance(c)
+	if err != nil {
+		return nil, err
+	}
+	s := &readableStream{ctx: c, r: r}
+	s.handle = cgo.NewHandle(s)
+	ext := NewExternalHandle(c.iso, s.handle)
+	defer ext.Release()
+	if err := obj.SetInternalField(0, ext); err != nil {
+		s.handle.Delete()
+		return nil, err
+	}
+	return obj, nil
+}
+
+// readableStreamTemplate returns the class of the objects created by
+// NewReadableStream. Its methods are created once per isolate and find the
+// state of the stream they are called on in its internal field, so that
+// streams don't register callbacks of their own.
+func (i *Isolate) readableStreamTemplate() *ObjectTemplate {
+	if i.readableStream != nil {
+		return i.readableStream
+	}
+	class := NewFunctionTemplate(i, func(info *FunctionCallbackInfo) *Value { return nil })
+	method := func(cb func(s *readableStream, this *Object) (*Value, error)) *FunctionTemplate {
+		return NewFunctionTemplateWithOptions(i, func(info *FunctionCallbackInfo) (*Value, error) {
+			return cb(readableStreamOf(info), info.This())
+		}, FunctionTemplateOptions{Receiver: class})
+	}
+	read := method((*readableStream).read)
+	cancel := method((*readableStream).cancel)
+	self := NewFunctionTemplateWithOptions(i, func(info *FunctionCallbackInfo) (*Value, error) {
+		return info.This().Value, nil
+	}, FunctionTemplateOptions{Receiver: class})
+
+	proto := class.PrototypeTemplate()
+	for name, fn := range map[string]*FunctionTemplate{"read": read, "next": read, "cancel": cancel, "return": cancel} {
+		// Only fails for unsupported value types.
+		_ = proto.Set(name, fn, DontEnum)
+	}
+	_ = proto.SetSymbol(SymbolAsyncIterator(i), self, DontEnum)
+
+	i.readableStream = class.InstanceTemplate()
+	i.readableStream.SetInternalFieldCount(1)
+	return i.readableStream
+}
+
+// readableStreamOf returns the state of the stream a method is called on. A
+// closed stream no longer has its state, so a stand-in that is done is
+// returned for it.
+func readableStreamOf(info *FunctionCallbackInfo) *readableStream {
+	field := info.This().GetInternalField(0)
+	defer field.Release()
+	if h, err := field.ExternalHandle(); err == nil {
+		return h.Value().(*readableStream)
+	}
+	return &readableStream{ctx: info.Context(), done: true}
+}
+
+// readableStream is the state of a stream created by NewReadableStream.
+type readableStream struct {
+	ctx  *Context
+	r    io.Reader
+	done bool
+	// err is the error returned by the reader together with the last chunk,
+	// to reject the following read with.
+	err error
+	// promise is the promise returned by the last read or cancel. It is
+	// settled before being returned, but can only be released once the
+	// callback returned it, so it is released by the next call.
+	promise *Value
+	// handle refers to the stream from the internal field of its object
+	// until the stream is closed.
+	handle cgo.Handle
+}
+
+// maxEmptyReads is how many times in a row read() calls a reader that returns
+// no bytes and no error before giving up with io.ErrNoProgress, as
+// bufio.Reader does.
+const maxEmptyReads = 100
+
+func (s *readableStream) read(this *Object) (*Value, error) {
+	resolver, err := s.newResolver()
+	if err != nil {
+		return nil, err
+	}
+	defer resolver.Release()
+	if s.done {
+		return s.resolveDone(resolver)
+	}
+	if s.err == nil {
+		buf := make([]byte, readableStreamChunkSize)
+		var n int
+		for i := 0; n == 0 && s.err == nil; i++ {
+			if i == maxEmptyReads {
+				s.err = io.ErrNoProgress
+				break
+			}
+			n, s.err = s.r.Read(buf)
+		}
+		if n > 0 {
+			chunk, err := newUint8Array(s.ctx, buf[:n])
+			if err != nil {
+				return nil, err
+			}
+			defer chunk.Release()
+			return s.resolve(resolver, chunk, false)
+		}
+	}
+	if s.err == io.EOF {
+		s.close(this)
+		return s.resolveDone(resolver)
+	}
+	errv, err := NewErrorValue(s.ctx, ErrorKindGeneric, s.err.Error())
+	if err != nil {
+		return nil, err
+	}
+	defer errv.Release()
+	s.close(this)
+	resolver.Reject(errv)
+	return s.settled(resolver), nil
+}
+
+func (s *readableStream) cancel(this *Object) (*Value, error) {
+	r := s.r
+	s.close(this)
+	if closer, ok := r.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			return nil, err
+		}
+	}
+	resolver, err := s.newResolver()
+	if err != nil {
+		return nil, err
+	}
+	defer resolver.Release()
+	return s.resolveDone(resolver)
+}
+
+// close finishes the stream, and detaches it from this, its object, so that
+// neither the stream nor the reader are kept alive by the object.
+func (s *readableStream) close(this *Object) {
+	s.done = true
+	s.r = nil
+	if s.handle != 0 {
+		// Only fails if this has no internal field, which the receiver check
+		// of the methods rules out.
+		_ = this.SetInternalField(0, Undefined(s.ctx.iso))
+		s.handle.Delete()
+		s.handle = 0
+	}
+}
+
+// newResolver releases the promise returned by the previous call, which the
+// script holds on to if it still needs it, and creates the resolver of the
+// promise of this call.
+func (s *readableStream) newResolver() (*PromiseResolver, error) {
+	if s.promise != nil {
+		s.promise.Release()
+		s.promise = nil
+	}
+	return NewPromiseResolver(s.ctx)
+}
+
+func (s *readableStream) resolveDone(resolver *PromiseResolver) (*Value, error) {
+	return s.resolve(resolver, Undefined(s.ctx.iso), true)
+}
+
+// resolve resolves the promise of a read with `{ value, done }`.
+func (s *readableStream) resolve(resolver *PromiseResolver, value *Value, done bool) (*Value, error) {
+	doneVal, err := NewValue(s.ctx.iso, done)
+	if err != nil {
+		return nil, err
+	}
+	defer doneVal.Release()
+	result, err := s.ctx.NewObjectFrom(map[string]Valuer{"value": value, "done": doneVal})
+	if err != nil {
+		return nil, err
+	}
+	defer result.Release()
+	resolver.Resolve(result)
+	return s.settled(resolver), nil
+}
+
+// settled returns the promise of resolver, which has been settled, and keeps
+// it to release on the next call.
+func (s *readableStream) settled(resolver *PromiseResolver) *Value {
+	s.promise = resolver.GetPromise().Value
+	return s.promise
+}
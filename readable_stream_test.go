@@ -0,0 +1,168 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	v8 "github.com/lizc2003/v8go"
+)
+
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestContextNewReadableStream(t *testing.T) {
+	t.Parallel()
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	if _, err := ctx.NewReadableStream(nil); err == nil {
+		t.Error("expected error for a nil reader, got <nil>")
+	}
+
+	consume := func(r io.Reader, script string) *v8.Value {
+		t.Helper()
+		stream, err := ctx.NewReadableStream(r)
+		fatalIf(t, err)
+		fatalIf(t, ctx.Global().Set("stream", stream))
+		val, err := ctx.RunScript(script, "stream.js")
+		fatalIf(t, err)
+		p, err := val.AsPromise()
+		fatalIf(t, err)
+		ctx.PerformMicrotaskCheckpoint()
+		if p.State() != v8.Fulfilled {
+			t.Fatalf("expected the script to finish, got state %v", p.State())
+		}
+		return p.Result()
+	}
+
+	const forAwait = `(async () => {
+		let text = "", chunks = 0;
+		for await (const chunk of stream) {
+			if (!(chunk instanceof Uint8Array)) throw new Error("not a Uint8Array");
+			text += String.fromCharCode(...chunk);
+			chunks++;
+		}
+		return text + "|" + chunks;
+	})()`
+	if got := consume(iotest.OneByteReader(strings.NewReader("abc")), forAwait); got.String() != "abc|3" {
+		t.Errorf("unexpected result %q", got)
+	}
+
+	const readTwice = `(async () => {
+		const first = await stream.read();
+		const second = await stream.read();
+		return [first.done, first.value.length, second.done, second.value].join("|");
+	})()`
+	if got := consume(strings.NewReader("hello"), readTwice); got.String() != "false|5|true|" {
+		t.Errorf("unexpected result %q", got)
+	}
+
+	failing := iotest.DataErrReader(io.MultiReader(strings.NewReader("ok"), iotest.ErrReader(errors.New("disk on fire"))))
+	got := consume(failing, `(async () => {
+		const first = await stream.read();
+		try { await stream.read(); } catch (e) { return first.value.length + "|" + e.message; }
+	})()`)
+	if got.String() != "2|disk on fire" {
+		t.Errorf("unexpected result %q", got)
+	}
+
+	closer := &closeRecorder{Reader: strings.NewReader("unread")}
+	if got := consume(closer, `stream.cancel().then(r => r.done)`); !got.Boolean() || !closer.closed {
+		t.Errorf("expected cancel to close the reader and finish the stream, got %v", got)
+	}
+}
+
+type emptyReader struct{}
+
+func (emptyReader) Read([]byte) (int, error) { return 0, nil }
+
+func TestReadableStreamNoProgress(t *testing.T) {
+	t.Parallel()
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	stream, err := ctx.NewReadableStream(emptyReader{})
+	fatalIf(t, err)
+	fatalIf(t, ctx.Global().Set("stream", stream))
+	val, err := ctx.RunScript(`stream.read().then(() => "resolved", e => e.message)`, "stream.js")
+	fatalIf(t, err)
+	p, err := val.AsPromise()
+	fatalIf(t, err)
+	ctx.PerformMicrotaskCheckpoint()
+	if got := p.Result().String(); got != io.ErrNoProgress.Error() {
+		t.Errorf("expected the read to reject with %q, got %q", io.ErrNoProgress, got)
+	}
+}
+
+func TestReadableStreamReleasesChunks(t *testing.T) {
+	t.Parallel()
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	stream, err := ctx.NewReadableStream(iotest.OneByteReader(strings.NewReader(strings.Repeat("x", 100))))
+	fatalIf(t, err)
+	fatalIf(t, ctx.Global().Set("stream", stream))
+	before := ctx.RetainedValueCount()
+	val, err := ctx.RunScript(`(async () => { let n = 0; for await (const c of stream) n += c.length; return n; })()`, "stream.js")
+	fatalIf(t, err)
+	defer val.Release()
+	ctx.PerformMicrotaskCheckpoint()
+	// Each of the 100 reads would retain several values if they were leaked.
+	if n := ctx.RetainedValueCount(); n > before+10 {
+		t.Errorf("expected the chunks to be released, retained %d values, was %d", n, before)
+	}
+}
+
+func TestReadableStreamSharesCallbacks(t *testing.T) {
+	t.Parallel()
+	ctx := v8.NewContext()
+	iso := ctx.Isolate()
+	defer iso.Dispose()
+	defer ctx.Close()
+
+	_, err := ctx.NewReadableStream(strings.NewReader("first"))
+	fatalIf(t, err)
+	before := iso.CallbackCount()
+	for i := 0; i < 10; i++ {
+		stream, err := ctx.NewReadableStream(strings.NewReader("more"))
+		fatalIf(t, err)
+		stream.Release()
+	}
+	if n := iso.CallbackCount(); n != before {
+		t.Errorf("expected streams to share their callbacks, got %d callbacks, was %d", n, before)
+	}
+
+	closer := &closeRecorder{Reader: strings.NewReader("unread")}
+	stream, err := ctx.NewReadableStream(closer)
+	fatalIf(t, err)
+	fatalIf(t, ctx.Global().Set("stream", stream))
+	val, err := ctx.RunScript(`(async () => {
+		await stream.cancel();
+		const { done } = await stream.read();
+		return done;
+	})()`, "stream.js")
+	fatalIf(t, err)
+	p, err := val.AsPromise()
+	fatalIf(t, err)
+	ctx.PerformMicrotaskCheckpoint()
+	if p.State() != v8.Fulfilled || !p.Result().Boolean() || !closer.closed {
+		t.Errorf("expected a cancelled stream to be done, got %v", p.Result())
+	}
+}
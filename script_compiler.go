@@ -7,11 +7,17 @@ package v8go
 // #include "v8go.h"
 import "C"
 
+// CompileMode controls how much of a script V8 compiles up front.
 type CompileMode C.int
 
 var (
+	// CompileModeDefault compiles only the top-level code; inner functions
+	// are compiled lazily the first time they are called.
 	CompileModeDefault = CompileMode(C.ScriptCompilerNoCompileOptions)
-	CompileModeEager   = CompileMode(C.ScriptCompilerEagerCompile)
+	// CompileModeEager compiles all functions up front. This costs more CPU at
+	// compile time, but avoids lazy compilation pauses later, and a code cache
+	// created from the script then covers every function.
+	CompileModeEager = CompileMode(C.ScriptCompilerEagerCompile)
 )
 
 type CompilerCachedData struct {
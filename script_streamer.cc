@@ -0,0 +1,162 @@
+/********** ScriptStreamer **********/
+
+#include "script_streamer.h"
+
+#include <condition_variable>
+#include <cstring>
+#include <deque>
+#include <memory>
+#include <mutex>
+#include <string>
+#include <thread>
+
+#include "context.h"
+#include "deps/include/v8-context.h"
+#include "isolate-macros.h"
+
+using namespace v8;
+
+// ChunkedSourceStream hands the chunks written from Go to the V8 parser,
+// which pulls them from a background thread through GetMoreData.
+class ChunkedSourceStream : public ScriptCompiler::ExternalSourceStream {
+ public:
+  size_t GetMoreData(const uint8_t** src) override {
+    std::unique_lock<std::mutex> lock(mu_);
+    cv_.wait(lock, [this] { return !chunks_.empty() || done_; });
+    if (chunks_.empty()) {
+      return 0;
+    }
+    std::string chunk = std::move(chunks_.front());
+    chunks_.pop_front();
+
+    // V8 takes ownership of the returned data and frees it with delete[].
+    uint8_t* data = new uint8_t[chunk.size()];
+    memcpy(data, chunk.data(), chunk.size());
+    *src = data;
+    return chunk.size();
+  }
+
+  void Push(const char* data, size_t length) {
+    std::lock_guard<std::mutex> lock(mu_);
+    chunks_.emplace_back(data, length);
+    cv_.notify_one();
+  }
+
+  void Close() {
+    std::lock_guard<std::mutex> lock(mu_);
+    done_ = true;
+    cv_.notify_one();
+  }
+
+ private:
+  std::mutex mu_;
+  std::condition_variable cv_;
+  std::deque<std::string> chunks_;
+  bool done_ = false;
+};
+
+struct m_scriptStreamer {
+  Isolate* iso;
+  std::string origin;
+  std::string full_source;
+  // Owned by source.
+  ChunkedSourceStream* stream;
+  std::unique_ptr<ScriptCompiler::StreamedSource> source;
+  // Null if V8 can't stream the script, in which case the full source is
+  // compiled in one go when finished.
+  std::unique_ptr<ScriptCompiler::ScriptStreamingTask> task;
+  std::thread thread;
+};
+
+ScriptStreamerPtr NewScriptStreamer(IsolatePtr iso, const char* origin) {
+  ISOLATE_SCOPE(iso);
+
+  m_scriptStreamer* s = new m_scriptStreamer;
+  s->iso = iso;
+  s->origin = origin;
+  s->stream = new ChunkedSourceStream;
+  s->source.reset(new ScriptCompiler::StreamedSource(
+      std::unique_ptr<ScriptCompiler::ExternalSourceStream>(s->stream),
+      ScriptCompiler::StreamedSource::UTF8));
+  s->task.reset(ScriptCompiler::StartStreaming(iso, s->source.get()));
+  if (s->task) {
+    ScriptCompiler::ScriptStreamingTask* task = s->task.get();
+    s->thread = std::thread([task] { task->Run(); });
+  }
+  return s;
+}
+
+void ScriptStreamerWrite(ScriptStreamerPtr ptr,
+                         const char* data,
+                         size_t length) {
+  ptr->full_source.append(data, length);
+  if (ptr->task) {
+    ptr->stream->Push(data, length);
+  }
+}
+
+void ScriptStreamerWait(ScriptStreamerPtr ptr) {
+  ptr->stream->Close();
+  if (ptr->thread.joinable()) {
+    ptr->thread.join();
+  }
+}
+
+void ScriptStreamerAbort(ScriptStreamerPtr ptr) {
+  ScriptStreamerWait(ptr);
+
+  ISOLATE_SCOPE(ptr->iso);
+  delete ptr;
+}
+
+RtnUnboundScript ScriptStreamerFinish(ScriptStreamerPtr ptr) {
+  ScriptStreamerWait(ptr);
+
+  Isolate* iso = ptr->iso;
+  ISOLATE_SCOPE(iso);
+  INTERNAL_CONTEXT(iso);
+  TryCatch try_catch(iso);
+  Local<Context> local_ctx = ctx->ptr.Get(iso);
+  Context::Scope context_scope(local_ctx);
+
+  RtnUnboundScript rtn = {};
+
+  Local<String> src, ogn;
+  if (!String::NewFromUtf8(iso, ptr->full_source.data(),
+                           NewStringType::kNormal, ptr->full_source.size())
+           .ToLocal(&src) ||
+      !String::NewFromUtf8(iso, ptr->origin.c_str(), NewStringType::kNormal)
+           .ToLocal(&ogn)) {
+    rtn.error = ExceptionError(try_catch, iso, local_ctx);
+    delete ptr;
+    return rtn;
+  }
+  ScriptOrigin script_origin(ogn);
+
+  Local<UnboundScript> unbound_script;
+  bool ok;
+  if (ptr->task) {
+    Local<Script> script;
+    ok = ScriptCompiler::Compile(local_ctx, ptr->source.get(), src,
+                                 script_origin)
+             .ToLocal(&script);
+    if (ok) {
+      unbound_script = script->GetUnboundScript();
+    }
+  } else {
+    ScriptCompiler::Source source(src, script_origin);
+    ok = ScriptCompiler::CompileUnboundScript(iso, &source)
+             .ToLocal(&unbound_script);
+  }
+  delete ptr;
+  if (!ok) {
+    rtn.error = ExceptionError(try_catch, iso, local_ctx);
+    return rtn;
+  }
+
+  m_unboundScript* us = new m_unboundScript;
+  us->ptr.Reset(iso, unbound_script);
+  ctx->unboundScripts.push_back(us);
+  rtn.ptr = us;
+  return rtn;
+}
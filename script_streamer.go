@@ -0,0 +1,210 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+// #include <stdlib.h>
+// #include "script_streamer.h"
+import "C"
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"unsafe"
+)
+
+// ScriptStreamer compiles a script while its source is still being received,
+// e.g. from the network. V8 parses the chunks written to it on a background
+// thread, so most of the compilation work is done by the time Finish is
+// called.
+type ScriptStreamer struct {
+	ptr C.ScriptStreamerPtr
+	iso *Isolate
+}
+
+// NewStreamingCompile starts compiling a script from UTF-8 source that is
+// written to the returned ScriptStreamer. origin (a.k.a. filename) is used in
+// stack traces, as for CompileUnboundScript.
+// Finish or Abort must be called to release the resources of the streamer;
+// a streamer that is garbage collected without either is aborted by the next
+// call to NewStreamingCompile or by Isolate.Dispose.
+func (i *Isolate) NewStreamingCompile(origin string) (*ScriptStreamer, error) {
+	if i.ptr == nil {
+		return nil, errors.New("v8go: Isolate has been disposed")
+	}
+	i.abandoned.abort()
+	cOrigin := C.CString(scriptOrigin(origin))
+	defer C.free(unsafe.Pointer(cOrigin))
+
+	s := &ScriptStreamer{
+		ptr: C.NewScriptStreamer(i.ptr, cOrigin),
+		iso: i,
+	}
+	runtime.SetFinalizer(s, (*ScriptStreamer).finalizer)
+	return s, nil
+}
+
+// Write adds the next chunk of source. Chunks may split multi-byte UTF-8
+// characters. Write implements io.Writer.
+func (s *ScriptStreamer) Write(p []byte) (int, error) {
+	if s.ptr == nil {
+		return 0, errors.New("v8go: ScriptStreamer has been finished")
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	C.ScriptStreamerWrite(s.ptr, (*C.char)(unsafe.Pointer(&p[0])), C.size_t(len(p)))
+	return len(p), nil
+}
+
+// Finish signals the end of the source, waits for the background parsing to
+// complete and returns the compiled script.
+// error will be of type `JSError` if the script failed to compile.
+func (s *ScriptStreamer) Finish() (*UnboundScript, error) {
+	if s.ptr == nil {
+		return nil, errors.New("v8go: ScriptStreamer has been finished")
+	}
+	rtn := C.ScriptStreamerFinish(s.ptr)
+	s.ptr = nil
+	if rtn.ptr == nil {
+		return nil, s.iso.takeHeapLimitError(newJSError(rtn.error))
+	}
+	return &UnboundScript{
+		ptr: rtn.ptr,
+		iso: s.iso,
+	}, nil
+}
+
+// Abort stops the compilation without a script, ending the background
+// parsing of the source written so far, and releases the resources of the
+// streamer. Calling Abort on a finished streamer does nothing.
+func (s *ScriptStreamer) Abort() {
+	if s.ptr == nil {
+		return
+	}
+	C.ScriptStreamerAbort(s.ptr)
+	s.ptr = nil
+}
+
+func (s *ScriptStreamer) finalizer() {
+	// The background thread of an abandoned streamer would otherwise block
+	// forever waiting for more source. Aborting it enters the isolate, which
+	// must not be done from the finalizer goroutine, so it is left to the
+	// isolate's thread.
+	if s.ptr != nil {
+		s.iso.abandoned.add(s.ptr)
+		s.ptr = nil
+	}
+}
+
+// abandonedStreamers is the queue of streamers of an isolate that were
+// garbage collected without being finished or aborted.
+type abandonedStreamers struct {
+	mu     sync.Mutex
+	ptrs   []C.ScriptStreamerPtr
+	closed bool
+}
+
+// add queues ptr to be aborted, unless the isolate has been disposed, after
+// which there is nothing left to abort it with.
+func (a *abandonedStreamers) add(ptr C.ScriptStreamerPtr) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.closed {
+		a.ptrs = append(a.ptrs, ptr)
+	}
+}
+
+// abort aborts the queued streamers. It must be called on the isolate's
+// thread.
+func (a *abandonedStreamers) abort() {
+	a.take(false)
+}
+
+// close aborts the queued streamers before the isolate is disposed, and
+// stops queueing new ones.
+func (a *abandonedStreamers) close() {
+	a.take(true)
+}
+
+func (a *abandonedStreamers) take(closing bool) {
+	a.mu.Lock()
+	ptrs := a.ptrs
+	a.ptrs = nil
+	a.closed = a.closed || closing
+	a.mu.Unlock()
+	for _, ptr := range ptrs {
+		C.ScriptStreamerAbort(ptr)
+	}
+}
+
+// CompileFuture is the result of a script compiled on a background thread
+// with Isolate.CompileInBackground.
+type CompileFuture struct {
+	s    *ScriptStreamer
+	done chan struct{}
+	// finished is set by the first call to Result, whose results script and
+	// err are returned by later calls.
+	finished bool
+	script   *UnboundScript
+	err      error
+}
+
+// CompileInBackground starts compiling source on a background thread and
+// returns without waiting for it, so the isolate can run other work in the
+// meantime. origin (a.k.a. filename) is used in stack traces, as for
+// CompileUnboundScript. Result must always be called, on the isolate's
+// thread, to get the compiled script and release the resources of the
+// compilation; a future that is garbage collected without it is aborted like
+// an abandoned ScriptStreamer.
+//
+// V8 does most of the parsing and compilation in the background, but may post
+// tasks that must run on the isolate's thread; an application waiting for
+// Done should keep pumping them with Isolate.PumpMessageLoop, e.g. from its
+// event loop.
+func (i *Isolate) CompileInBackground(source, origin string) (*CompileFuture, error) {
+	s, err := i.NewStreamingCompile(origin)
+	if err != nil {
+		return nil, err
+	}
+	if len(source) > 0 {
+		cSource := C.CString(source)
+		C.ScriptStreamerWrite(s.ptr, cSource, C.size_t(len(source)))
+		C.free(unsafe.Pointer(cSource))
+	}
+	f := &CompileFuture{s: s, done: make(chan struct{})}
+	ptr := s.ptr
+	go func() {
+		C.ScriptStreamerWait(ptr)
+		close(f.done)
+	}()
+	runtime.SetFinalizer(f, (*CompileFuture).finalizer)
+	return f, nil
+}
+
+// Done returns a channel that is closed when the background work is
+// complete, after which Result returns without blocking on it.
+func (f *CompileFuture) Done() <-chan struct{} {
+	return f.done
+}
+
+// Result waits for the background work to complete and finishes compiling
+// the script on the calling thread, which must be the isolate's. Later calls
+// return the same script or error.
+// error will be of type `JSError` if the script failed to compile.
+func (f *CompileFuture) Result() (*UnboundScript, error) {
+	if !f.finished {
+		<-f.done
+		f.script, f.err = f.s.Finish()
+		f.finished = true
+	}
+	return f.script, f.err
+}
+
+func (f *CompileFuture) finalizer() {
+	// The goroutine waiting for the background work refers to f, so it has
+	// returned by the time f is collected. The streamer is queued to be
+	// aborted on the isolate's thread.
+	f.s.finalizer()
+}
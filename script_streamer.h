@@ -0,0 +1,27 @@
+#ifndef V8GO_SCRIPT_STREAMER_H
+#define V8GO_SCRIPT_STREAMER_H
+
+#include <stddef.h>
+
+#include "unbound_script.h"
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+typedef struct m_scriptStreamer m_scriptStreamer;
+typedef m_scriptStreamer* ScriptStreamerPtr;
+
+extern ScriptStreamerPtr NewScriptStreamer(IsolatePtr iso_ptr,
+                                           const char* origin);
+extern void ScriptStreamerWrite(ScriptStreamerPtr ptr,
+                                const char* data,
+                                size_t length);
+extern void ScriptStreamerWait(ScriptStreamerPtr ptr);
+extern void ScriptStreamerAbort(ScriptStreamerPtr ptr);
+extern RtnUnboundScript ScriptStreamerFinish(ScriptStreamerPtr ptr);
+
+#ifdef __cplusplus
+}  // extern "C"
+#endif
+#endif
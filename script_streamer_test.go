@@ -0,0 +1,158 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	v8 "github.com/lizc2003/v8go"
+)
+
+func TestScriptStreamer(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	ctx := v8.NewContext(iso)
+	defer ctx.Close()
+
+	streamer, err := iso.NewStreamingCompile("bundle.js")
+	fatalIf(t, err)
+
+	source := strings.Repeat("var x = (x || 0) + 1;\n", 1000) + "'héllo ' + x"
+	// Write in small chunks to split multi-byte characters between writes.
+	for r := strings.NewReader(source); ; {
+		chunk := make([]byte, 7)
+		n, err := r.Read(chunk)
+		if err == io.EOF {
+			break
+		}
+		fatalIf(t, err)
+		_, err = streamer.Write(chunk[:n])
+		fatalIf(t, err)
+	}
+
+	us, err := streamer.Finish()
+	fatalIf(t, err)
+	val, err := us.Run(ctx)
+	fatalIf(t, err)
+	if got, want := val.String(), "héllo 1000"; got != want {
+		t.Errorf("unexpected result: got %q, want %q", got, want)
+	}
+
+	if _, err := streamer.Write([]byte("1")); err == nil {
+		t.Error("expected an error writing to a finished streamer")
+	}
+}
+
+func TestScriptStreamerSyntaxError(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	streamer, err := iso.NewStreamingCompile("broken.js")
+	fatalIf(t, err)
+	io.WriteString(streamer, "function (")
+
+	if _, err := streamer.Finish(); err == nil {
+		t.Fatal("expected a compile error")
+	}
+}
+
+func TestIsolateCompileInBackground(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	ctx := v8.NewContext(iso)
+	defer ctx.Close()
+
+	source := strings.Repeat("var x = (x || 0) + 1;\n", 1000) + "x"
+	future, err := iso.CompileInBackground(source, "bundle.js")
+	fatalIf(t, err)
+
+	// The isolate stays usable while the script compiles.
+	val, err := ctx.RunScript("6 * 7", "")
+	fatalIf(t, err)
+	if val.Int32() != 42 {
+		t.Errorf("unexpected result: %v", val)
+	}
+	for done := false; !done; {
+		select {
+		case <-future.Done():
+			done = true
+		default:
+			iso.PumpMessageLoop(false)
+		}
+	}
+
+	us, err := future.Result()
+	fatalIf(t, err)
+	val, err = us.Run(ctx)
+	fatalIf(t, err)
+	if val.Int32() != 1000 {
+		t.Errorf("unexpected result: got %v, want 1000", val)
+	}
+	if again, err := future.Result(); err != nil || again != us {
+		t.Errorf("expected the same script getting the result twice, got %v, %v", again, err)
+	}
+
+	future, err = iso.CompileInBackground("function (", "broken.js")
+	fatalIf(t, err)
+	_, err = future.Result()
+	if err == nil {
+		t.Fatal("expected a compile error")
+	}
+	if _, again := future.Result(); again != err {
+		t.Errorf("expected the same error getting the result twice, got %v", again)
+	}
+}
+
+func TestScriptStreamerAbort(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	streamer, err := iso.NewStreamingCompile("partial.js")
+	fatalIf(t, err)
+	io.WriteString(streamer, "var x = ")
+	streamer.Abort()
+	// Aborting twice is a no-op.
+	streamer.Abort()
+
+	if _, err := streamer.Finish(); err == nil {
+		t.Error("expected an error finishing an aborted streamer")
+	}
+	if _, err := streamer.Write([]byte("1")); err == nil {
+		t.Error("expected an error writing to an aborted streamer")
+	}
+}
+
+func TestScriptStreamerAbandoned(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	streamer, err := iso.NewStreamingCompile("abandoned.js")
+	fatalIf(t, err)
+	io.WriteString(streamer, "var x = ")
+	streamer.Finalize()
+	if n := iso.AbandonedStreamerCount(); n != 1 {
+		t.Fatalf("expected the streamer to be queued for the isolate, got %d", n)
+	}
+
+	next, err := iso.NewStreamingCompile("next.js")
+	fatalIf(t, err)
+	if n := iso.AbandonedStreamerCount(); n != 0 {
+		t.Errorf("expected the next streaming compile to abort the queue, got %d", n)
+	}
+	// A streamer abandoned last is aborted by Dispose.
+	next.Finalize()
+}
@@ -0,0 +1,174 @@
+#include "serializer.h"
+#include "context-macros.h"
+#include "deps/include/v8-value-serializer.h"
+#include "isolate-macros.h"
+#include "utils.h"
+#include "value-macros.h"
+
+#include <algorithm>
+#include <vector>
+
+using namespace v8;
+
+RtnBytes SerializeValue(ContextPtr ctx_ptr, ValuePtr val) {
+  LOCAL_VALUE_IN_CONTEXT(ctx_ptr, val);
+  RtnBytes rtn = {};
+
+  ValueSerializer serializer(iso);
+  serializer.WriteHeader();
+  if (serializer.WriteValue(local_ctx, value).IsNothing()) {
+    rtn.error = ExceptionError(try_catch, iso, local_ctx);
+    return rtn;
+  }
+  std::pair<uint8_t*, size_t> data = serializer.Release();
+  rtn.data = data.first;
+  rtn.length = data.second;
+  return rtn;
+}
+
+// Reads a value written by a ValueSerializer in ctx. The buffers of
+// transferred stand in for the ArrayBuffers passed to TransferArrayBuffer, in
+// order.
+static bool ReadValue(Isolate* iso,
+                      Local<Context> ctx,
+                      const uint8_t* data,
+                      size_t length,
+                      const std::vector<Local<ArrayBuffer>>& transferred,
+                      Local<Value>* result) {
+  ValueDeserializer deserializer(iso, data, length);
+  for (size_t i = 0; i < transferred.size(); i++) {
+    deserializer.TransferArrayBuffer(i, transferred[i]);
+  }
+  return deserializer.ReadHeader(ctx).FromMaybe(false) &&
+         deserializer.ReadValue(ctx).ToLocal(result);
+}
+
+// Clones value from src into dst, which may be the same context, by
+// serializing it in src and deserializing it in dst. The memory of the
+// ArrayBuffers of transfer moves to new buffers in the clone, and the
+// originals are detached once the clone is complete, as with postMessage, so a
+// failed clone leaves them intact. data_clone_error is set if the transfer
+// list can't be transferred.
+static RtnValue CloneValue(Isolate* iso,
+                           TryCatch& try_catch,
+                           Local<Context> src,
+                           ContextPtr dst,
+                           Local<Value> value,
+                           ValuePtr* transfer,
+                           int transfer_count,
+                           int* data_clone_error) {
+  RtnValue rtn = {};
+
+  ValueSerializer serializer(iso);
+  std::vector<Local<ArrayBuffer>> buffers;
+  for (int i = 0; i < transfer_count; i++) {
+    Local<Value> t = transfer[i]->ptr.Get(iso);
+    if (!t->IsArrayBuffer()) {
+      *data_clone_error = 1;
+      rtn.error.msg =
+          CopyString("Value in the transfer list is not an ArrayBuffer");
+      return rtn;
+    }
+    Local<ArrayBuffer> buffer = t.As<ArrayBuffer>();
+    if (!buffer->IsDetachable() || buffer->WasDetached() ||
+        std::find(buffers.begin(), buffers.end(), buffer) != buffers.end()) {
+      *data_clone_error = 1;
+      rtn.error.msg = CopyString(
+          "ArrayBuffer in the transfer list can't be transferred");
+      return rtn;
+    }
+    serializer.TransferArrayBuffer(buffers.size(), buffer);
+    buffers.push_back(buffer);
+  }
+
+  serializer.WriteHeader();
+  if (serializer.WriteValue(src, value).IsNothing()) {
+    rtn.error = ExceptionError(try_catch, iso, src);
+    return rtn;
+  }
+  std::pair<uint8_t*, size_t> data = serializer.Release();
+
+  Local<Context> dst_ctx = dst->ptr.Get(iso);
+  Context::Scope dst_scope(dst_ctx);
+  std::vector<Local<ArrayBuffer>> transferred;
+  for (Local<ArrayBuffer> buffer : buffers) {
+    transferred.push_back(ArrayBuffer::New(iso, buffer->GetBackingStore()));
+  }
+  Local<Value> result;
+  bool ok =
+      ReadValue(iso, dst_ctx, data.first, data.second, transferred, &result);
+  free(data.first);
+  if (!ok) {
+    rtn.error = ExceptionError(try_catch, iso, dst_ctx);
+    return rtn;
+  }
+  for (Local<ArrayBuffer> buffer : buffers) {
+    if (buffer->Detach(Local<Value>()).IsNothing()) {
+      rtn.error = ExceptionError(try_catch, iso, src);
+      return rtn;
+    }
+  }
+
+  m_value* clone = new m_value;
+  clone->id = 0;
+  clone->iso = iso;
+  clone->ctx = dst;
+  clone->ptr = Global<Value>(iso, result);
+  rtn.value = tracked_value(dst, clone);
+  return rtn;
+}
+
+RtnValue DeserializeValue(ContextPtr ctx,
+                          const uint8_t* data,
+                          size_t length) {
+  LOCAL_CONTEXT(ctx);
+  RtnValue rtn = {};
+
+  Local<Value> result;
+  if (!ReadValue(iso, local_ctx, data, length, {}, &result)) {
+    rtn.error = ExceptionError(try_catch, iso, local_ctx);
+    return rtn;
+  }
+
+  m_value* val = new m_value;
+  val->id = 0;
+  val->iso = iso;
+  val->ctx = ctx;
+  val->ptr = Global<Value>(iso, result);
+  rtn.value = tracked_value(ctx, val);
+  return rtn;
+}
+
+RtnValue ValueCloneInto(ContextPtr dst, ValuePtr val) {
+  if (val->iso != dst->iso) {
+    RtnValue rtn = {};
+    rtn.error.msg = CopyString("value belongs to a different isolate");
+    return rtn;
+  }
+  LOCAL_VALUE(val);
+
+  // Primitives don't belong to a context, so only objects are copied.
+  if (value->IsObject()) {
+    int data_clone_error = 0;
+    return CloneValue(iso, try_catch, local_ctx, dst, value, nullptr, 0,
+                      &data_clone_error);
+  }
+  RtnValue rtn = {};
+  m_value* clone = new m_value;
+  clone->id = 0;
+  clone->iso = iso;
+  clone->ctx = dst;
+  clone->ptr = Global<Value>(iso, value);
+  rtn.value = tracked_value(dst, clone);
+  return rtn;
+}
+
+RtnValue StructuredCloneValue(ContextPtr ctx_ptr,
+                              ValuePtr val,
+                              ValuePtr* transfer,
+                              int transfer_count,
+                              int* data_clone_error) {
+  LOCAL_VALUE_IN_CONTEXT(ctx_ptr, val);
+  return CloneValue(iso, try_catch, local_ctx, ctx, value, transfer,
+                    transfer_count, data_clone_error);
+}
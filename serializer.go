@@ -0,0 +1,149 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+// #include <stdlib.h>
+// #include "serializer.h"
+import "C"
+import (
+	"errors"
+	"unsafe"
+)
+
+// SerializeValue serializes val to bytes using V8's structured clone wire
+// format, the same format browsers use for postMessage and IndexedDB. The
+// result can be passed to DeserializeValue in any isolate, including one in
+// another process, as long as it runs a compatible version of V8.
+//
+// Dates, RegExps, Maps, Sets, ArrayBuffers, typed arrays and cyclic references
+// are supported; ArrayBuffer contents are copied into the data. An error is
+// returned for values that can't be cloned, such as functions, symbols and
+// SharedArrayBuffers.
+//
+// ArrayBuffers can't be transferred rather than copied here, as a transferred
+// buffer is left out of the data and its memory would have to reach the
+// deserializer some other way. Within an isolate, the `transfer` option of
+// structuredClone moves them; see EnableStructuredClone.
+func SerializeValue(ctx *Context, val Valuer) ([]byte, error) {
+	if ctx == nil {
+		return nil, errors.New("v8go: Context is required")
+	}
+	rtn := C.SerializeValue(ctx.ptr, val.value().valuePtr())
+	if rtn.data == nil {
+		return nil, ctx.resultError(rtn.error)
+	}
+	defer C.free(unsafe.Pointer(rtn.data))
+	return C.GoBytes(unsafe.Pointer(rtn.data), C.int(rtn.length)), nil
+}
+
+// DeserializeValue creates a value in ctx from data produced by
+// SerializeValue.
+func DeserializeValue(ctx *Context, data []byte) (*Value, error) {
+	if ctx == nil {
+		return nil, errors.New("v8go: Context is required")
+	}
+	if len(data) == 0 {
+		return nil, errors.New("v8go: no data to deserialize")
+	}
+	rtn := C.DeserializeValue(ctx.ptr, (*C.uint8_t)(unsafe.Pointer(&data[0])), C.size_t(len(data)))
+	return valueResult(ctx, rtn)
+}
+
+// CloneInto copies v into ctx, which must belong to the same isolate, e.g. to
+// pass the result of a script in one tenant's context to another. Primitives
+// are shared, as they don't belong to a context, while objects are copied
+// like SerializeValue and DeserializeValue would, so the clone shares no
+// state with v and uses the builtins of ctx. An error is returned for objects
+// that can't be cloned, such as functions.
+func (v *Value) CloneInto(ctx *Context) (*Value, error) {
+	if ctx == nil {
+		return nil, errors.New("v8go: Context is required")
+	}
+	rtn := C.ValueCloneInto(ctx.ptr, v.valuePtr())
+	return valueResult(ctx, rtn)
+}
+
+// EnableStructuredClone defines the global `structuredClone(value, options)`
+// function of the HTML standard in the context, which V8 leaves to the
+// embedder. Values are cloned with V8's serializer, like SerializeValue. The
+// ArrayBuffers in the `transfer` option are moved to the clone rather than
+// copied, and detached, so they can no longer be used in the original.
+func (c *Context) EnableStructuredClone() error {
+	fn := NewFunctionTemplateWithError(c.iso, func(info *FunctionCallbackInfo) (*Value, error) {
+		args := info.Args()
+		if len(args) == 0 {
+			return nil, newErrorException(c, ErrorKindType, "structuredClone: 1 argument required, but only 0 present")
+		}
+		var transfer []*Value
+		if len(args) > 1 && !args[1].IsNullOrUndefined() {
+			var err error
+			if transfer, err = transferList(args[1]); err != nil {
+				return nil, newErrorException(c, ErrorKindType, err.Error())
+			}
+			defer releaseValues(transfer)
+		}
+		clone, err := structuredClone(c, args[0], transfer)
+		if _, ok := err.(*Exception); ok {
+			return nil, err
+		}
+		if err != nil {
+			return nil, newErrorException(c, ErrorKindGeneric, err.Error())
+		}
+		return clone, nil
+	})
+	global := c.Global()
+	defer global.Release()
+	return global.Set("structuredClone", fn.GetFunction(c))
+}
+
+// transferList returns the values of the `transfer` option of
+// structuredClone.
+func transferList(options *Value) ([]*Value, error) {
+	opts, err := options.AsObject()
+	if err != nil {
+		return nil, errors.New("structuredClone: options must be an object")
+	}
+	list, err := opts.Get("transfer")
+	if err != nil {
+		return nil, err
+	}
+	defer list.Release()
+	if list.IsUndefined() {
+		return nil, nil
+	}
+	arr, err := list.AsArray()
+	if err != nil {
+		return nil, errors.New("structuredClone: transfer must be an array")
+	}
+	return arr.Slice()
+}
+
+// newDataCloneError returns an error named "DataCloneError" to throw from a
+// callback.
+func newDataCloneError(ctx *Context, msg string) error {
+	return newDOMException(ctx, "DataCloneError", 25, msg)
+}
+
+// structuredClone clones val in ctx, moving the ArrayBuffers of transfer to
+// the clone. A transfer list that can't be transferred returns an *Exception
+// named "DataCloneError", as browsers throw.
+func structuredClone(ctx *Context, val *Value, transfer []*Value) (*Value, error) {
+	var transferPtr *C.ValuePtr
+	ptrs := make([]C.ValuePtr, len(transfer))
+	for i, t := range transfer {
+		ptrs[i] = t.valuePtr()
+	}
+	if len(ptrs) > 0 {
+		transferPtr = &ptrs[0]
+	}
+	var dataCloneError C.int
+	rtn := C.StructuredCloneValue(ctx.ptr, val.valuePtr(), transferPtr, C.int(len(ptrs)), &dataCloneError)
+	if dataCloneError != 0 {
+		msg := C.GoString(rtn.error.msg)
+		C.free(unsafe.Pointer(rtn.error.msg))
+		return nil, newDataCloneError(ctx, msg)
+	}
+	return valueResult(ctx, rtn)
+}
@@ -0,0 +1,36 @@
+#ifndef V8GO_SERIALIZER_H
+#define V8GO_SERIALIZER_H
+
+#include <stddef.h>
+#include <stdint.h>
+
+#include "errors.h"
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+typedef struct m_ctx m_ctx;
+typedef m_ctx* ContextPtr;
+
+typedef struct {
+  uint8_t* data;
+  size_t length;
+  RtnError error;
+} RtnBytes;
+
+extern RtnBytes SerializeValue(ContextPtr ctx_ptr, ValuePtr val_ptr);
+extern RtnValue DeserializeValue(ContextPtr ctx_ptr,
+                                 const uint8_t* data,
+                                 size_t length);
+extern RtnValue ValueCloneInto(ContextPtr ctx_ptr, ValuePtr val_ptr);
+extern RtnValue StructuredCloneValue(ContextPtr ctx_ptr,
+                                     ValuePtr val_ptr,
+                                     ValuePtr* transfer,
+                                     int transfer_count,
+                                     int* data_clone_error);
+
+#ifdef __cplusplus
+}  // extern "C"
+#endif
+#endif
@@ -0,0 +1,151 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"testing"
+
+	v8 "github.com/lizc2003/v8go"
+)
+
+func TestSerializeValue(t *testing.T) {
+	t.Parallel()
+
+	ctx1 := v8.NewContext()
+	defer ctx1.Isolate().Dispose()
+	defer ctx1.Close()
+
+	val, err := ctx1.RunScript(`
+		var orig = {
+			date: new Date(0),
+			set: new Set(["a"]),
+			bytes: new Uint8Array([1, 2, 3]),
+		};
+		orig.self = orig;
+		orig`, "")
+	fatalIf(t, err)
+	data, err := v8.SerializeValue(ctx1, val)
+	fatalIf(t, err)
+
+	// Deserialize into a different isolate.
+	ctx2 := v8.NewContext()
+	defer ctx2.Isolate().Dispose()
+	defer ctx2.Close()
+
+	copied, err := v8.DeserializeValue(ctx2, data)
+	fatalIf(t, err)
+	fatalIf(t, ctx2.Global().Set("copy", copied))
+
+	res, err := ctx2.RunScript(`
+		copy.self === copy &&
+			copy.date.getTime() === 0 &&
+			copy.set.has("a") &&
+			copy.bytes instanceof Uint8Array && copy.bytes[2] === 3`, "")
+	fatalIf(t, err)
+	if !res.Boolean() {
+		t.Error("deserialized value does not match the original")
+	}
+}
+
+func TestSerializeValue_errors(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	fn, err := ctx.RunScript(`(function() {})`, "")
+	fatalIf(t, err)
+	if _, err := v8.SerializeValue(ctx, fn); err == nil {
+		t.Error("expected error serializing a function")
+	}
+
+	if _, err := v8.DeserializeValue(ctx, []byte{0xff, 0x0f, 0x6f}); err == nil {
+		t.Error("expected error deserializing invalid data")
+	}
+	if _, err := v8.DeserializeValue(ctx, nil); err == nil {
+		t.Error("expected error deserializing empty data")
+	}
+}
+
+func TestValueCloneInto(t *testing.T) {
+	t.Parallel()
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	src := v8.NewContext(iso)
+	defer src.Close()
+	dst := v8.NewContext(iso)
+	defer dst.Close()
+
+	val, err := src.RunScript(`({name: "report", sizes: new Map([["a", 1]]), created: new Date(0)})`, "")
+	fatalIf(t, err)
+	clone, err := val.CloneInto(dst)
+	fatalIf(t, err)
+	fatalIf(t, dst.Global().Set("report", clone))
+	check, err := dst.RunScript(`report instanceof Object && report.sizes instanceof Map && report.created instanceof Date && report.name + ":" + report.sizes.get("a")`, "")
+	fatalIf(t, err)
+	if got, want := check.String(), "report:1"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	sym, err := src.RunScript(`Symbol.for("shared")`, "")
+	fatalIf(t, err)
+	symClone, err := sym.CloneInto(dst)
+	fatalIf(t, err)
+	fatalIf(t, dst.Global().Set("sym", symClone))
+	if same, err := dst.RunScript(`sym === Symbol.for("shared")`, ""); err != nil || !same.Boolean() {
+		t.Errorf("expected the symbol to be shared, got %v, %v", same, err)
+	}
+
+	fn, err := src.RunScript(`(() => 1)`, "")
+	fatalIf(t, err)
+	if _, err := fn.CloneInto(dst); err == nil {
+		t.Error("expected error cloning a function, got <nil>")
+	}
+
+	other := v8.NewContext()
+	defer other.Isolate().Dispose()
+	defer other.Close()
+	if _, err := val.CloneInto(other); err == nil {
+		t.Error("expected error cloning into another isolate, got <nil>")
+	}
+}
+
+func TestContextEnableStructuredClone(t *testing.T) {
+	t.Parallel()
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	fatalIf(t, ctx.EnableStructuredClone())
+
+	tests := [...]struct {
+		source string
+		want   string
+	}{
+		{`const o = { a: [1, { b: new Date(0) }], m: new Map([["k", 1]]) }; o.self = o;
+			const c = structuredClone(o);
+			[c !== o, c.self === c, c.a[1].b instanceof Date, c.m.get("k")].join("|")`, "true|true|true|1"},
+		{`structuredClone("text")`, "text"},
+		{`const buf = new Uint8Array([1, 2, 3]).buffer;
+			const copy = structuredClone(buf);
+			[copy.byteLength, buf.byteLength].join("|")`, "3|3"},
+		{`const src = new Uint8Array([1, 2, 3]);
+			const moved = structuredClone({ src }, { transfer: [src.buffer] });
+			[moved.src.join(","), src.byteLength, src.buffer.detached].join("|")`, "1,2,3|0|true"},
+		{`try { structuredClone(() => 1) } catch (e) { "threw" }`, "threw"},
+		{`try { structuredClone({}, { transfer: [{}] }) } catch (e) { [e.name, e.code, e.message].join("|") }`, "DataCloneError|25|Value in the transfer list is not an ArrayBuffer"},
+		{`const b = new ArrayBuffer(1); try { structuredClone(b, { transfer: [b, b] }) } catch (e) { e.name + "|" + b.byteLength }`, "DataCloneError|1"},
+		{`const b = new ArrayBuffer(1); try { structuredClone({ b, f() {} }, { transfer: [b] }) } catch (e) { b.byteLength }`, "1"},
+		{`try { structuredClone() } catch (e) { e instanceof TypeError }`, "true"},
+	}
+	for _, tt := range tests {
+		val, err := ctx.RunScript("{"+tt.source+"}", "clone.js")
+		fatalIf(t, err)
+		if got := val.String(); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.source, got, tt.want)
+		}
+	}
+}
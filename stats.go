@@ -0,0 +1,56 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+// #include <stdlib.h>
+// #include "isolate.h"
+import "C"
+import "unsafe"
+
+// EnableStats makes the isolate record V8's internal statistics: counters,
+// such as the number of compilations or global handles, and histograms, such
+// as the times of GC phases. Only events after the call are recorded. Read
+// the statistics with VisitStats or DumpAndResetStats.
+func (i *Isolate) EnableStats() {
+	C.IsolateEnableStats(i.ptr)
+}
+
+// VisitStats calls fn with the name and value of each statistic recorded by
+// isolates with EnableStats. Each histogram is reported as two values, the
+// number and the sum of its samples, named after the histogram with a
+// ".count" and a ".sum" suffix. V8 doesn't report which isolate a statistic
+// belongs to, so the values are totals across isolates.
+func VisitStats(fn func(name string, value int64)) {
+	visitStats(false, fn)
+}
+
+// DumpAndResetStats is like VisitStats, but resets the statistics to zero
+// after reading them, which suits exporting them to a monitoring system at
+// intervals.
+func DumpAndResetStats(fn func(name string, value int64)) {
+	visitStats(true, fn)
+}
+
+func visitStats(reset bool, fn func(name string, value int64)) {
+	var creset C.int
+	if reset {
+		creset = 1
+	}
+	rtn := C.StatsGet(creset)
+	if rtn.count == 0 {
+		return
+	}
+	defer C.free(unsafe.Pointer(rtn.entries))
+
+	entries := unsafe.Slice(rtn.entries, rtn.count)
+	names := make([]string, len(entries))
+	for i, e := range entries {
+		names[i] = C.GoString(e.name)
+		C.free(unsafe.Pointer(e.name))
+	}
+	for i, e := range entries {
+		fn(names[i], int64(e.value))
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"testing"
+
+	v8 "github.com/lizc2003/v8go"
+)
+
+func TestIsolateStats(t *testing.T) {
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	iso.EnableStats()
+
+	ctx := v8.NewContext(iso)
+	defer ctx.Close()
+	_, err := ctx.RunScript(`
+		function fib(n) { return n < 2 ? n : fib(n - 1) + fib(n - 2); }
+		const garbage = [];
+		for (let i = 0; i < 1000; i++) garbage.push({ i, s: String(i) });
+		fib(20);
+	`, "stats.js")
+	fatalIf(t, err)
+	iso.LowMemoryNotification()
+
+	total := func(visit func(func(string, int64))) int64 {
+		var sum int64
+		visit(func(name string, value int64) {
+			if name == "" {
+				t.Error("unexpected statistic without a name")
+			}
+			sum += value
+		})
+		return sum
+	}
+
+	if got := total(v8.VisitStats); got == 0 {
+		t.Fatal("expected statistics to be recorded")
+	}
+	dumped := total(v8.DumpAndResetStats)
+	if got := total(v8.VisitStats); got >= dumped {
+		t.Errorf("expected statistics to be reset, got a total of %d after %d", got, dumped)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+// #include "value.h"
+import "C"
+import (
+	"errors"
+	"fmt"
+)
+
+// ConcatStrings returns the concatenation of the strings a and b, without
+// running a script. V8 represents the result as a rope that refers to a and b
+// rather than copying them. An error is returned if a or b is not a string, or
+// if the result would exceed the maximum string length.
+func ConcatStrings(a, b *Value) (*Value, error) {
+	return concatStrings([]*Value{a, b})
+}
+
+func concatStrings(strs []*Value) (*Value, error) {
+	ptrs := make([]C.ValuePtr, len(strs))
+	for i, s := range strs {
+		if s == nil || !s.IsString() {
+			return nil, fmt.Errorf("v8go: value %d is not a string", i)
+		}
+		ptrs[i] = s.valuePtr()
+	}
+	rtn := C.StringConcat(&ptrs[0], C.int(len(ptrs)))
+	return valueResult(strs[0].ctx, rtn)
+}
+
+// StringBuilder assembles a string from segments, such as the output of a
+// template, by concatenating them in V8 with String::Concat, so the segments
+// are not copied to and from Go. The zero value is not usable; create one with
+// NewStringBuilder.
+type StringBuilder struct {
+	iso   *Isolate
+	parts []*Value
+	// owned are the segments created by AppendString, released by Reset.
+	owned []*Value
+}
+
+// NewStringBuilder creates an empty StringBuilder for strings of iso.
+func NewStringBuilder(iso *Isolate) *StringBuilder {
+	if iso == nil {
+		panic("nil Isolate argument not supported")
+	}
+	return &StringBuilder{iso: iso}
+}
+
+// Append adds the string s to the end of the builder. An error is returned if
+// s is not a string.
+func (b *StringBuilder) Append(s *Value) error {
+	if s == nil || !s.IsString() {
+		return errors.New("v8go: value is not a string")
+	}
+	b.parts = append(b.parts, s)
+	return nil
+}
+
+// AppendString adds s to the end of the builder as a new JS string, which is
+// released by Reset.
+func (b *StringBuilder) AppendString(s string) error {
+	val, err := NewValue(b.iso, s)
+	if err != nil {
+		return err
+	}
+	b.owned = append(b.owned, val)
+	b.parts = append(b.parts, val)
+	return nil
+}
+
+// Len returns the number of segments in the builder.
+func (b *StringBuilder) Len() int {
+	return len(b.parts)
+}
+
+// Value returns the concatenation of the segments in the builder, or the empty
+// string if it has none. The builder can be appended to and used again.
+func (b *StringBuilder) Value() (*Value, error) {
+	if len(b.parts) == 0 {
+		return NewValue(b.iso, "")
+	}
+	return concatStrings(b.parts)
+}
+
+// Reset empties the builder and releases the segments created by
+// AppendString. Values returned by Value are not affected.
+func (b *StringBuilder) Reset() {
+	for _, v := range b.owned {
+		v.Release()
+	}
+	b.parts = nil
+	b.owned = nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"strings"
+	"testing"
+
+	v8 "github.com/lizc2003/v8go"
+)
+
+func TestConcatStrings(t *testing.T) {
+	t.Parallel()
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	a, _ := v8.NewValue(iso, "hello, ")
+	b, _ := v8.NewValue(iso, "wörld")
+	s, err := v8.ConcatStrings(a, b)
+	fatalIf(t, err)
+	if got, want := s.String(), "hello, wörld"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	n, _ := v8.NewValue(iso, int32(1))
+	if _, err := v8.ConcatStrings(a, n); err == nil {
+		t.Error("expected error concatenating a number, got <nil>")
+	}
+}
+
+func TestStringBuilder(t *testing.T) {
+	t.Parallel()
+	ctx := v8.NewContext()
+	iso := ctx.Isolate()
+	defer iso.Dispose()
+	defer ctx.Close()
+
+	b := v8.NewStringBuilder(iso)
+	empty, err := b.Value()
+	fatalIf(t, err)
+	if !empty.IsString() || empty.String() != "" {
+		t.Errorf("expected the empty string, got %v", empty)
+	}
+
+	name, err := ctx.RunScript(`"v8go"`, "")
+	fatalIf(t, err)
+	fatalIf(t, b.AppendString("<h1>"))
+	fatalIf(t, b.Append(name))
+	fatalIf(t, b.AppendString("</h1>"))
+	if b.Len() != 3 {
+		t.Errorf("expected 3 segments, got %d", b.Len())
+	}
+	html, err := b.Value()
+	fatalIf(t, err)
+	if got, want := html.String(), "<h1>v8go</h1>"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	obj, err := ctx.RunScript(`({})`, "")
+	fatalIf(t, err)
+	if err := b.Append(obj); err == nil {
+		t.Error("expected error appending an object, got <nil>")
+	}
+
+	b.Reset()
+	if b.Len() != 0 {
+		t.Errorf("expected no segments after Reset, got %d", b.Len())
+	}
+	if got := html.String(); got != "<h1>v8go</h1>" {
+		t.Errorf("expected the built string to survive Reset, got %q", got)
+	}
+	if !name.IsString() {
+		t.Error("expected appended values not to be released by Reset")
+	}
+
+	for i := 0; i < 1000; i++ {
+		fatalIf(t, b.AppendString("ab"))
+	}
+	long, err := b.Value()
+	fatalIf(t, err)
+	if got := long.String(); got != strings.Repeat("ab", 1000) {
+		t.Errorf("unexpected long string of length %d", len(got))
+	}
+	b.Reset()
+}
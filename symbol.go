@@ -42,13 +42,13 @@ func symbolByIndex(iso *Isolate, idx C.SymbolIndex) *Symbol {
 	if val == nil {
 		panic(fmt.Errorf("unknown symbol index: %d", idx))
 	}
-	return &Symbol{&Value{val, nil}}
+	return &Symbol{&Value{ptr: val}}
 }
 
 // Description returns the string representation of the symbol,
 // e.g. "Symbol.asyncIterator".
 func (sym *Symbol) Description() string {
-	s := C.SymbolDescription(sym.Value.ptr)
+	s := C.SymbolDescription(sym.valuePtr())
 	defer C.free(unsafe.Pointer(s))
 	return C.GoString(s)
 }
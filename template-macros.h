@@ -6,9 +6,11 @@
 #include "deps/include/v8-locker.h"
 #include "deps/include/v8-template.h"
 
+#include "isolate-macros.h"
+
 #define LOCAL_TEMPLATE(tmpl_ptr)     \
   Isolate* iso = tmpl_ptr->iso;      \
-  Locker locker(iso);                \
+  IsolateLocker locker(iso);         \
   Isolate::Scope isolate_scope(iso); \
   HandleScope handle_scope(iso);     \
   Local<Template> tmpl = tmpl_ptr->ptr.Get(iso);
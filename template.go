@@ -18,12 +18,33 @@ import (
 type template struct {
 	ptr C.TemplatePtr
 	iso *Isolate
+
+	// cbref is the callback of a function template or the call as function
+	// handler of an object template, and acbref the access check of an
+	// object template. escaped records whether V8 may have
+	// created a function or object from the template, in which case they
+	// must stay registered after the template is finalized. A template
+	// escapes through:
+	//   - FunctionTemplate.GetFunction
+	//   - FunctionTemplate.InstanceTemplate and PrototypeTemplate, for the
+	//     function template and the returned object template
+	//   - FunctionTemplate.Inherit, for the base template
+	//   - ObjectTemplate.SetAccessorProperty, for the getter and setter
+	//   - ObjectTemplate.NewInstance
+	//   - NewContext, for an ObjectTemplate passed as the global template
+	//   - Set and SetSymbol, for a template set as a property of another
+	// Any new API that hands a template to V8 must mark it as escaped too.
+	cbref   int
+	acbref  int
+	escaped bool
 }
 
 // Set adds a property to each instance created by this template.
 // The property must be defined either as a primitive value, or a template.
 // If the value passed is a Go supported primitive (string, int32, uint32, int64, uint64, float64, big.Int)
 // then a value will be created and set as the value property.
+// Optional attributes are combined, e.g. passing DontEnum for methods on a
+// prototype template makes them non-enumerable like those of built-in classes.
 func (t *template) Set(name string, val interface{}, attributes ...PropertyAttribute) error {
 	cname := C.CString(name)
 	defer C.free(unsafe.Pointer(cname))
@@ -41,16 +62,18 @@ func (t *template) Set(name string, val interface{}, attributes ...PropertyAttri
 		}
 		C.TemplateSetValue(t.ptr, cname, newVal.ptr, C.int(attrs))
 	case *ObjectTemplate:
+		v.escaped = true
 		C.TemplateSetTemplate(t.ptr, cname, v.ptr, C.int(attrs))
 		runtime.KeepAlive(v)
 	case *FunctionTemplate:
+		v.escaped = true
 		C.TemplateSetTemplate(t.ptr, cname, v.ptr, C.int(attrs))
 		runtime.KeepAlive(v)
 	case *Value:
 		if v.IsObject() || v.IsExternal() {
 			return errors.New("v8go: unsupported property: value type must be a primitive or use a template")
 		}
-		C.TemplateSetValue(t.ptr, cname, v.ptr, C.int(attrs))
+		C.TemplateSetValue(t.ptr, cname, v.valuePtr(), C.int(attrs))
 	default:
 		return fmt.Errorf("v8go: unsupported property type `%T`, must be one of string, int32, uint32, int64, uint64, float64, *big.Int, *v8go.Value, *v8go.ObjectTemplate or *v8go.FunctionTemplate", v)
 	}
@@ -75,16 +98,18 @@ func (t *template) SetSymbol(key *Symbol, val interface{}, attributes ...Propert
 		if err != nil {
 			return fmt.Errorf("v8go: unable to create new value: %v", err)
 		}
-		if C.TemplateSetAnyValue(t.ptr, key.ptr, newVal.ptr, C.int(attrs)) == 0 {
+		if C.TemplateSetAnyValue(t.ptr, key.valuePtr(), newVal.ptr, C.int(attrs)) == 0 {
 			return fmt.Errorf("v8go: unable to set property for symbol %v", key)
 		}
 	case *ObjectTemplate:
-		if C.TemplateSetAnyTemplate(t.ptr, key.ptr, v.ptr, C.int(attrs)) == 0 {
+		v.escaped = true
+		if C.TemplateSetAnyTemplate(t.ptr, key.valuePtr(), v.ptr, C.int(attrs)) == 0 {
 			return fmt.Errorf("v8go: unable to set property for symbol %v", key)
 		}
 		runtime.KeepAlive(v)
 	case *FunctionTemplate:
-		if C.TemplateSetAnyTemplate(t.ptr, key.ptr, v.ptr, C.int(attrs)) == 0 {
+		v.escaped = true
+		if C.TemplateSetAnyTemplate(t.ptr, key.valuePtr(), v.ptr, C.int(attrs)) == 0 {
 			return fmt.Errorf("v8go: unable to set property for symbol %v", key)
 		}
 		runtime.KeepAlive(v)
@@ -92,7 +117,7 @@ func (t *template) SetSymbol(key *Symbol, val interface{}, attributes ...Propert
 		if v.IsObject() || v.IsExternal() {
 			return errors.New("v8go: unsupported property: value type must be a primitive or use a template")
 		}
-		if C.TemplateSetAnyValue(t.ptr, key.ptr, v.ptr, C.int(attrs)) == 0 {
+		if C.TemplateSetAnyValue(t.ptr, key.valuePtr(), v.valuePtr(), C.int(attrs)) == 0 {
 			return fmt.Errorf("v8go: unable to set property for symbol %v", key)
 		}
 	default:
@@ -104,6 +129,17 @@ func (t *template) SetSymbol(key *Symbol, val interface{}, attributes ...Propert
 }
 
 func (t *template) finalizer() {
+	// A template that no function or object was ever created from can't
+	// call back into Go anymore, so its callbacks can be released along with
+	// it.
+	if !t.escaped {
+		if t.cbref != 0 {
+			t.iso.unregisterCallback(t.cbref)
+		}
+		if t.acbref != 0 {
+			t.iso.unregisterCallback(t.acbref)
+		}
+	}
 	// Using v8::PersistentBase::Reset() wouldn't be thread-safe to do from
 	// this finalizer goroutine so just free the wrapper and let the template
 	// itself get cleaned up when the isolate is disposed.
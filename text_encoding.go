@@ -0,0 +1,181 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+import (
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// textEncodingShim defines TextEncoder and TextDecoder in terms of the native
+// encode and decode functions it is called with.
+const textEncodingShim = `(function (encode, decode) {
+	"use strict";
+	const labels = {
+		"utf-8": "utf-8", "utf8": "utf-8", "unicode-1-1-utf-8": "utf-8",
+		"utf-16le": "utf-16le", "utf-16": "utf-16le",
+	};
+	class TextEncoder {
+		get encoding() { return "utf-8"; }
+		encode(input = "") { return encode(String(input)); }
+	}
+	class TextDecoder {
+		#encoding; #fatal; #ignoreBOM;
+		constructor(label = "utf-8", options = {}) {
+			const encoding = labels[String(label).trim().toLowerCase()];
+			if (encoding === undefined) {
+				throw new RangeError("The encoding label provided ('" + label + "') is invalid.");
+			}
+			this.#encoding = encoding;
+			this.#fatal = Boolean(options && options.fatal);
+			this.#ignoreBOM = Boolean(options && options.ignoreBOM);
+		}
+		get encoding() { return this.#encoding; }
+		get fatal() { return this.#fatal; }
+		get ignoreBOM() { return this.#ignoreBOM; }
+		decode(input) {
+			if (input === undefined) return "";
+			if (!ArrayBuffer.isView(input) && !(input instanceof ArrayBuffer)) {
+				throw new TypeError("The provided value is not of type '(ArrayBuffer or ArrayBufferView)'");
+			}
+			return decode(input, this.#encoding, this.#fatal, this.#ignoreBOM);
+		}
+	}
+	for (const ctor of [TextEncoder, TextDecoder]) {
+		Object.defineProperty(globalThis, ctor.name, {
+			value: ctor, writable: true, configurable: true,
+		});
+	}
+})`
+
+// EnableTextEncoding defines the TextEncoder and TextDecoder classes of the
+// Encoding standard in the context, which V8 leaves to the embedder.
+// TextEncoder encodes strings as UTF-8 Uint8Arrays. TextDecoder decodes an
+// ArrayBuffer or ArrayBufferView as UTF-8 or UTF-16LE, replacing invalid data
+// with U+FFFD, or throwing a TypeError with the `fatal` option, and skips a
+// leading byte order mark unless the `ignoreBOM` option is set. Streaming
+// decode, i.e. the `stream` option of decode, is not supported.
+func (c *Context) EnableTextEncoding() error {
+	iso := c.iso
+	encode := NewFunctionTemplateWithError(iso, func(info *FunctionCallbackInfo) (*Value, error) {
+		return newUint8Array(c, []byte(info.Args()[0].String()))
+	}).GetFunction(c)
+	decode := NewFunctionTemplateWithError(iso, func(info *FunctionCallbackInfo) (*Value, error) {
+		args := info.Args()
+		s, ok := decodeText(copyBytes(args[0]), args[1].String(), args[2].Boolean(), args[3].Boolean())
+		if !ok {
+			return nil, newErrorException(c, ErrorKindType, "The encoded data was not valid for encoding "+args[1].String())
+		}
+		return NewValue(iso, s)
+	}).GetFunction(c)
+	return c.runShim(textEncodingShim, "v8go:text-encoding", encode.Value, decode.Value)
+}
+
+// decodeText decodes b from encoding, which is "utf-8" or "utf-16le". It
+// returns false if fatal is set and b is not valid.
+func decodeText(b []byte, encoding string, fatal, ignoreBOM bool) (string, bool) {
+	if encoding == "utf-16le" {
+		if !ignoreBOM && len(b) >= 2 && b[0] == 0xff && b[1] == 0xfe {
+			b = b[2:]
+		}
+		return decodeUTF16LE(b, fatal)
+	}
+	if !ignoreBOM && len(b) >= 3 && b[0] == 0xef && b[1] == 0xbb && b[2] == 0xbf {
+		b = b[3:]
+	}
+	if utf8.Valid(b) {
+		return string(b), true
+	}
+	if fatal {
+		return "", false
+	}
+	return replaceInvalidUTF8(b), true
+}
+
+// replaceInvalidUTF8 replaces each maximal subpart of an invalid UTF-8
+// sequence in b with U+FFFD, as the Encoding standard requires, rather than
+// each invalid byte.
+func replaceInvalidUTF8(b []byte) string {
+	var sb strings.Builder
+	sb.Grow(len(b))
+	for len(b) > 0 {
+		r, size := utf8.DecodeRune(b)
+		if r != utf8.RuneError || size > 1 {
+			sb.Write(b[:size])
+			b = b[size:]
+			continue
+		}
+		sb.WriteRune(utf8.RuneError)
+		b = b[validUTF8Prefix(b):]
+	}
+	return sb.String()
+}
+
+// validUTF8Prefix returns the length of the longest prefix of b that starts
+// a valid UTF-8 sequence, which is at least 1.
+func validUTF8Prefix(b []byte) int {
+	var n int
+	lo, hi := byte(0x80), byte(0xbf)
+	switch c := b[0]; {
+	case c >= 0xc2 && c <= 0xdf:
+		n = 2
+	case c >= 0xe0 && c <= 0xef:
+		n = 3
+		if c == 0xe0 {
+			lo = 0xa0
+		} else if c == 0xed {
+			hi = 0x9f
+		}
+	case c >= 0xf0 && c <= 0xf4:
+		n = 4
+		if c == 0xf0 {
+			lo = 0x90
+		} else if c == 0xf4 {
+			hi = 0x8f
+		}
+	default:
+		return 1
+	}
+	i := 1
+	for ; i < n && i < len(b); i++ {
+		if b[i] < lo || b[i] > hi {
+			break
+		}
+		lo, hi = 0x80, 0xbf
+	}
+	return i
+}
+
+// decodeUTF16LE decodes b as UTF-16LE, replacing lone surrogates and a
+// trailing odd byte with U+FFFD, or returning false for them if fatal is set.
+func decodeUTF16LE(b []byte, fatal bool) (string, bool) {
+	if fatal && len(b)%2 != 0 {
+		return "", false
+	}
+	units := make([]uint16, len(b)/2)
+	for i := range units {
+		units[i] = uint16(b[2*i]) | uint16(b[2*i+1])<<8
+	}
+	runes := make([]rune, 0, len(units)+1)
+	for i := 0; i < len(units); i++ {
+		r := rune(units[i])
+		switch {
+		case !utf16.IsSurrogate(r):
+			runes = append(runes, r)
+		case r < 0xdc00 && i+1 < len(units) && units[i+1] >= 0xdc00 && units[i+1] <= 0xdfff:
+			runes = append(runes, utf16.DecodeRune(r, rune(units[i+1])))
+			i++
+		case fatal:
+			return "", false
+		default:
+			runes = append(runes, utf8.RuneError)
+		}
+	}
+	if len(b)%2 != 0 {
+		runes = append(runes, utf8.RuneError)
+	}
+	return string(runes), true
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"testing"
+
+	v8 "github.com/lizc2003/v8go"
+)
+
+func TestContextEnableTextEncoding(t *testing.T) {
+	t.Parallel()
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	fatalIf(t, ctx.EnableTextEncoding())
+
+	tests := [...]struct {
+		source string
+		want   string
+	}{
+		{`Array.from(new TextEncoder().encode("héllo €")).join(",")`, "104,195,169,108,108,111,32,226,130,172"},
+		{`new TextEncoder().encoding`, "utf-8"},
+		{`new TextDecoder().decode(new Uint8Array([0xef, 0xbb, 0xbf, 0x61]))`, "a"},
+		{`new TextDecoder("utf-8", { ignoreBOM: true }).decode(new Uint8Array([0xef, 0xbb, 0xbf, 0x61]))`, "\ufeffa"},
+		{`new TextDecoder().decode(new Uint8Array([0x61, 0xe2, 0x82, 0x41, 0x80]))`, "a�A�"},
+		{`new TextDecoder("UTF-16LE").decode(new Uint8Array([0x68, 0, 0x69, 0]))`, "hi"},
+		{`new TextDecoder("utf-16le").decode(new Uint8Array([0, 0xd8, 0x68, 0]))`, "�h"},
+		{`new TextDecoder().decode(new Uint8Array([0, 0x61, 0x62]).subarray(1))`, "ab"},
+		{`new TextDecoder().decode(new Uint8Array([0xf0, 0x9f, 0x98, 0x80]).buffer)`, "😀"},
+		{`new TextDecoder().decode()`, ""},
+		{`[new TextDecoder("utf8", { fatal: true }).fatal, new TextDecoder("utf8").encoding].join("|")`, "true|utf-8"},
+		{`try { new TextDecoder("utf-8", { fatal: true }).decode(new Uint8Array([0xff])) } catch (e) { e instanceof TypeError }`, "true"},
+		{`try { new TextDecoder("koi8-r") } catch (e) { e instanceof RangeError }`, "true"},
+		{`try { new TextDecoder().decode("text") } catch (e) { e instanceof TypeError }`, "true"},
+	}
+	for _, tt := range tests {
+		val, err := ctx.RunScript(tt.source, "text.js")
+		fatalIf(t, err)
+		if got := val.String(); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestTextEncoderIgnoresReplacedUint8Array(t *testing.T) {
+	t.Parallel()
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	fatalIf(t, ctx.EnableTextEncoding())
+	// The built-ins are read the first time v8go needs one.
+	_, err := ctx.RunScript(`new TextEncoder().encode("")`, "first.js")
+	fatalIf(t, err)
+	val, err := ctx.RunScript(`
+		const Original = Uint8Array;
+		globalThis.Uint8Array = function () { throw new Error("replaced"); };
+		new TextEncoder().encode("ok") instanceof Original`, "encode.js")
+	fatalIf(t, err)
+	if !val.Boolean() {
+		t.Error("expected encode to create a built-in Uint8Array")
+	}
+}
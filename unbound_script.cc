@@ -3,6 +3,10 @@
 #include "unbound_script.h"
 #include "context-macros.h"
 #include "isolate-macros.h"
+#include "utils.h"
+
+#include <algorithm>
+#include <vector>
 
 namespace v8 {
 class Isolate;
@@ -56,3 +60,42 @@ RtnValue UnboundScriptRun(ContextPtr ctx, UnboundScriptPtr us_ptr) {
   rtn.value = tracked_value(ctx, val);
   return rtn;
 }
+
+RtnCompileHints UnboundScriptGetCompileHints(Isolate* iso,
+                                             UnboundScriptPtr us_ptr) {
+  ISOLATE_SCOPE(iso);
+  // The hints are recorded on the compiled script shared by all the contexts
+  // it is bound to, so binding it to any context gives the same result.
+  Local<Context> local_ctx = isolateInternalContext(iso)->ptr.Get(iso);
+  Context::Scope context_scope(local_ctx);
+
+  RtnCompileHints rtn = {};
+  std::vector<int> hints =
+      us_ptr->ptr.Get(iso)->BindToCurrentContext()->GetProducedCompileHints();
+  if (hints.empty()) {
+    return rtn;
+  }
+  rtn.positions = (int*)malloc(sizeof(int) * hints.size());
+  std::copy(hints.begin(), hints.end(), rtn.positions);
+  rtn.count = hints.size();
+  return rtn;
+}
+
+static const char* CopyURL(Isolate* iso, Local<Value> url) {
+  if (url.IsEmpty() || !url->IsString()) {
+    return nullptr;
+  }
+  String::Utf8Value str(iso, url);
+  return CopyString(str);
+}
+
+const char* UnboundScriptGetSourceURL(Isolate* iso, UnboundScriptPtr us_ptr) {
+  ISOLATE_SCOPE(iso);
+  return CopyURL(iso, us_ptr->ptr.Get(iso)->GetSourceURL());
+}
+
+const char* UnboundScriptGetSourceMappingURL(Isolate* iso,
+                                             UnboundScriptPtr us_ptr) {
+  ISOLATE_SCOPE(iso);
+  return CopyURL(iso, us_ptr->ptr.Get(iso)->GetSourceMappingURL());
+}
@@ -37,3 +37,39 @@ func (u *UnboundScript) CreateCodeCache() *CompilerCachedData {
 	C.ScriptCompilerCachedDataDelete(rtn)
 	return cachedData
 }
+
+// CompileHints returns the positions of the functions called so far by runs
+// of a script compiled with CompileOptions.ProduceCompileHints, or nil if the
+// script wasn't. Pass them as CompileOptions.CompileHints when compiling the
+// same source again, e.g. in a new isolate, to compile those functions eagerly.
+func (u *UnboundScript) CompileHints() []int {
+	rtn := C.UnboundScriptGetCompileHints(u.iso.ptr, u.ptr)
+	if rtn.positions == nil {
+		return nil
+	}
+	defer C.free(unsafe.Pointer(rtn.positions))
+	positions := unsafe.Slice(rtn.positions, rtn.count)
+	hints := make([]int, len(positions))
+	for i, pos := range positions {
+		hints[i] = int(pos)
+	}
+	return hints
+}
+
+// SourceURL returns the URL given by a `//# sourceURL=` comment in the
+// script, or "" if there is none.
+func (u *UnboundScript) SourceURL() string {
+	s := C.UnboundScriptGetSourceURL(u.iso.ptr, u.ptr)
+	defer C.free(unsafe.Pointer(s))
+	return C.GoString(s)
+}
+
+// SourceMappingURL returns the URL of the source map given by a
+// `//# sourceMappingURL=` comment in the script, or "" if there is none.
+// Tools use the source map to map positions in stack traces back to the
+// original source.
+func (u *UnboundScript) SourceMappingURL() string {
+	s := C.UnboundScriptGetSourceMappingURL(u.iso.ptr, u.ptr)
+	defer C.free(unsafe.Pointer(s))
+	return C.GoString(s)
+}
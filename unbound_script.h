@@ -54,12 +54,23 @@ typedef struct {
   int rejected;
 } ScriptCompilerCachedData;
 
+typedef struct {
+  int* positions;
+  int count;
+} RtnCompileHints;
+
 extern ScriptCompilerCachedData* UnboundScriptCreateCodeCache(
     IsolatePtr iso_ptr,
     UnboundScriptPtr us_ptr);
 extern void ScriptCompilerCachedDataDelete(
     ScriptCompilerCachedData* cached_data);
 extern RtnValue UnboundScriptRun(ContextPtr ctx_ptr, UnboundScriptPtr us_ptr);
+extern RtnCompileHints UnboundScriptGetCompileHints(IsolatePtr iso_ptr,
+                                                    UnboundScriptPtr us_ptr);
+extern const char* UnboundScriptGetSourceURL(IsolatePtr iso_ptr,
+                                             UnboundScriptPtr us_ptr);
+extern const char* UnboundScriptGetSourceMappingURL(IsolatePtr iso_ptr,
+                                                    UnboundScriptPtr us_ptr);
 
 #ifdef __cplusplus
 }  // extern "C"
@@ -45,3 +45,28 @@ func TestUnboundScriptRun_OnlyInTheSameIsolate(t *testing.T) {
 		t.Error("expected panic running unbound script in a context belonging to a different isolate")
 	}
 }
+
+func TestUnboundScriptSourceURLs(t *testing.T) {
+	t.Parallel()
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	us, err := iso.CompileUnboundScript(`
+		function add(a, b) { return a + b; }
+		//# sourceURL=webpack://app/add.js
+		//# sourceMappingURL=add.js.map
+	`, "bundle.js", v8.CompileOptions{})
+	fatalIf(t, err)
+	if got := us.SourceURL(); got != "webpack://app/add.js" {
+		t.Errorf("unexpected source URL %q", got)
+	}
+	if got := us.SourceMappingURL(); got != "add.js.map" {
+		t.Errorf("unexpected source mapping URL %q", got)
+	}
+
+	us, err = iso.CompileUnboundScript(`1 + 1`, "plain.js", v8.CompileOptions{})
+	fatalIf(t, err)
+	if us.SourceURL() != "" || us.SourceMappingURL() != "" {
+		t.Errorf("expected no URLs, got %q and %q", us.SourceURL(), us.SourceMappingURL())
+	}
+}
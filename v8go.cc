@@ -6,6 +6,7 @@
 
 #include <stdio.h>
 
+#include <algorithm>
 #include <cstdlib>
 #include <cstring>
 #include "utils.h"
@@ -23,6 +24,19 @@ void FunctionTemplateCallback(const FunctionCallbackInfo<Value>& info);
 const int ScriptCompilerNoCompileOptions = ScriptCompiler::kNoCompileOptions;
 const int ScriptCompilerConsumeCodeCache = ScriptCompiler::kConsumeCodeCache;
 const int ScriptCompilerEagerCompile = ScriptCompiler::kEagerCompile;
+const int ScriptCompilerProduceCompileHints =
+    ScriptCompiler::kProduceCompileHints;
+const int ScriptCompilerConsumeCompileHints =
+    ScriptCompiler::kConsumeCompileHints;
+
+// Tells V8 to compile the function at position eagerly if it is one of the
+// compile hints of the CompileOptions passed as data.
+static bool ConsumeCompileHint(int position, void* data) {
+  CompileOptions* opts = static_cast<CompileOptions*>(data);
+  return std::binary_search(opts->compileHints,
+                            opts->compileHints + opts->compileHintsCount,
+                            position);
+}
 
 m_unboundScript* tracked_unbound_script(m_ctx* ctx, m_unboundScript* us) {
   ctx->unboundScripts.push_back(us);
@@ -66,19 +80,27 @@ RtnUnboundScript IsolateCompileUnboundScript(IsolatePtr iso,
 
   ScriptOrigin script_origin(ogn);
 
-  ScriptCompiler::Source source(src, script_origin, cached_data);
-
   Local<UnboundScript> unbound_script;
-  if (!ScriptCompiler::CompileUnboundScript(iso, &source, option)
-           .ToLocal(&unbound_script)) {
+  bool compiled;
+  if (option == ScriptCompiler::kConsumeCompileHints) {
+    ScriptCompiler::Source source(src, script_origin, ConsumeCompileHint,
+                                  &opts);
+    compiled = ScriptCompiler::CompileUnboundScript(iso, &source, option)
+                   .ToLocal(&unbound_script);
+  } else {
+    // source owns cached_data, so check it before source goes out of scope.
+    ScriptCompiler::Source source(src, script_origin, cached_data);
+    compiled = ScriptCompiler::CompileUnboundScript(iso, &source, option)
+                   .ToLocal(&unbound_script);
+    if (cached_data) {
+      rtn.cachedDataRejected = cached_data->rejected;
+    }
+  }
+  if (!compiled) {
     rtn.error = ExceptionError(try_catch, iso, local_ctx);
     return rtn;
   };
 
-  if (cached_data) {
-    rtn.cachedDataRejected = cached_data->rejected;
-  }
-
   m_unboundScript* us = new m_unboundScript;
   us->ptr.Reset(iso, unbound_script);
   rtn.ptr = tracked_unbound_script(ctx, us);
@@ -106,7 +128,7 @@ ValuePtr IsolateThrowException(IsolatePtr iso, ValuePtr value) {
 
 CPUProfiler* NewCPUProfiler(IsolatePtr iso_ptr) {
   Isolate* iso = static_cast<Isolate*>(iso_ptr);
-  Locker locker(iso);
+  IsolateLocker locker(iso);
   Isolate::Scope isolate_scope(iso);
   HandleScope handle_scope(iso);
 
@@ -130,7 +152,7 @@ void CPUProfilerStartProfiling(CPUProfiler* profiler, const char* title) {
     return;
   }
 
-  Locker locker(profiler->iso);
+  IsolateLocker locker(profiler->iso);
   Isolate::Scope isolate_scope(profiler->iso);
   HandleScope handle_scope(profiler->iso);
 
@@ -168,7 +190,7 @@ CPUProfile* CPUProfilerStopProfiling(CPUProfiler* profiler, const char* title) {
     return nullptr;
   }
 
-  Locker locker(profiler->iso);
+  IsolateLocker locker(profiler->iso);
   Isolate::Scope isolate_scope(profiler->iso);
   HandleScope handle_scope(profiler->iso);
 
@@ -308,7 +330,7 @@ const char* JSONStringify(ContextPtr ctx, ValuePtr val) {
     iso = val->iso;
   }
 
-  Locker locker(iso);
+  IsolateLocker locker(iso);
   Isolate::Scope isolate_scope(iso);
   HandleScope handle_scope(iso);
 
@@ -344,6 +366,32 @@ const char* ExceptionGetMessageString(ValuePtr ptr) {
   return CopyString(utf8);
 }
 
+RtnValue ContextNewError(ContextPtr ctx,
+                         ErrorTypeIndex idx,
+                         const char* message) {
+  LOCAL_CONTEXT(ctx);
+  RtnValue rtn = {};
+
+  Local<String> local_msg;
+  if (!String::NewFromUtf8(iso, message, NewStringType::kNormal)
+           .ToLocal(&local_msg)) {
+    rtn.error = ExceptionError(try_catch, iso, local_ctx);
+    return rtn;
+  }
+  Local<Value> err = NewErrorOfType(idx, local_msg);
+  if (err.IsEmpty()) {
+    rtn.error.msg = CopyString("unknown error type");
+    return rtn;
+  }
+  m_value* val = new m_value;
+  val->id = 0;
+  val->iso = iso;
+  val->ctx = ctx;
+  val->ptr = Global<Value>(iso, err);
+  rtn.value = tracked_value(ctx, val);
+  return rtn;
+}
+
 /********** Promise **********/
 
 RtnValue NewPromiseResolver(ContextPtr ctx) {
@@ -476,6 +524,43 @@ RtnValue PromiseCatch(ValuePtr ptr, int callback_ref) {
   return rtn;
 }
 
+RtnValue PromiseThenFunctions(ValuePtr ptr,
+                              ValuePtr on_fulfilled,
+                              ValuePtr on_rejected) {
+  LOCAL_VALUE(ptr)
+  RtnValue rtn = {};
+  Local<Promise> promise = value.As<Promise>();
+  MaybeLocal<Promise> maybe_result;
+  if (on_fulfilled == nullptr) {
+    Local<Function> on_rejected_func =
+        on_rejected->ptr.Get(iso).As<Function>();
+    maybe_result = promise->Catch(local_ctx, on_rejected_func);
+  } else if (on_rejected == nullptr) {
+    Local<Function> on_fulfilled_func =
+        on_fulfilled->ptr.Get(iso).As<Function>();
+    maybe_result = promise->Then(local_ctx, on_fulfilled_func);
+  } else {
+    Local<Function> on_fulfilled_func =
+        on_fulfilled->ptr.Get(iso).As<Function>();
+    Local<Function> on_rejected_func =
+        on_rejected->ptr.Get(iso).As<Function>();
+    maybe_result =
+        promise->Then(local_ctx, on_fulfilled_func, on_rejected_func);
+  }
+  Local<Promise> result;
+  if (!maybe_result.ToLocal(&result)) {
+    rtn.error = ExceptionError(try_catch, iso, local_ctx);
+    return rtn;
+  }
+  m_value* result_val = new m_value;
+  result_val->id = 0;
+  result_val->iso = iso;
+  result_val->ctx = ctx;
+  result_val->ptr = Global<Value>(iso, result);
+  rtn.value = tracked_value(ctx, result_val);
+  return rtn;
+}
+
 ValuePtr PromiseResult(ValuePtr ptr) {
   LOCAL_VALUE(ptr)
   Local<Promise> promise = value.As<Promise>();
@@ -603,4 +688,54 @@ size_t BackingStoreByteLength(BackingStorePtr ptr) {
   }
   return ptr->backing_store->ByteLength();
 }
+
+BackingStorePtr NewBackingStore(IsolatePtr iso, size_t byte_length) {
+  ISOLATE_SCOPE(iso);
+  std::shared_ptr<BackingStore> backing_store =
+      ArrayBuffer::NewBackingStore(iso, byte_length);
+  return new v8BackingStore(std::move(backing_store));
+}
+
+RtnValue NewArrayBufferWithBackingStore(ContextPtr ctx, BackingStorePtr ptr) {
+  LOCAL_CONTEXT(ctx);
+  RtnValue rtn = {};
+
+  Local<ArrayBuffer> buffer = ArrayBuffer::New(iso, ptr->backing_store);
+  m_value* val = new m_value;
+  val->id = 0;
+  val->iso = iso;
+  val->ctx = ctx;
+  val->ptr = Global<Value>(iso, buffer);
+  rtn.value = tracked_value(ctx, val);
+  return rtn;
+}
+
+int ArrayBufferDetach(ValuePtr ptr) {
+  LOCAL_VALUE(ptr);
+  Local<ArrayBuffer> buffer = value.As<ArrayBuffer>();
+  if (!buffer->IsDetachable()) {
+    return 0;
+  }
+  return buffer->Detach(Local<Value>()).FromMaybe(false);
+}
+
+// Copies the contents of an ArrayBuffer or ArrayBufferView into dest, up to
+// length bytes, and returns the byte length of the value. Pass a null dest to
+// only get the length.
+size_t ValueCopyBytes(ValuePtr ptr, void* dest, size_t length) {
+  LOCAL_VALUE(ptr);
+  if (value->IsArrayBufferView()) {
+    Local<ArrayBufferView> view = value.As<ArrayBufferView>();
+    if (dest != nullptr) {
+      view->CopyContents(dest, length);
+    }
+    return view->ByteLength();
+  }
+  Local<ArrayBuffer> buffer = value.As<ArrayBuffer>();
+  size_t byte_length = buffer->ByteLength();
+  if (dest != nullptr && buffer->Data() != nullptr) {
+    memcpy(dest, buffer->Data(), std::min(length, byte_length));
+  }
+  return byte_length;
+}
 }
@@ -11,6 +11,7 @@ package v8go
 // #include <stdlib.h>
 import "C"
 import (
+	"errors"
 	"strings"
 	"sync"
 	"unsafe"
@@ -29,15 +30,89 @@ func SetFlags(flags ...string) {
 	cflags := C.CString(strings.Join(flags, " "))
 	C.SetFlags(cflags)
 	C.free(unsafe.Pointer(cflags))
+
+	flagsMutex.Lock()
+	setFlags = append(setFlags, flags...)
+	flagsMutex.Unlock()
+}
+
+// FeatureFlags returns the flags that have been passed to SetFlags, in the
+// order they were set. V8 has no public API to query the state of its flags,
+// so flags that are enabled by default, e.g. shipped harmony features, are not
+// listed; later flags override earlier ones.
+func FeatureFlags() []string {
+	flagsMutex.Lock()
+	defer flagsMutex.Unlock()
+	return append([]string(nil), setFlags...)
+}
+
+// PlatformOptions configures the V8 platform, which is shared by all
+// isolates in the process.
+type PlatformOptions struct {
+	// ThreadPoolSize is the number of worker threads V8 uses for background
+	// tasks such as concurrent compilation and garbage collection. When zero,
+	// V8 picks a size based on the number of CPUs of the machine, which may be
+	// too many in a container with CPU limits.
+	ThreadPoolSize int
+
+	// SingleThreaded disables the worker thread pool and V8's background
+	// compilation and garbage collection threads, so that all tasks run on the
+	// foreground thread when they are pumped with Isolate.PumpMessageLoop. This
+	// makes timing deterministic, which is mostly useful for tests.
+	// ThreadPoolSize is ignored when set.
+	SingleThreaded bool
+
+	// Predictable passes V8's --predictable flag, which, together with
+	// SingleThreaded, makes V8 run its background work such as garbage
+	// collection at deterministic points. Combine it with
+	// IsolateOptions.PredictableMode for reproducible script output.
+	Predictable bool
+}
+
+// InitializeWithPlatform initializes V8 with the given platform options. It
+// must be called before any Isolate is created, as V8 is otherwise initialized
+// with the default options on first use; an error is returned if V8 has
+// already been initialized.
+func InitializeWithPlatform(opts PlatformOptions) error {
+	if opts.ThreadPoolSize < 0 {
+		return errors.New("v8go: thread pool size must not be negative")
+	}
+	initialized := false
+	v8once.Do(func() {
+		initialize(opts)
+		initialized = true
+	})
+	if !initialized {
+		return errors.New("v8go: V8 has already been initialized")
+	}
+	return nil
 }
 
 func initializeIfNecessary() {
 	v8once.Do(func() {
-		cflags := C.CString("--no-freeze_flags_after_init")
-		defer C.free(unsafe.Pointer(cflags))
-		C.SetFlags(cflags)
-		C.Init()
+		initialize(PlatformOptions{})
 	})
 }
 
+func initialize(opts PlatformOptions) {
+	flags := "--no-freeze_flags_after_init"
+	var singleThreaded int
+	if opts.SingleThreaded {
+		flags += " --single-threaded"
+		singleThreaded = 1
+	}
+	if opts.Predictable {
+		flags += " --predictable"
+	}
+	cflags := C.CString(flags)
+	defer C.free(unsafe.Pointer(cflags))
+	C.SetFlags(cflags)
+	C.Init(C.int(opts.ThreadPoolSize), C.int(singleThreaded))
+}
+
 var v8once sync.Once
+
+var (
+	flagsMutex sync.Mutex
+	setFlags   []string
+)
@@ -77,7 +77,7 @@ typedef struct {
   int64_t endTime;
 } CPUProfile;
 
-extern void Init();
+extern void Init(int thread_pool_size, int single_threaded);
 
 extern CPUProfiler* NewCPUProfiler(IsolatePtr iso_ptr);
 extern void CPUProfilerDispose(CPUProfiler* ptr);
@@ -105,6 +105,9 @@ extern int TemplateSetAnyTemplate(TemplatePtr ptr,
                                   int attributes);
 
 const char* ExceptionGetMessageString(ValuePtr ptr);
+extern RtnValue ContextNewError(ContextPtr ctx_ptr,
+                                ErrorTypeIndex idx,
+                                const char* message);
 
 extern RtnValue NewPromiseResolver(ContextPtr ctx_ptr);
 extern ValuePtr PromiseResolverGetPromise(ValuePtr ptr);
@@ -114,6 +117,9 @@ int PromiseState(ValuePtr ptr);
 RtnValue PromiseThen(ValuePtr ptr, int callback_ref);
 RtnValue PromiseThen2(ValuePtr ptr, int on_fulfilled_ref, int on_rejected_ref);
 RtnValue PromiseCatch(ValuePtr ptr, int callback_ref);
+RtnValue PromiseThenFunctions(ValuePtr ptr,
+                              ValuePtr on_fulfilled,
+                              ValuePtr on_rejected);
 extern ValuePtr PromiseResult(ValuePtr ptr);
 
 extern RtnValue FunctionCall(ValuePtr ptr,
@@ -20,6 +20,30 @@ func TestVersion(t *testing.T) {
 	}
 }
 
+func TestFeatureFlags(t *testing.T) {
+	// Flags are global to the process, so this test doesn't run in parallel
+	// and restores the flag it sets.
+	restore := "--noexpose_gc"
+	for _, f := range v8.FeatureFlags() {
+		if f == "--expose_gc" || f == "--noexpose_gc" {
+			restore = f
+		}
+	}
+	t.Cleanup(func() { v8.SetFlags(restore) })
+
+	v8.SetFlags("--noexpose_gc")
+	flags := v8.FeatureFlags()
+	found := false
+	for _, f := range flags {
+		if f == "--noexpose_gc" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected --noexpose_gc in feature flags, got %v", flags)
+	}
+}
+
 func TestSetFlag(t *testing.T) {
 	t.Parallel()
 	ctx := v8.NewContext()
@@ -37,3 +61,16 @@ func TestSetFlag(t *testing.T) {
 		t.Errorf("expected <nil> error, but got: %v", err)
 	}
 }
+
+func TestInitializeWithPlatform(t *testing.T) {
+	t.Parallel()
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	if err := v8.InitializeWithPlatform(v8.PlatformOptions{ThreadPoolSize: 2}); err == nil {
+		t.Error("expected error initializing after an isolate was created, got <nil>")
+	}
+	if err := v8.InitializeWithPlatform(v8.PlatformOptions{ThreadPoolSize: -1}); err == nil {
+		t.Error("expected error for a negative thread pool size, got <nil>")
+	}
+}
@@ -8,10 +8,11 @@
 
 #include "context.h"
 #include "isolate.h"
+#include "isolate-macros.h"
 
 #define LOCAL_VALUE(val)                   \
   Isolate* iso = val->iso;                 \
-  Locker locker(iso);                      \
+  IsolateLocker locker(iso);               \
   Isolate::Scope isolate_scope(iso);       \
   HandleScope handle_scope(iso);           \
   TryCatch try_catch(iso);                 \
@@ -26,4 +27,23 @@
   Context::Scope context_scope(local_ctx); \
   Local<Value> value = val->ptr.Get(iso);
 
+// Like LOCAL_VALUE, but enters ctx_ptr if it is not null rather than the
+// context the value belongs to.
+#define LOCAL_VALUE_IN_CONTEXT(ctx_ptr, val)    \
+  Isolate* iso = val->iso;                      \
+  IsolateLocker locker(iso);                    \
+  Isolate::Scope isolate_scope(iso);            \
+  HandleScope handle_scope(iso);                \
+  TryCatch try_catch(iso);                      \
+  m_ctx* ctx = ctx_ptr;                         \
+  if (ctx == nullptr) {                         \
+    ctx = val->ctx;                             \
+  }                                             \
+  if (ctx == nullptr) {                         \
+    ctx = isolateInternalContext(iso);          \
+  }                                             \
+  Local<Context> local_ctx = ctx->ptr.Get(iso); \
+  Context::Scope context_scope(local_ctx);      \
+  Local<Value> value = val->ptr.Get(iso);
+
 #endif
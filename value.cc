@@ -1,6 +1,20 @@
+#include <cmath>
+#include <cstring>
+#include <functional>
+#include <limits>
+#include <sstream>
+#include <string>
+
 #include "value.h"
 #include "context.h"
+#include "deps/include/v8-array-buffer.h"
+#include "deps/include/v8-container.h"
 #include "deps/include/v8-context.h"
+#include "deps/include/v8-external.h"
+#include "deps/include/v8-function.h"
+#include "deps/include/v8-primitive-object.h"
+#include "deps/include/v8-promise.h"
+#include "deps/include/v8-typed-array.h"
 #include "isolate-macros.h"
 #include "utils.h"
 #include "value-macros.h"
@@ -21,6 +35,12 @@ void ValueRelease(ValuePtr ptr) {
   delete ptr;
 }
 
+void ValuesRelease(ValuePtr* ptrs, int count) {
+  for (int i = 0; i < count; ++i) {
+    ValueRelease(ptrs[i]);
+  }
+}
+
 ValuePtr NewValueInteger(IsolatePtr iso, int32_t v) {
   ISOLATE_SCOPE_INTERNAL_CONTEXT(iso);
   m_value* val = new m_value;
@@ -41,13 +61,15 @@ ValuePtr NewValueIntegerFromUnsigned(IsolatePtr iso, uint32_t v) {
   return tracked_value(ctx, val);
 }
 
-RtnValue NewValueString(IsolatePtr iso, const char* v, int v_length) {
+static RtnValue NewValueStringOfType(IsolatePtr iso,
+                                     const char* v,
+                                     int v_length,
+                                     NewStringType type) {
   ISOLATE_SCOPE_INTERNAL_CONTEXT(iso);
   TryCatch try_catch(iso);
   RtnValue rtn = {};
   Local<String> str;
-  if (!String::NewFromUtf8(iso, v, NewStringType::kNormal, v_length)
-           .ToLocal(&str)) {
+  if (!String::NewFromUtf8(iso, v, type, v_length).ToLocal(&str)) {
     rtn.error = ExceptionError(try_catch, iso, ctx->ptr.Get(iso));
     return rtn;
   }
@@ -60,6 +82,26 @@ RtnValue NewValueString(IsolatePtr iso, const char* v, int v_length) {
   return rtn;
 }
 
+RtnValue NewValueString(IsolatePtr iso, const char* v, int v_length) {
+  return NewValueStringOfType(iso, v, v_length, NewStringType::kNormal);
+}
+
+RtnValue NewValueInternalizedString(IsolatePtr iso,
+                                    const char* v,
+                                    int v_length) {
+  return NewValueStringOfType(iso, v, v_length, NewStringType::kInternalized);
+}
+
+ValuePtr NewValueExternal(IsolatePtr iso, uintptr_t v) {
+  ISOLATE_SCOPE_INTERNAL_CONTEXT(iso);
+  m_value* val = new m_value;
+  val->id = 0;
+  val->iso = iso;
+  val->ctx = ctx;
+  val->ptr = Global<Value>(iso, External::New(iso, reinterpret_cast<void*>(v)));
+  return tracked_value(ctx, val);
+}
+
 ValuePtr NewValueNull(IsolatePtr iso) {
   ISOLATE_SCOPE_INTERNAL_CONTEXT(iso);
   m_value* val = new m_value;
@@ -152,34 +194,9 @@ ValuePtr NewValueError(IsolatePtr iso,
   Context::Scope context_scope(local_ctx);
 
   Local<String> local_msg = String::NewFromUtf8(iso, message).ToLocalChecked();
-  Local<Value> v;
-  switch (idx) {
-    case ERROR_RANGE:
-      v = Exception::RangeError(local_msg);
-      break;
-    case ERROR_REFERENCE:
-      v = Exception::ReferenceError(local_msg);
-      break;
-    case ERROR_SYNTAX:
-      v = Exception::SyntaxError(local_msg);
-      break;
-    case ERROR_TYPE:
-      v = Exception::TypeError(local_msg);
-      break;
-    case ERROR_WASM_COMPILE:
-      v = Exception::WasmCompileError(local_msg);
-      break;
-    case ERROR_WASM_LINK:
-      v = Exception::WasmLinkError(local_msg);
-      break;
-    case ERROR_WASM_RUNTIME:
-      v = Exception::WasmRuntimeError(local_msg);
-      break;
-    case ERROR_GENERIC:
-      v = Exception::Error(local_msg);
-      break;
-    default:
-      return nullptr;
+  Local<Value> v = NewErrorOfType(idx, local_msg);
+  if (v.IsEmpty()) {
+    return nullptr;
   }
   m_value* val = new m_value;
   val->id = 0;
@@ -235,6 +252,81 @@ RtnString ValueToDetailString(ValuePtr ptr) {
   return rtn;
 }
 
+const char* ValueDebugString(ValuePtr ptr) {
+  LOCAL_VALUE(ptr);
+  std::ostringstream sb;
+  // Only internal state is read, so no JS runs, e.g. getters or proxy traps.
+  if (value->IsNull()) {
+    sb << "null";
+  } else if (value->IsString()) {
+    sb << "string(" << value.As<String>()->Length() << ")";
+  } else if (!value->IsObject()) {
+    String::Utf8Value type(iso, value->TypeOf(iso));
+    sb << *type;
+  } else if (value->IsProxy()) {
+    sb << "Proxy";
+  } else {
+    Local<Object> obj = value.As<Object>();
+    String::Utf8Value ctor(iso, obj->GetConstructorName());
+    sb << *ctor;
+    if (value->IsArray()) {
+      sb << "(" << value.As<Array>()->Length() << ")";
+    } else if (value->IsTypedArray()) {
+      sb << "(" << value.As<TypedArray>()->Length() << ")";
+    } else if (value->IsArrayBufferView()) {
+      sb << "(" << value.As<ArrayBufferView>()->ByteLength() << ")";
+    } else if (value->IsArrayBuffer()) {
+      sb << "(" << value.As<ArrayBuffer>()->ByteLength() << ")";
+    } else if (value->IsSharedArrayBuffer()) {
+      sb << "(" << value.As<SharedArrayBuffer>()->ByteLength() << ")";
+    } else if (value->IsMap()) {
+      sb << "(" << value.As<Map>()->Size() << ")";
+    } else if (value->IsSet()) {
+      sb << "(" << value.As<Set>()->Size() << ")";
+    } else if (value->IsPromise()) {
+      switch (value.As<Promise>()->State()) {
+        case Promise::kPending:
+          sb << "<pending>";
+          break;
+        case Promise::kFulfilled:
+          sb << "<fulfilled>";
+          break;
+        case Promise::kRejected:
+          sb << "<rejected>";
+          break;
+      }
+    } else if (value->IsFunction()) {
+      String::Utf8Value name(iso, value.As<Function>()->GetDebugName());
+      sb << " " << (name.length() > 0 ? *name : "(anonymous)");
+    }
+  }
+  return CopyString(sb.str());
+}
+
+RtnString ValueObjectProtoToString(ContextPtr ctx_ptr, ValuePtr ptr) {
+  LOCAL_VALUE_IN_CONTEXT(ctx_ptr, ptr);
+  RtnString rtn = {0};
+  // Object.prototype.toString doesn't convert undefined and null to objects.
+  if (value->IsUndefined() || value->IsNull()) {
+    std::string tag =
+        value->IsUndefined() ? "[object Undefined]" : "[object Null]";
+    rtn.data = CopyString(tag);
+    rtn.length = tag.length();
+    return rtn;
+  }
+  Local<Object> obj;
+  Local<String> str;
+  if (!value->ToObject(local_ctx).ToLocal(&obj) ||
+      !obj->ObjectProtoToString(local_ctx).ToLocal(&str)) {
+    rtn.error = ExceptionError(try_catch, iso, local_ctx);
+    return rtn;
+  }
+  String::Utf8Value tag(iso, str);
+  rtn.data = CopyString(tag);
+  rtn.length = tag.length();
+  return rtn;
+}
+
 RtnString ValueToString(ValuePtr ptr) {
   LOCAL_VALUE(ptr);
   RtnString rtn = {0};
@@ -255,6 +347,58 @@ uint32_t ValueToUint32(ValuePtr ptr) {
   return value->Uint32Value(local_ctx).ToChecked();
 }
 
+RtnInt32 ValueInt32Value(ValuePtr ptr) {
+  LOCAL_VALUE(ptr);
+  RtnInt32 rtn = {};
+  if (!value->Int32Value(local_ctx).To(&rtn.value)) {
+    rtn.failed = 1;
+    rtn.error = ExceptionError(try_catch, iso, local_ctx);
+  }
+  return rtn;
+}
+
+RtnUint32 ValueUint32Value(ValuePtr ptr) {
+  LOCAL_VALUE(ptr);
+  RtnUint32 rtn = {};
+  if (!value->Uint32Value(local_ctx).To(&rtn.value)) {
+    rtn.failed = 1;
+    rtn.error = ExceptionError(try_catch, iso, local_ctx);
+  }
+  return rtn;
+}
+
+int64_t ValueBigIntToInt64(ValuePtr ptr, int* lossless) {
+  LOCAL_VALUE(ptr);
+  bool ok = false;
+  int64_t i = value.As<BigInt>()->Int64Value(&ok);
+  *lossless = ok;
+  return i;
+}
+
+uint64_t ValueBigIntToUint64(ValuePtr ptr, int* lossless) {
+  LOCAL_VALUE(ptr);
+  bool ok = false;
+  uint64_t u = value.As<BigInt>()->Uint64Value(&ok);
+  *lossless = ok;
+  return u;
+}
+
+void* ValueToExternal(ValuePtr ptr) {
+  LOCAL_VALUE(ptr);
+  if (!value->IsExternal()) {
+    return nullptr;
+  }
+  return value.As<External>()->Value();
+}
+
+uintptr_t ValueToExternalHandle(ValuePtr ptr) {
+  LOCAL_VALUE(ptr);
+  if (!value->IsExternal()) {
+    return 0;
+  }
+  return reinterpret_cast<uintptr_t>(value.As<External>()->Value());
+}
+
 ValueBigInt ValueToBigInt(ValuePtr ptr) {
   LOCAL_VALUE(ptr);
   Local<BigInt> bint;
@@ -287,6 +431,95 @@ RtnValue ValueToObject(ValuePtr ptr) {
   return rtn;
 }
 
+RtnValue ValueCoerceToString(ContextPtr ctx_ptr, ValuePtr ptr) {
+  LOCAL_VALUE_IN_CONTEXT(ctx_ptr, ptr);
+  RtnValue rtn = {};
+  Local<String> str;
+  if (!value->ToString(local_ctx).ToLocal(&str)) {
+    rtn.error = ExceptionError(try_catch, iso, local_ctx);
+    return rtn;
+  }
+  m_value* new_val = new m_value;
+  new_val->id = 0;
+  new_val->iso = iso;
+  new_val->ctx = ctx;
+  new_val->ptr = Global<Value>(iso, str);
+  rtn.value = tracked_value(ctx, new_val);
+  return rtn;
+}
+
+RtnValue ValueCoerceToNumber(ContextPtr ctx_ptr, ValuePtr ptr) {
+  LOCAL_VALUE_IN_CONTEXT(ctx_ptr, ptr);
+  RtnValue rtn = {};
+  Local<Number> num;
+  if (!value->ToNumber(local_ctx).ToLocal(&num)) {
+    rtn.error = ExceptionError(try_catch, iso, local_ctx);
+    return rtn;
+  }
+  m_value* new_val = new m_value;
+  new_val->id = 0;
+  new_val->iso = iso;
+  new_val->ctx = ctx;
+  new_val->ptr = Global<Value>(iso, num);
+  rtn.value = tracked_value(ctx, new_val);
+  return rtn;
+}
+
+RtnValue ValueCoerceToObject(ContextPtr ctx_ptr, ValuePtr ptr) {
+  LOCAL_VALUE_IN_CONTEXT(ctx_ptr, ptr);
+  RtnValue rtn = {};
+  Local<Object> obj;
+  if (!value->ToObject(local_ctx).ToLocal(&obj)) {
+    rtn.error = ExceptionError(try_catch, iso, local_ctx);
+    return rtn;
+  }
+  m_value* new_val = new m_value;
+  new_val->id = 0;
+  new_val->iso = iso;
+  new_val->ctx = ctx;
+  new_val->ptr = Global<Value>(iso, obj);
+  rtn.value = tracked_value(ctx, new_val);
+  return rtn;
+}
+
+RtnValue StringConcat(ValuePtr* strs, int count) {
+  LOCAL_VALUE(strs[0]);
+  RtnValue rtn = {};
+
+  Local<String> result = value.As<String>();
+  for (int i = 1; i < count; i++) {
+    if (strs[i]->iso != iso) {
+      rtn.error.msg = CopyString("strings belong to different isolates");
+      return rtn;
+    }
+    // Concat returns an empty handle rather than throwing if the result
+    // would exceed the maximum string length.
+    result = String::Concat(iso, result, strs[i]->ptr.Get(iso).As<String>());
+    if (result.IsEmpty()) {
+      rtn.error.msg = CopyString("RangeError: Invalid string length");
+      return rtn;
+    }
+  }
+
+  m_value* val = new m_value;
+  val->id = 0;
+  val->iso = iso;
+  val->ctx = ctx;
+  val->ptr = Global<Value>(iso, result);
+  rtn.value = tracked_value(ctx, val);
+  return rtn;
+}
+
+ValuePtr ValueCoerceToBoolean(ValuePtr ptr) {
+  LOCAL_VALUE(ptr);
+  m_value* new_val = new m_value;
+  new_val->id = 0;
+  new_val->iso = iso;
+  new_val->ctx = ctx;
+  new_val->ptr = Global<Value>(iso, value->ToBoolean(iso));
+  return tracked_value(ctx, new_val);
+}
+
 int ValueSameValue(ValuePtr val1, ValuePtr val2) {
   Isolate* iso = val1->iso;
   ISOLATE_SCOPE(iso);
@@ -296,6 +529,42 @@ int ValueSameValue(ValuePtr val1, ValuePtr val2) {
   return value1->SameValue(value2);
 }
 
+int ValueGetIdentityHash(ValuePtr ptr) {
+  LOCAL_VALUE(ptr);
+  if (value->IsObject()) {
+    return value.As<Object>()->GetIdentityHash();
+  }
+  if (value->IsName()) {
+    // Strings hash by content, symbols by identity.
+    return value.As<Name>()->GetIdentityHash();
+  }
+  if (value->IsNumber()) {
+    // SameValue treats all NaNs as the same value, but not +0 and -0.
+    double d = value.As<Number>()->Value();
+    if (std::isnan(d)) {
+      d = std::numeric_limits<double>::quiet_NaN();
+    }
+    uint64_t bits;
+    memcpy(&bits, &d, sizeof(bits));
+    return static_cast<int>(bits ^ (bits >> 32));
+  }
+  if (value->IsBigInt()) {
+    String::Utf8Value str(iso, value);
+    return static_cast<int>(
+        std::hash<std::string>()(std::string(*str, str.length())));
+  }
+  if (value->IsTrue()) {
+    return 1;
+  }
+  if (value->IsFalse()) {
+    return 2;
+  }
+  if (value->IsNull()) {
+    return 3;
+  }
+  return 0;
+}
+
 int ValueIsUndefined(ValuePtr ptr) {
   LOCAL_VALUE(ptr);
   return value->IsUndefined();
@@ -341,6 +610,11 @@ int ValueIsFunction(ValuePtr ptr) {
   return value->IsFunction();
 }
 
+int ValueIsConstructor(ValuePtr ptr) {
+  LOCAL_VALUE(ptr);
+  return value->IsObject() && value.As<Object>()->IsConstructor();
+}
+
 int ValueIsObject(ValuePtr ptr) {
   LOCAL_VALUE(ptr);
   return value->IsObject();
@@ -406,6 +680,11 @@ int ValueIsSymbolObject(ValuePtr ptr) {
   return value->IsSymbolObject();
 }
 
+int ValueIsBooleanObject(ValuePtr ptr) {
+  LOCAL_VALUE(ptr);
+  return value->IsBooleanObject();
+}
+
 int ValueIsNativeError(ValuePtr ptr) {
   LOCAL_VALUE(ptr);
   return value->IsNativeError();
@@ -556,6 +835,36 @@ int ValueIsProxy(ValuePtr ptr) {
   return value->IsProxy();
 }
 
+int ValueIsWeakRef(ValuePtr ptr) {
+  LOCAL_VALUE(ptr);
+  return value->IsWeakRef();
+}
+
+ValuePtr ValueUnboxPrimitive(ValuePtr ptr) {
+  LOCAL_VALUE(ptr);
+  Local<Value> result;
+  if (value->IsNumberObject()) {
+    result = Number::New(iso, value.As<NumberObject>()->ValueOf());
+  } else if (value->IsStringObject()) {
+    result = value.As<StringObject>()->ValueOf();
+  } else if (value->IsBooleanObject()) {
+    result = Boolean::New(iso, value.As<BooleanObject>()->ValueOf());
+  } else if (value->IsSymbolObject()) {
+    result = value.As<SymbolObject>()->ValueOf();
+  } else if (value->IsBigIntObject()) {
+    result = value.As<BigIntObject>()->ValueOf();
+  } else {
+    return nullptr;
+  }
+
+  m_value* new_val = new m_value;
+  new_val->id = 0;
+  new_val->iso = iso;
+  new_val->ctx = ctx;
+  new_val->ptr = Global<Value>(iso, result);
+  return tracked_value(ctx, new_val);
+}
+
 int ValueIsWasmModuleObject(ValuePtr ptr) {
   LOCAL_VALUE(ptr);
   return value->IsWasmModuleObject();
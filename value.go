@@ -11,7 +11,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"math/big"
+	"runtime/cgo"
 	"unsafe"
 )
 
@@ -19,6 +21,9 @@ import (
 type Value struct {
 	ptr C.ValuePtr
 	ctx *Context
+	// shared is set for the values all users of an isolate share, such as
+	// the ones returned by Undefined and Null, which must never be released.
+	shared bool
 }
 
 // Valuer is an interface that reperesents anything that extends from a Value
@@ -33,16 +38,28 @@ func (v *Value) value() *Value {
 
 func newValueNull(iso *Isolate) *Value {
 	return &Value{
-		ptr: C.NewValueNull(iso.ptr),
+		ptr:    C.NewValueNull(iso.ptr),
+		shared: true,
 	}
 }
 
 func newValueUndefined(iso *Isolate) *Value {
 	return &Value{
-		ptr: C.NewValueUndefined(iso.ptr),
+		ptr:    C.NewValueUndefined(iso.ptr),
+		shared: true,
 	}
 }
 
+// NewPropertyKey creates an internalized JS string for use as a property key
+// with Object.GetKey. Internalized strings are unique per isolate, so V8 can
+// look up properties by them without hashing or comparing the name again.
+func NewPropertyKey(iso *Isolate, name string) (*Value, error) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	rtn := C.NewValueInternalizedString(iso.ptr, cname, C.int(len(name)))
+	return valueResult(nil, rtn)
+}
+
 // Undefined returns the `undefined` JS value
 func Undefined(iso *Isolate) *Value {
 	return iso.undefined
@@ -139,6 +156,29 @@ func NewValue(iso *Isolate, val interface{}) (*Value, error) {
 	return rtnVal, nil
 }
 
+// NewExternal creates an External value holding ptr, which JS code can pass
+// around but not inspect, and Value.External returns it unchanged. V8 keeps
+// ptr where the Go garbage collector can't see it, so it must not point to
+// memory allocated by Go; to pass a Go value through JS, use NewExternalHandle
+// instead.
+func NewExternal(iso *Isolate, ptr unsafe.Pointer) *Value {
+	if iso == nil {
+		panic("nil Isolate argument not supported")
+	}
+	return &Value{ptr: C.NewValueExternal(iso.ptr, C.uintptr_t(uintptr(ptr)))}
+}
+
+// NewExternalHandle creates an External value holding the cgo.Handle h, which
+// is the safe way to pass a Go value through JS opaquely, e.g. captured in a
+// closure, and recover it with Value.ExternalHandle. The caller remains
+// responsible for deleting the handle once JS no longer uses the value.
+func NewExternalHandle(iso *Isolate, h cgo.Handle) *Value {
+	if iso == nil {
+		panic("nil Isolate argument not supported")
+	}
+	return &Value{ptr: C.NewValueExternal(iso.ptr, C.uintptr_t(h))}
+}
+
 // Format implements the fmt.Formatter interface to provide a custom formatter
 // primarily to output the detail string (for debugging) with `%+v` verb.
 func (v *Value) Format(s fmt.State, verb rune) {
@@ -158,7 +198,7 @@ func (v *Value) Format(s fmt.State, verb rune) {
 
 // ArrayIndex attempts to converts a string to an array index. Returns ok false if conversion fails.
 func (v *Value) ArrayIndex() (idx uint32, ok bool) {
-	arrayIdx := C.ValueToArrayIndex(v.ptr)
+	arrayIdx := C.ValueToArrayIndex(v.valuePtr())
 	defer C.free(unsafe.Pointer(arrayIdx))
 	if arrayIdx == nil {
 		return 0, false
@@ -171,7 +211,7 @@ func (v *Value) BigInt() *big.Int {
 	if v == nil {
 		return nil
 	}
-	bint := C.ValueToBigInt(v.ptr)
+	bint := C.ValueToBigInt(v.valuePtr())
 	defer C.free(unsafe.Pointer(bint.word_array))
 	if bint.word_array == nil {
 		return nil
@@ -193,42 +233,69 @@ func (v *Value) BigInt() *big.Int {
 
 // Boolean perform the equivalent of `Boolean(value)` in JS. This can never fail.
 func (v *Value) Boolean() bool {
-	return C.ValueToBoolean(v.ptr) != 0
+	return C.ValueToBoolean(v.valuePtr()) != 0
+}
+
+// DebugString returns a concise description of the type of the value for
+// logging, e.g. "Array(3)", "Uint8Array(1024)", "Map(5)",
+// "Promise<pending>" or "Function handler": the constructor name of objects,
+// with their length, size or state where they have one, and the `typeof` of
+// other values, with the length of strings. Unlike DetailString, it never runs
+// JS, such as getters or proxy traps, so it is safe to call on any value.
+func (v *Value) DebugString() string {
+	s := C.ValueDebugString(v.valuePtr())
+	defer C.free(unsafe.Pointer(s))
+	return C.GoString(s)
 }
 
 // DetailString provide a string representation of this value usable for debugging.
 func (v *Value) DetailString() string {
-	rtn := C.ValueToDetailString(v.ptr)
+	rtn := C.ValueToDetailString(v.valuePtr())
 	if rtn.data == nil {
-		err := newJSError(rtn.error)
+		err := v.ctx.resultError(rtn.error)
 		panic(err) // TODO: Return a fallback value
 	}
 	defer C.free(unsafe.Pointer(rtn.data))
 	return C.GoStringN(rtn.data, rtn.length)
 }
 
+// ObjectProtoToString performs the equivalent of
+// `Object.prototype.toString.call(value)` in JS in the given context,
+// returning a tag such as "[object Array]". The tag can be customized by an
+// object with a `Symbol.toStringTag` property, whose getter may throw.
+// If ctx is nil, the context the value belongs to is used.
+func (v *Value) ObjectProtoToString(ctx *Context) (string, error) {
+	ctx = v.coercionContext(ctx)
+	rtn := C.ValueObjectProtoToString(ctx.contextPtr(), v.valuePtr())
+	if rtn.error.msg != nil {
+		return "", ctx.resultError(rtn.error)
+	}
+	defer C.free(unsafe.Pointer(rtn.data))
+	return C.GoStringN(rtn.data, rtn.length), nil
+}
+
 // Int32 perform the equivalent of `Number(value)` in JS and convert the result to a
 // signed 32-bit integer by performing the steps in https://tc39.es/ecma262/#sec-toint32.
 func (v *Value) Int32() int32 {
-	return int32(C.ValueToInt32(v.ptr))
+	return int32(C.ValueToInt32(v.valuePtr()))
 }
 
 // Integer perform the equivalent of `Number(value)` in JS and convert the result to an integer.
 // Negative values are rounded up, positive values are rounded down. NaN is converted to 0.
 // Infinite values yield undefined results.
 func (v *Value) Integer() int64 {
-	return int64(C.ValueToInteger(v.ptr))
+	return int64(C.ValueToInteger(v.valuePtr()))
 }
 
 // Number perform the equivalent of `Number(value)` in JS.
 func (v *Value) Number() float64 {
-	return float64(C.ValueToNumber(v.ptr))
+	return float64(C.ValueToNumber(v.valuePtr()))
 }
 
 // Object perform the equivalent of Object(value) in JS.
 // To just cast this value as an Object use AsObject() instead.
 func (v *Value) Object() *Object {
-	rtn := C.ValueToObject(v.ptr)
+	rtn := C.ValueToObject(v.valuePtr())
 	obj, err := objectResult(v.ctx, rtn)
 	if err != nil {
 		panic(err) // TODO: Return error
@@ -240,7 +307,7 @@ func (v *Value) Object() *Object {
 // are returned as-is, objects will return `[object Object]` and functions will
 // print their definition.
 func (v *Value) String() string {
-	s := C.ValueToString(v.ptr)
+	s := C.ValueToString(v.valuePtr())
 	defer C.free(unsafe.Pointer(s.data))
 	return C.GoStringN(s.data, C.int(s.length))
 }
@@ -248,305 +315,499 @@ func (v *Value) String() string {
 // Uint32 perform the equivalent of `Number(value)` in JS and convert the result to an
 // unsigned 32-bit integer by performing the steps in https://tc39.es/ecma262/#sec-touint32.
 func (v *Value) Uint32() uint32 {
-	return uint32(C.ValueToUint32(v.ptr))
+	return uint32(C.ValueToUint32(v.valuePtr()))
+}
+
+// Int32Value is like Int32, but returns an error instead of crashing if the
+// conversion throws, e.g. because `valueOf` throws.
+// error will be of type `JSError` if not nil.
+func (v *Value) Int32Value() (int32, error) {
+	rtn := C.ValueInt32Value(v.valuePtr())
+	if rtn.failed != 0 {
+		return 0, v.ctx.resultError(rtn.error)
+	}
+	return int32(rtn.value), nil
+}
+
+// Uint32Value is like Uint32, but returns an error instead of crashing if the
+// conversion throws, e.g. because `valueOf` throws.
+// error will be of type `JSError` if not nil.
+func (v *Value) Uint32Value() (uint32, error) {
+	rtn := C.ValueUint32Value(v.valuePtr())
+	if rtn.failed != 0 {
+		return 0, v.ctx.resultError(rtn.error)
+	}
+	return uint32(rtn.value), nil
+}
+
+// Int64 returns the value of a Number or BigInt as an int64. Unlike Integer,
+// it returns an error rather than a truncated or wrapped result if the value
+// is not an integer or doesn't fit an int64, or if it is of another type.
+func (v *Value) Int64() (int64, error) {
+	switch {
+	case v.IsBigInt():
+		var lossless C.int
+		i := C.ValueBigIntToInt64(v.valuePtr(), &lossless)
+		if lossless == 0 {
+			return 0, fmt.Errorf("v8go: %sn overflows int64", v)
+		}
+		return int64(i), nil
+	case v.IsNumber():
+		f := v.Number()
+		if f != math.Trunc(f) {
+			return 0, fmt.Errorf("v8go: %v is not an integer", f)
+		}
+		if f < math.MinInt64 || f >= math.MaxInt64 {
+			return 0, fmt.Errorf("v8go: %v overflows int64", f)
+		}
+		return int64(f), nil
+	}
+	return 0, errors.New("v8go: value is not a Number or BigInt")
+}
+
+// Uint64 is like Int64, but returns the value as a uint64, with an error for
+// negative values.
+func (v *Value) Uint64() (uint64, error) {
+	switch {
+	case v.IsBigInt():
+		var lossless C.int
+		u := C.ValueBigIntToUint64(v.valuePtr(), &lossless)
+		if lossless == 0 {
+			return 0, fmt.Errorf("v8go: %sn overflows uint64", v)
+		}
+		return uint64(u), nil
+	case v.IsNumber():
+		f := v.Number()
+		if f != math.Trunc(f) {
+			return 0, fmt.Errorf("v8go: %v is not an integer", f)
+		}
+		if f < 0 || f >= math.MaxUint64 {
+			return 0, fmt.Errorf("v8go: %v overflows uint64", f)
+		}
+		return uint64(f), nil
+	}
+	return 0, errors.New("v8go: value is not a Number or BigInt")
+}
+
+// External returns the pointer held by an External value created with
+// NewExternal, or nil if the value is not an External.
+func (v *Value) External() unsafe.Pointer {
+	return C.ValueToExternal(v.valuePtr())
+}
+
+// ExternalHandle returns the cgo.Handle held by an External value created with
+// NewExternalHandle, or an error if the value is not an External.
+func (v *Value) ExternalHandle() (cgo.Handle, error) {
+	if !v.IsExternal() {
+		return 0, errors.New("v8go: value is not an External")
+	}
+	return cgo.Handle(C.ValueToExternalHandle(v.valuePtr())), nil
+}
+
+// ToString performs the equivalent of `String(value)` in JS and returns the
+// resulting JS string. Unlike String, this invokes `toString` on objects in
+// the given context, and returns any error it throws.
+// If ctx is nil, the context the value belongs to is used.
+func (v *Value) ToString(ctx *Context) (*Value, error) {
+	ctx = v.coercionContext(ctx)
+	rtn := C.ValueCoerceToString(ctx.contextPtr(), v.valuePtr())
+	return valueResult(ctx, rtn)
+}
+
+// ToNumber performs the equivalent of `Number(value)` in JS and returns the
+// resulting JS number, invoking `valueOf` on objects in the given context.
+// If ctx is nil, the context the value belongs to is used.
+func (v *Value) ToNumber(ctx *Context) (*Value, error) {
+	ctx = v.coercionContext(ctx)
+	rtn := C.ValueCoerceToNumber(ctx.contextPtr(), v.valuePtr())
+	return valueResult(ctx, rtn)
+}
+
+// ToObject performs the equivalent of `Object(value)` in JS in the given
+// context, returning an error for null and undefined.
+// If ctx is nil, the context the value belongs to is used.
+func (v *Value) ToObject(ctx *Context) (*Object, error) {
+	ctx = v.coercionContext(ctx)
+	rtn := C.ValueCoerceToObject(ctx.contextPtr(), v.valuePtr())
+	return objectResult(ctx, rtn)
+}
+
+// ToBoolean performs the equivalent of `Boolean(value)` in JS and returns the
+// resulting JS boolean. This can never fail.
+func (v *Value) ToBoolean() *Value {
+	return &Value{ptr: C.ValueCoerceToBoolean(v.valuePtr()), ctx: v.ctx}
+}
+
+func (v *Value) coercionContext(ctx *Context) *Context {
+	if ctx == nil {
+		return v.ctx
+	}
+	return ctx
 }
 
 // SameValue returns true if the other value is the same value.
 // This is equivalent to `Object.is(v, other)` in JS.
 func (v *Value) SameValue(other *Value) bool {
-	return C.ValueSameValue(v.ptr, other.ptr) != 0
+	return C.ValueSameValue(v.valuePtr(), other.valuePtr()) != 0
+}
+
+// GetIdentityHash returns a hash of the value that is consistent with
+// SameValue: values for which SameValue is true have the same hash. Objects
+// and symbols hash by identity, with a hash V8 keeps for the lifetime of the
+// object, and other primitives hash by value. As different values may have
+// the same hash, a Go map keyed by the hash must compare the values in a
+// bucket with SameValue.
+func (v *Value) GetIdentityHash() int {
+	return int(C.ValueGetIdentityHash(v.valuePtr()))
 }
 
 // IsUndefined returns true if this value is the undefined value. See ECMA-262 4.3.10.
 func (v *Value) IsUndefined() bool {
-	return C.ValueIsUndefined(v.ptr) != 0
+	return C.ValueIsUndefined(v.valuePtr()) != 0
 }
 
 // IsNull returns true if this value is the null value. See ECMA-262 4.3.11.
 func (v *Value) IsNull() bool {
-	return C.ValueIsNull(v.ptr) != 0
+	return C.ValueIsNull(v.valuePtr()) != 0
 }
 
 // IsNullOrUndefined returns true if this value is either the null or the undefined value.
 // See ECMA-262 4.3.11. and 4.3.12
 // This is equivalent to `value == null` in JS.
 func (v *Value) IsNullOrUndefined() bool {
-	return C.ValueIsNullOrUndefined(v.ptr) != 0
+	return C.ValueIsNullOrUndefined(v.valuePtr()) != 0
 }
 
 // IsTrue returns true if this value is true.
 // This is not the same as `BooleanValue()`. The latter performs a conversion to boolean,
 // i.e. the result of `Boolean(value)` in JS, whereas this checks `value === true`.
 func (v *Value) IsTrue() bool {
-	return C.ValueIsTrue(v.ptr) != 0
+	return C.ValueIsTrue(v.valuePtr()) != 0
 }
 
 // IsFalse returns true if this value is false.
 // This is not the same as `!BooleanValue()`. The latter performs a conversion to boolean,
 // i.e. the result of `!Boolean(value)` in JS, whereas this checks `value === false`.
 func (v *Value) IsFalse() bool {
-	return C.ValueIsFalse(v.ptr) != 0
+	return C.ValueIsFalse(v.valuePtr()) != 0
 }
 
 // IsName returns true if this value is a symbol or a string.
 // This is equivalent to `typeof value === 'string' || typeof value === 'symbol'` in JS.
 func (v *Value) IsName() bool {
-	return C.ValueIsName(v.ptr) != 0
+	return C.ValueIsName(v.valuePtr()) != 0
 }
 
 // IsString returns true if this value is an instance of the String type. See ECMA-262 8.4.
 // This is equivalent to `typeof value === 'string'` in JS.
 func (v *Value) IsString() bool {
-	return C.ValueIsString(v.ptr) != 0
+	return C.ValueIsString(v.valuePtr()) != 0
 }
 
 // IsSymbol returns true if this value is a symbol.
 // This is equivalent to `typeof value === 'symbol'` in JS.
 func (v *Value) IsSymbol() bool {
-	return C.ValueIsSymbol(v.ptr) != 0
+	return C.ValueIsSymbol(v.valuePtr()) != 0
 }
 
 // IsFunction returns true if this value is a function.
 // This is equivalent to `typeof value === 'function'` in JS.
 func (v *Value) IsFunction() bool {
-	return C.ValueIsFunction(v.ptr) != 0
+	return C.ValueIsFunction(v.valuePtr()) != 0
+}
+
+// IsConstructor returns true if this value is a function that can be called
+// with `new`, e.g. with Function.NewInstance. Arrow functions, methods, async
+// functions and generators are functions but not constructors.
+func (v *Value) IsConstructor() bool {
+	return C.ValueIsConstructor(v.valuePtr()) != 0
 }
 
 // IsObject returns true if this value is an object.
 func (v *Value) IsObject() bool {
-	return v.ctx != nil && C.ValueIsObject(v.ptr) != 0
+	return v.ctx != nil && C.ValueIsObject(v.valuePtr()) != 0
 }
 
 // IsBigInt returns true if this value is a bigint.
 // This is equivalent to `typeof value === 'bigint'` in JS.
 func (v *Value) IsBigInt() bool {
-	return C.ValueIsBigInt(v.ptr) != 0
+	return C.ValueIsBigInt(v.valuePtr()) != 0
 }
 
 // IsBoolean returns true if this value is boolean.
 // This is equivalent to `typeof value === 'boolean'` in JS.
 func (v *Value) IsBoolean() bool {
-	return C.ValueIsBoolean(v.ptr) != 0
+	return C.ValueIsBoolean(v.valuePtr()) != 0
 }
 
 // IsNumber returns true if this value is a number.
 // This is equivalent to `typeof value === 'number'` in JS.
 func (v *Value) IsNumber() bool {
-	return C.ValueIsNumber(v.ptr) != 0
+	return C.ValueIsNumber(v.valuePtr()) != 0
 }
 
-// IsExternal returns true if this value is an `External` object.
+// IsExternal returns true if this value is an `External` object, as created
+// by NewExternal.
 func (v *Value) IsExternal() bool {
-	// TODO(rogchap): requires test case
-	return v.ctx != nil && C.ValueIsExternal(v.ptr) != 0
+	return C.ValueIsExternal(v.valuePtr()) != 0
 }
 
 // IsInt32 returns true if this value is a 32-bit signed integer.
+// Numbers with a fractional part, -0 and integers outside the int32 range
+// return false, so a true result means Int32 converts the value losslessly.
 func (v *Value) IsInt32() bool {
-	return C.ValueIsInt32(v.ptr) != 0
+	return C.ValueIsInt32(v.valuePtr()) != 0
 }
 
 // IsUint32 returns true if this value is a 32-bit unsigned integer.
+// As with IsInt32, a true result means Uint32 converts the value losslessly.
 func (v *Value) IsUint32() bool {
-	return C.ValueIsUint32(v.ptr) != 0
+	return C.ValueIsUint32(v.valuePtr()) != 0
 }
 
 // IsDate returns true if this value is a `Date`.
 func (v *Value) IsDate() bool {
-	return C.ValueIsDate(v.ptr) != 0
+	return C.ValueIsDate(v.valuePtr()) != 0
 }
 
 // IsArgumentsObject returns true if this value is an Arguments object.
 func (v *Value) IsArgumentsObject() bool {
-	return C.ValueIsArgumentsObject(v.ptr) != 0
+	return C.ValueIsArgumentsObject(v.valuePtr()) != 0
 }
 
 // IsBigIntObject returns true if this value is a BigInt object.
 func (v *Value) IsBigIntObject() bool {
-	return C.ValueIsBigIntObject(v.ptr) != 0
+	return C.ValueIsBigIntObject(v.valuePtr()) != 0
 }
 
 // IsNumberObject returns true if this value is a `Number` object.
 func (v *Value) IsNumberObject() bool {
-	return C.ValueIsNumberObject(v.ptr) != 0
+	return C.ValueIsNumberObject(v.valuePtr()) != 0
 }
 
 // IsStringObject returns true if this value is a `String` object.
 func (v *Value) IsStringObject() bool {
-	return C.ValueIsStringObject(v.ptr) != 0
+	return C.ValueIsStringObject(v.valuePtr()) != 0
 }
 
 // IsSymbolObject returns true if this value is a `Symbol` object.
 func (v *Value) IsSymbolObject() bool {
-	return C.ValueIsSymbolObject(v.ptr) != 0
+	return C.ValueIsSymbolObject(v.valuePtr()) != 0
+}
+
+// IsBooleanObject returns true if this value is a `Boolean` object.
+func (v *Value) IsBooleanObject() bool {
+	return C.ValueIsBooleanObject(v.valuePtr()) != 0
+}
+
+// ValueOf unwraps a boxed primitive, e.g. `new Number(5)` or `Object("str")`,
+// returning the primitive it holds. It returns an error if the value is not a
+// Number, String, Boolean, Symbol or BigInt object. Unlike calling `valueOf`
+// in JS, this never runs user code.
+func (v *Value) ValueOf() (*Value, error) {
+	ptr := C.ValueUnboxPrimitive(v.valuePtr())
+	if ptr == nil {
+		return nil, errors.New("v8go: value is not a boxed primitive")
+	}
+	return &Value{ptr: ptr, ctx: v.ctx}, nil
 }
 
 // IsNativeError returns true if this value is a NativeError.
 func (v *Value) IsNativeError() bool {
-	return C.ValueIsNativeError(v.ptr) != 0
+	return C.ValueIsNativeError(v.valuePtr()) != 0
 }
 
 // IsRegExp returns true if this value is a `RegExp`.
 func (v *Value) IsRegExp() bool {
-	return C.ValueIsRegExp(v.ptr) != 0
+	return C.ValueIsRegExp(v.valuePtr()) != 0
 }
 
 // IsAsyncFunc returns true if this value is an async function.
 func (v *Value) IsAsyncFunction() bool {
-	return C.ValueIsAsyncFunction(v.ptr) != 0
+	return C.ValueIsAsyncFunction(v.valuePtr()) != 0
 }
 
 // Is IsGeneratorFunc returns true if this value is a Generator function.
 func (v *Value) IsGeneratorFunction() bool {
-	return C.ValueIsGeneratorFunction(v.ptr) != 0
+	return C.ValueIsGeneratorFunction(v.valuePtr()) != 0
 }
 
 // IsGeneratorObject returns true if this value is a Generator object (iterator).
 func (v *Value) IsGeneratorObject() bool {
-	return C.ValueIsGeneratorObject(v.ptr) != 0
+	return C.ValueIsGeneratorObject(v.valuePtr()) != 0
 }
 
 // IsPromise returns true if this value is a `Promise`.
 func (v *Value) IsPromise() bool {
-	return C.ValueIsPromise(v.ptr) != 0
+	return C.ValueIsPromise(v.valuePtr()) != 0
 }
 
 // IsMap returns true if this value is a `Map`.
 func (v *Value) IsMap() bool {
-	return C.ValueIsMap(v.ptr) != 0
+	return C.ValueIsMap(v.valuePtr()) != 0
 }
 
 // IsSet returns true if this value is a `Set`.
 func (v *Value) IsSet() bool {
-	return C.ValueIsSet(v.ptr) != 0
+	return C.ValueIsSet(v.valuePtr()) != 0
 }
 
 // IsMapIterator returns true if this value is a `Map` Iterator.
 func (v *Value) IsMapIterator() bool {
-	return C.ValueIsMapIterator(v.ptr) != 0
+	return C.ValueIsMapIterator(v.valuePtr()) != 0
 }
 
 // IsSetIterator returns true if this value is a `Set` Iterator.
 func (v *Value) IsSetIterator() bool {
-	return C.ValueIsSetIterator(v.ptr) != 0
+	return C.ValueIsSetIterator(v.valuePtr()) != 0
 }
 
 // IsWeakMap returns true if this value is a `WeakMap`.
 func (v *Value) IsWeakMap() bool {
-	return C.ValueIsWeakMap(v.ptr) != 0
+	return C.ValueIsWeakMap(v.valuePtr()) != 0
 }
 
 // IsWeakSet returns true if this value is a `WeakSet`.
 func (v *Value) IsWeakSet() bool {
-	return C.ValueIsWeakSet(v.ptr) != 0
+	return C.ValueIsWeakSet(v.valuePtr()) != 0
 }
 
 // IsArray returns true if this value is an array.
 // Note that it will return false for a `Proxy` of an array.
 func (v *Value) IsArray() bool {
-	return C.ValueIsArray(v.ptr) != 0
+	return C.ValueIsArray(v.valuePtr()) != 0
 }
 
 // IsArrayBuffer returns true if this value is an `ArrayBuffer`.
 func (v *Value) IsArrayBuffer() bool {
-	return C.ValueIsArrayBuffer(v.ptr) != 0
+	return C.ValueIsArrayBuffer(v.valuePtr()) != 0
 }
 
 // IsArrayBufferView returns true if this value is an `ArrayBufferView`.
 func (v *Value) IsArrayBufferView() bool {
-	return C.ValueIsArrayBufferView(v.ptr) != 0
+	return C.ValueIsArrayBufferView(v.valuePtr()) != 0
 }
 
 // IsTypedArray returns true if this value is one of TypedArrays.
 func (v *Value) IsTypedArray() bool {
-	return C.ValueIsTypedArray(v.ptr) != 0
+	return C.ValueIsTypedArray(v.valuePtr()) != 0
 }
 
 // IsUint8Array returns true if this value is an `Uint8Array`.
 func (v *Value) IsUint8Array() bool {
-	return C.ValueIsUint8Array(v.ptr) != 0
+	return C.ValueIsUint8Array(v.valuePtr()) != 0
 }
 
 // IsUint8ClampedArray returns true if this value is an `Uint8ClampedArray`.
 func (v *Value) IsUint8ClampedArray() bool {
-	return C.ValueIsUint8ClampedArray(v.ptr) != 0
+	return C.ValueIsUint8ClampedArray(v.valuePtr()) != 0
 }
 
 // IsInt8Array returns true if this value is an `Int8Array`.
 func (v *Value) IsInt8Array() bool {
-	return C.ValueIsInt8Array(v.ptr) != 0
+	return C.ValueIsInt8Array(v.valuePtr()) != 0
 }
 
 // IsUint16Array returns true if this value is an `Uint16Array`.
 func (v *Value) IsUint16Array() bool {
-	return C.ValueIsUint16Array(v.ptr) != 0
+	return C.ValueIsUint16Array(v.valuePtr()) != 0
 }
 
 // IsInt16Array returns true if this value is an `Int16Array`.
 func (v *Value) IsInt16Array() bool {
-	return C.ValueIsInt16Array(v.ptr) != 0
+	return C.ValueIsInt16Array(v.valuePtr()) != 0
 }
 
 // IsUint32Array returns true if this value is an `Uint32Array`.
 func (v *Value) IsUint32Array() bool {
-	return C.ValueIsUint32Array(v.ptr) != 0
+	return C.ValueIsUint32Array(v.valuePtr()) != 0
 }
 
 // IsInt32Array returns true if this value is an `Int32Array`.
 func (v *Value) IsInt32Array() bool {
-	return C.ValueIsInt32Array(v.ptr) != 0
+	return C.ValueIsInt32Array(v.valuePtr()) != 0
 }
 
 // IsFloat32Array returns true if this value is a `Float32Array`.
 func (v *Value) IsFloat32Array() bool {
-	return C.ValueIsFloat32Array(v.ptr) != 0
+	return C.ValueIsFloat32Array(v.valuePtr()) != 0
 }
 
 // IsFloat64Array returns true if this value is a `Float64Array`.
 func (v *Value) IsFloat64Array() bool {
-	return C.ValueIsFloat64Array(v.ptr) != 0
+	return C.ValueIsFloat64Array(v.valuePtr()) != 0
 }
 
 // IsBigInt64Array returns true if this value is a `BigInt64Array`.
 func (v *Value) IsBigInt64Array() bool {
-	return C.ValueIsBigInt64Array(v.ptr) != 0
+	return C.ValueIsBigInt64Array(v.valuePtr()) != 0
 }
 
-// IsBigUint64Array returns true if this value is a BigUint64Array`.
+// IsBigUint64Array returns true if this value is a `BigUint64Array`.
 func (v *Value) IsBigUint64Array() bool {
-	return C.ValueIsBigUint64Array(v.ptr) != 0
+	return C.ValueIsBigUint64Array(v.valuePtr()) != 0
 }
 
 // IsDataView returns true if this value is a `DataView`.
 func (v *Value) IsDataView() bool {
-	return C.ValueIsDataView(v.ptr) != 0
+	return C.ValueIsDataView(v.valuePtr()) != 0
 }
 
 // IsSharedArrayBuffer returns true if this value is a `SharedArrayBuffer`.
 func (v *Value) IsSharedArrayBuffer() bool {
-	return C.ValueIsSharedArrayBuffer(v.ptr) != 0
+	return C.ValueIsSharedArrayBuffer(v.valuePtr()) != 0
 }
 
 // IsProxy returns true if this value is a JavaScript `Proxy`.
 func (v *Value) IsProxy() bool {
-	return C.ValueIsProxy(v.ptr) != 0
+	return C.ValueIsProxy(v.valuePtr()) != 0
+}
+
+// IsWeakRef returns true if this value is a `WeakRef`.
+func (v *Value) IsWeakRef() bool {
+	return C.ValueIsWeakRef(v.valuePtr()) != 0
 }
 
-// Release this value.  Using the value after calling this function will result in undefined behavior.
+// Release this value. Calling Release more than once is a no-op, and calling
+// methods of the value after it has been released panics with a clear
+// message. Values held by V8, e.g. as object properties, are not affected.
+// The isolate's shared Undefined and Null values are never released.
 func (v *Value) Release() {
-	C.ValueRelease(v.ptr)
+	if ptr := v.take(); ptr != nil {
+		C.ValueRelease(ptr)
+	}
+}
+
+// take marks v as released and returns its C pointer to release, or nil if v
+// has already been released or must not be released.
+func (v *Value) take() C.ValuePtr {
+	if v.shared {
+		return nil
+	}
+	ptr := v.ptr
+	v.ptr = nil
+	return ptr
+}
+
+// valuePtr returns the C pointer for v, panicking with a clear message if v
+// has been released rather than crashing inside V8.
+func (v *Value) valuePtr() C.ValuePtr {
+	if v.ptr == nil {
+		panic("v8go: use of released Value")
+	}
+	return v.ptr
 }
 
 // IsWasmModuleObject returns true if this value is a `WasmModuleObject`.
 func (v *Value) IsWasmModuleObject() bool {
 	// TODO(rogchap): requires test case
-	return C.ValueIsWasmModuleObject(v.ptr) != 0
+	return C.ValueIsWasmModuleObject(v.valuePtr()) != 0
 }
 
 // IsModuleNamespaceObject returns true if the value is a `Module` Namespace `Object`.
 func (v *Value) IsModuleNamespaceObject() bool {
 	// TODO(rogchap): requires test case
-	return C.ValueIsModuleNamespaceObject(v.ptr) != 0
+	return C.ValueIsModuleNamespaceObject(v.valuePtr()) != 0
 }
 
 // AsObject will cast the value to the Object type. If the value is not an Object
@@ -575,6 +836,33 @@ func (v *Value) AsPromise() (*Promise, error) {
 	return &Promise{&Object{v}}, nil
 }
 
+// AsProxy will cast the value to the Proxy type. If the value is not a Proxy
+// then an error is returned.
+func (v *Value) AsProxy() (*Proxy, error) {
+	if !v.IsProxy() {
+		return nil, errors.New("v8go: value is not a Proxy")
+	}
+	return &Proxy{&Object{v}}, nil
+}
+
+// AsArray will cast the value to the Array type. If the value is not an Array
+// then an error is returned.
+func (v *Value) AsArray() (*Array, error) {
+	if !v.IsArray() {
+		return nil, errors.New("v8go: value is not an Array")
+	}
+	return &Array{&Object{v}}, nil
+}
+
+// AsWeakRef will cast the value to the WeakRef type. If the value is not a
+// WeakRef then an error is returned.
+func (v *Value) AsWeakRef() (*WeakRef, error) {
+	if !v.IsWeakRef() {
+		return nil, errors.New("v8go: value is not a WeakRef")
+	}
+	return &WeakRef{&Object{v}}, nil
+}
+
 func (v *Value) AsException() (*Exception, error) {
 	if !v.IsNativeError() {
 		return nil, errors.New("v8go: value is not an Error")
@@ -603,7 +891,7 @@ func (v *Value) SharedArrayBufferGetContents() ([]byte, func(), error) {
 		return nil, nil, errors.New("v8go: value is not a SharedArrayBuffer")
 	}
 
-	backingStore := C.SharedArrayBufferGetBackingStore(v.ptr)
+	backingStore := C.SharedArrayBufferGetBackingStore(v.valuePtr())
 	release := func() {
 		C.BackingStoreRelease(backingStore)
 	}
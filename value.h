@@ -37,6 +37,9 @@ typedef struct m_value m_value;
 typedef m_value* ValuePtr;
 typedef v8Isolate* IsolatePtr;
 
+typedef struct m_ctx m_ctx;
+typedef m_ctx* ContextPtr;
+
 typedef struct v8BackingStore v8BackingStore;
 typedef v8BackingStore* BackingStorePtr;
 
@@ -52,7 +55,20 @@ typedef struct {
   RtnError error;
 } RtnString;
 
+typedef struct {
+  int32_t value;
+  int failed;
+  RtnError error;
+} RtnInt32;
+
+typedef struct {
+  uint32_t value;
+  int failed;
+  RtnError error;
+} RtnUint32;
+
 void ValueRelease(ValuePtr ptr);
+void ValuesRelease(ValuePtr* ptrs, int count);
 extern RtnString ValueToString(ValuePtr ptr);
 const uint32_t* ValueToArrayIndex(ValuePtr ptr);
 int ValueToBoolean(ValuePtr ptr);
@@ -60,10 +76,24 @@ int32_t ValueToInt32(ValuePtr ptr);
 int64_t ValueToInteger(ValuePtr ptr);
 double ValueToNumber(ValuePtr ptr);
 RtnString ValueToDetailString(ValuePtr ptr);
+RtnString ValueObjectProtoToString(ContextPtr ctx_ptr, ValuePtr ptr);
+const char* ValueDebugString(ValuePtr ptr);
 uint32_t ValueToUint32(ValuePtr ptr);
+RtnInt32 ValueInt32Value(ValuePtr ptr);
+RtnUint32 ValueUint32Value(ValuePtr ptr);
+int64_t ValueBigIntToInt64(ValuePtr ptr, int* lossless);
+uint64_t ValueBigIntToUint64(ValuePtr ptr, int* lossless);
+void* ValueToExternal(ValuePtr ptr);
+uintptr_t ValueToExternalHandle(ValuePtr ptr);
 extern ValueBigInt ValueToBigInt(ValuePtr ptr);
 extern RtnValue ValueToObject(ValuePtr ptr);
+extern RtnValue ValueCoerceToString(ContextPtr ctx_ptr, ValuePtr ptr);
+extern RtnValue ValueCoerceToNumber(ContextPtr ctx_ptr, ValuePtr ptr);
+extern RtnValue ValueCoerceToObject(ContextPtr ctx_ptr, ValuePtr ptr);
+extern RtnValue StringConcat(ValuePtr* strs, int count);
+extern ValuePtr ValueCoerceToBoolean(ValuePtr ptr);
 int ValueSameValue(ValuePtr ptr, ValuePtr otherPtr);
+int ValueGetIdentityHash(ValuePtr ptr);
 int ValueIsUndefined(ValuePtr ptr);
 int ValueIsNull(ValuePtr ptr);
 int ValueIsNullOrUndefined(ValuePtr ptr);
@@ -73,6 +103,7 @@ int ValueIsName(ValuePtr ptr);
 int ValueIsString(ValuePtr ptr);
 int ValueIsSymbol(ValuePtr ptr);
 int ValueIsFunction(ValuePtr ptr);
+int ValueIsConstructor(ValuePtr ptr);
 int ValueIsObject(ValuePtr ptr);
 int ValueIsBigInt(ValuePtr ptr);
 int ValueIsBoolean(ValuePtr ptr);
@@ -86,6 +117,7 @@ int ValueIsBigIntObject(ValuePtr ptr);
 int ValueIsNumberObject(ValuePtr ptr);
 int ValueIsStringObject(ValuePtr ptr);
 int ValueIsSymbolObject(ValuePtr ptr);
+int ValueIsBooleanObject(ValuePtr ptr);
 int ValueIsNativeError(ValuePtr ptr);
 int ValueIsRegExp(ValuePtr ptr);
 int ValueIsAsyncFunction(ValuePtr ptr);
@@ -116,6 +148,8 @@ int ValueIsBigUint64Array(ValuePtr ptr);
 int ValueIsDataView(ValuePtr ptr);
 int ValueIsSharedArrayBuffer(ValuePtr ptr);
 int ValueIsProxy(ValuePtr ptr);
+int ValueIsWeakRef(ValuePtr ptr);
+extern ValuePtr ValueUnboxPrimitive(ValuePtr ptr);
 int ValueIsWasmModuleObject(ValuePtr ptr);
 int ValueIsModuleNamespaceObject(ValuePtr ptr);
 
@@ -124,6 +158,9 @@ extern ValuePtr NewValueUndefined(IsolatePtr iso_ptr);
 extern ValuePtr NewValueInteger(IsolatePtr iso_ptr, int32_t v);
 extern ValuePtr NewValueIntegerFromUnsigned(IsolatePtr iso_ptr, uint32_t v);
 extern RtnValue NewValueString(IsolatePtr iso_ptr, const char* v, int v_length);
+extern RtnValue NewValueInternalizedString(IsolatePtr iso_ptr,
+                                           const char* v,
+                                           int v_length);
 extern ValuePtr NewValueBoolean(IsolatePtr iso_ptr, int v);
 extern ValuePtr NewValueNumber(IsolatePtr iso_ptr, double v);
 extern ValuePtr NewValueBigInt(IsolatePtr iso_ptr, int64_t v);
@@ -132,6 +169,7 @@ extern RtnValue NewValueBigIntFromWords(IsolatePtr iso_ptr,
                                         int sign_bit,
                                         int word_count,
                                         const uint64_t* words);
+extern ValuePtr NewValueExternal(IsolatePtr iso_ptr, uintptr_t v);
 extern ValuePtr NewValueError(IsolatePtr iso_ptr,
                               ErrorTypeIndex idx,
                               const char* message);
@@ -153,13 +191,16 @@ int ObjectHasIdx(ValuePtr ptr, uint32_t idx);
 int ObjectDelete(ValuePtr ptr, const char* key);
 int ObjectDeleteAnyKey(ValuePtr ptr, ValuePtr key);
 int ObjectDeleteIdx(ValuePtr ptr, uint32_t idx);
-extern RtnValue ObjectGetPrototype(ValuePtr ptr);
-extern void ObjectSetPrototype(ValuePtr ptr, ValuePtr proto_ptr);
 
 extern void BackingStoreRelease(BackingStorePtr ptr);
 extern void* BackingStoreData(BackingStorePtr ptr);
 extern size_t BackingStoreByteLength(BackingStorePtr ptr);
 extern BackingStorePtr SharedArrayBufferGetBackingStore(ValuePtr ptr);
+extern BackingStorePtr NewBackingStore(IsolatePtr iso_ptr, size_t byte_length);
+extern RtnValue NewArrayBufferWithBackingStore(ContextPtr ctx_ptr,
+                                               BackingStorePtr ptr);
+extern int ArrayBufferDetach(ValuePtr ptr);
+extern size_t ValueCopyBytes(ValuePtr ptr, void* dest, size_t length);
 
 #ifdef __cplusplus
 }
@@ -11,7 +11,9 @@ import (
 	"math/big"
 	"reflect"
 	"runtime"
+	"runtime/cgo"
 	"testing"
+	"unsafe"
 
 	v8 "github.com/lizc2003/v8go"
 )
@@ -172,6 +174,47 @@ func TestValueDetailString(t *testing.T) {
 	}
 }
 
+func TestValueDebugString(t *testing.T) {
+	t.Parallel()
+	ctx := v8.NewContext(nil)
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	tests := [...]struct {
+		source string
+		out    string
+	}{
+		{`undefined`, "undefined"},
+		{`null`, "null"},
+		{`1.5`, "number"},
+		{`10n`, "bigint"},
+		{`"héllo"`, "string(5)"},
+		{`Symbol("s")`, "symbol"},
+		{`[1, 2, 3]`, "Array(3)"},
+		{`new Uint8Array(1024)`, "Uint8Array(1024)"},
+		{`new DataView(new ArrayBuffer(8), 2)`, "DataView(6)"},
+		{`new ArrayBuffer(16)`, "ArrayBuffer(16)"},
+		{`new Map([[1, 2], [3, 4]])`, "Map(2)"},
+		{`new Set([1])`, "Set(1)"},
+		{`new Promise(() => {})`, "Promise<pending>"},
+		{`Promise.resolve(1)`, "Promise<fulfilled>"},
+		{`Promise.reject(1)`, "Promise<rejected>"},
+		{`(function handler() {})`, "Function handler"},
+		{`(async () => {})`, "AsyncFunction (anonymous)"},
+		{`new (class Point {})`, "Point"},
+		{`({ get x() { throw new Error("getter ran") } })`, "Object"},
+		{`new Proxy({}, { get() { throw new Error("trap ran") } })`, "Proxy"},
+	}
+
+	for _, tt := range tests {
+		result, err := ctx.RunScript(tt.source, "test.js")
+		fatalIf(t, err)
+		if str := result.DebugString(); str != tt.out {
+			t.Errorf("%s: expected %q, got %q", tt.source, tt.out, str)
+		}
+	}
+}
+
 func TestValueBoolean(t *testing.T) {
 	t.Parallel()
 	ctx := v8.NewContext(nil)
@@ -422,6 +465,34 @@ func TestValueUint32(t *testing.T) {
 	}
 }
 
+func TestValueInt32ValueUint32Value(t *testing.T) {
+	t.Parallel()
+	ctx := v8.NewContext(nil)
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, _ := ctx.RunScript("2_147_483_648", "test.js")
+	i32, err := val.Int32Value()
+	fatalIf(t, err)
+	if i32 != -1<<31 {
+		t.Errorf("unexpected value: expected %v, got %v", -1<<31, i32)
+	}
+	val, _ = ctx.RunScript("-1", "test.js")
+	u32, err := val.Uint32Value()
+	fatalIf(t, err)
+	if u32 != 1<<32-1 {
+		t.Errorf("unexpected value: expected %v, got %v", uint32(1<<32-1), u32)
+	}
+
+	val, _ = ctx.RunScript("({ valueOf() { throw new Error('no number') } })", "test.js")
+	if _, err := val.Int32Value(); err == nil || err.Error() != "Error: no number" {
+		t.Errorf("expected error from valueOf, got %v", err)
+	}
+	if _, err := val.Uint32Value(); err == nil || err.Error() != "Error: no number" {
+		t.Errorf("expected error from valueOf, got %v", err)
+	}
+}
+
 func TestValueBigInt(t *testing.T) {
 	t.Parallel()
 	iso := v8.NewIsolate()
@@ -465,6 +536,51 @@ func TestValueBigInt(t *testing.T) {
 	}
 }
 
+func TestValueCoercions(t *testing.T) {
+	t.Parallel()
+	ctx := v8.NewContext(nil)
+	iso := ctx.Isolate()
+	defer iso.Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript("({ toString() { return 'str' }, valueOf() { return 42 } })", "test.js")
+	fatalIf(t, err)
+
+	str, err := val.ToString(ctx)
+	fatalIf(t, err)
+	if !str.IsString() || str.String() != "str" {
+		t.Errorf("unexpected ToString result: %+v", str)
+	}
+	num, err := val.ToNumber(nil)
+	fatalIf(t, err)
+	if !num.IsNumber() || num.Int32() != 42 {
+		t.Errorf("unexpected ToNumber result: %+v", num)
+	}
+	if b := val.ToBoolean(); !b.IsTrue() {
+		t.Errorf("unexpected ToBoolean result: %+v", b)
+	}
+	zero, _ := v8.NewValue(iso, int32(0))
+	if b := zero.ToBoolean(); !b.IsFalse() {
+		t.Errorf("unexpected ToBoolean result: %+v", b)
+	}
+
+	prim, _ := v8.NewValue(iso, "foo")
+	obj, err := prim.ToObject(ctx)
+	fatalIf(t, err)
+	if !obj.IsStringObject() {
+		t.Errorf("expected a String object, got %+v", obj)
+	}
+	if _, err := v8.Null(iso).ToObject(ctx); err == nil {
+		t.Error("expected an error converting null to an object")
+	}
+
+	throws, err := ctx.RunScript("({ toString() { throw new Error('no string') } })", "test.js")
+	fatalIf(t, err)
+	if _, err := throws.ToString(ctx); err == nil || err.Error() != "Error: no string" {
+		t.Errorf("expected error from toString, got %v", err)
+	}
+}
+
 func TestValueObject(t *testing.T) {
 	t.Parallel()
 
@@ -587,6 +703,156 @@ func TestValueSameValue(t *testing.T) {
 	}
 }
 
+func TestValueGetIdentityHash(t *testing.T) {
+	t.Parallel()
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	// Each pair is evaluated separately, so objects are compared through
+	// different Values.
+	tests := [...]struct {
+		a, b string
+	}{
+		{"globalThis.obj = {}; obj", "obj"},
+		{"globalThis.sym = Symbol('s'); sym", "sym"},
+		{"'str'", "'s' + 'tr'"},
+		{"1.5", "3 / 2"},
+		{"NaN", "0 / 0"},
+		{"10n", "5n * 2n"},
+		{"true", "!false"},
+		{"null", "null"},
+		{"undefined", "void 0"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.a, func(t *testing.T) {
+			a, err := ctx.RunScript(tt.a, "a.js")
+			fatalIf(t, err)
+			b, err := ctx.RunScript(tt.b, "b.js")
+			fatalIf(t, err)
+			if !a.SameValue(b) {
+				t.Fatalf("expected `%s` and `%s` to be the same value", tt.a, tt.b)
+			}
+			if a.GetIdentityHash() != b.GetIdentityHash() {
+				t.Errorf("expected `%s` and `%s` to have the same hash, got %d and %d",
+					tt.a, tt.b, a.GetIdentityHash(), b.GetIdentityHash())
+			}
+		})
+	}
+
+	zero, err := ctx.RunScript("0", "zero.js")
+	fatalIf(t, err)
+	negZero, err := ctx.RunScript("-0", "zero.js")
+	fatalIf(t, err)
+	if zero.GetIdentityHash() == negZero.GetIdentityHash() {
+		t.Error("expected 0 and -0 to have different hashes")
+	}
+}
+
+func TestValueValueOf(t *testing.T) {
+	t.Parallel()
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	tests := [...]struct {
+		source string
+		is     func(*v8.Value) bool
+		want   string
+	}{
+		{"new Number(5)", (*v8.Value).IsNumber, "5"},
+		{"new String('str')", (*v8.Value).IsString, "str"},
+		{"new Boolean(false)", (*v8.Value).IsBoolean, "false"},
+		{"Object(Symbol('sym'))", (*v8.Value).IsSymbol, "Symbol(sym)"},
+		{"Object(10n)", (*v8.Value).IsBigInt, "10"},
+	}
+	for _, tt := range tests {
+		val, err := ctx.RunScript(tt.source, "test.js")
+		fatalIf(t, err)
+		prim, err := val.ValueOf()
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.source, err)
+			continue
+		}
+		if !tt.is(prim) || prim.DetailString() != tt.want {
+			t.Errorf("%s: unexpected primitive %q", tt.source, prim.DetailString())
+		}
+	}
+
+	val, err := ctx.RunScript("({valueOf() { throw new Error('called') }})", "test.js")
+	fatalIf(t, err)
+	if _, err := val.ValueOf(); err == nil {
+		t.Error("expected error unwrapping a plain object")
+	}
+}
+
+func TestValueIsInt32Uint32(t *testing.T) {
+	t.Parallel()
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	tests := [...]struct {
+		source        string
+		int32, uint32 bool
+	}{
+		{"0", true, true},
+		{"-0", false, false},
+		{"-1", true, false},
+		{"1.5", false, false},
+		{"2**31 - 1", true, true},
+		{"2**31", false, true},
+		{"-(2**31)", true, false},
+		{"2**32", false, false},
+		{"NaN", false, false},
+		{"'1'", false, false},
+		{"1n", false, false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.source, func(t *testing.T) {
+			val, err := ctx.RunScript(tt.source, "test.js")
+			fatalIf(t, err)
+			if got := val.IsInt32(); got != tt.int32 {
+				t.Errorf("IsInt32() = %v, want %v", got, tt.int32)
+			}
+			if got := val.IsUint32(); got != tt.uint32 {
+				t.Errorf("IsUint32() = %v, want %v", got, tt.uint32)
+			}
+		})
+	}
+}
+
+func ExampleValue_IsInt32() {
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	// Keep integral numbers as Go integers and only use float64 for numbers
+	// that have a fractional part or are out of range.
+	toGo := func(v *v8.Value) interface{} {
+		switch {
+		case v.IsInt32():
+			return int(v.Int32())
+		case v.IsUint32():
+			return int64(v.Uint32())
+		case v.IsNumber():
+			return v.Number()
+		}
+		return v.String()
+	}
+
+	for _, src := range []string{"42", "2**31", "0.5"} {
+		val, _ := ctx.RunScript(src, "example.js")
+		fmt.Printf("%T %v\n", toGo(val), toGo(val))
+	}
+	// Output:
+	// int 42
+	// int64 2147483648
+	// float64 0.5
+}
+
 func TestValueIsXXX(t *testing.T) {
 	t.Parallel()
 	iso := v8.NewIsolate()
@@ -648,6 +914,8 @@ func TestValueIsXXX(t *testing.T) {
 		{"new String", (*v8.Value).IsStringObject},
 		{"Object('')", (*v8.Value).IsStringObject},
 		{"Object(Symbol())", (*v8.Value).IsSymbolObject},
+		{"new Boolean", (*v8.Value).IsBooleanObject},
+		{"Object(true)", (*v8.Value).IsBooleanObject},
 		{"Error()", (*v8.Value).IsNativeError},
 		{"TypeError()", (*v8.Value).IsNativeError},
 		{"SyntaxError()", (*v8.Value).IsNativeError},
@@ -657,11 +925,16 @@ func TestValueIsXXX(t *testing.T) {
 		{"let v = async () => {}; v", (*v8.Value).IsAsyncFunction},
 		{"function* v(){}; v", (*v8.Value).IsGeneratorFunction},
 		{"function* v(){}; v()", (*v8.Value).IsGeneratorObject},
+		{"async function* v(){}; v", (*v8.Value).IsAsyncFunction},
+		{"async function* v(){}; v", (*v8.Value).IsGeneratorFunction},
+		{"async function* v(){}; v()", (*v8.Value).IsGeneratorObject},
 		{"new Promise(()=>{})", (*v8.Value).IsPromise},
 		{"new Map", (*v8.Value).IsMap},
 		{"new Set", (*v8.Value).IsSet},
 		{"(new Map).entries()", (*v8.Value).IsMapIterator},
+		{"(new Map).keys()", (*v8.Value).IsMapIterator},
 		{"(new Set).entries()", (*v8.Value).IsSetIterator},
+		{"(new Set).values()", (*v8.Value).IsSetIterator},
 		{"new WeakMap", (*v8.Value).IsWeakMap},
 		{"new WeakSet", (*v8.Value).IsWeakSet},
 		{"new Array", (*v8.Value).IsArray},
@@ -703,6 +976,79 @@ func TestValueIsXXX(t *testing.T) {
 	}
 }
 
+func TestValueIsConstructor(t *testing.T) {
+	t.Parallel()
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	tests := [...]struct {
+		source string
+		want   bool
+	}{
+		{"(function () {})", true},
+		{"(class {})", true},
+		{"Date", true},
+		{"(function () {}).bind(null)", true},
+		{"(() => {})", false},
+		{"({ m() {} }).m", false},
+		{"(async function () {})", false},
+		{"(function* () {})", false},
+		{"Math.max", false},
+		{"({})", false},
+		{"42", false},
+	}
+	for _, tt := range tests {
+		val, err := ctx.RunScript(tt.source, "test.js")
+		fatalIf(t, err)
+		if got := val.IsConstructor(); got != tt.want {
+			t.Errorf("IsConstructor() of %s: got %v, want %v", tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestValueTypedArrayKinds(t *testing.T) {
+	t.Parallel()
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	kinds := [...]struct {
+		name   string
+		assert func(*v8.Value) bool
+	}{
+		{"Uint8Array", (*v8.Value).IsUint8Array},
+		{"Uint8ClampedArray", (*v8.Value).IsUint8ClampedArray},
+		{"Int8Array", (*v8.Value).IsInt8Array},
+		{"Uint16Array", (*v8.Value).IsUint16Array},
+		{"Int16Array", (*v8.Value).IsInt16Array},
+		{"Uint32Array", (*v8.Value).IsUint32Array},
+		{"Int32Array", (*v8.Value).IsInt32Array},
+		{"Float32Array", (*v8.Value).IsFloat32Array},
+		{"Float64Array", (*v8.Value).IsFloat64Array},
+		{"BigInt64Array", (*v8.Value).IsBigInt64Array},
+		{"BigUint64Array", (*v8.Value).IsBigUint64Array},
+	}
+	for _, kind := range kinds {
+		val, err := ctx.RunScript("new "+kind.name+"(4)", "test.js")
+		fatalIf(t, err)
+		if !val.IsTypedArray() {
+			t.Errorf("expected %s to be a typed array", kind.name)
+		}
+		for _, other := range kinds {
+			if got, want := other.assert(val), other.name == kind.name; got != want {
+				t.Errorf("Is%s() of a %s: got %v, want %v", other.name, kind.name, got, want)
+			}
+		}
+	}
+
+	val, err := ctx.RunScript("new DataView(new ArrayBuffer(4))", "test.js")
+	fatalIf(t, err)
+	if val.IsTypedArray() {
+		t.Error("expected a DataView not to be a typed array")
+	}
+}
+
 func TestValueMarshalJSON(t *testing.T) {
 	t.Parallel()
 	iso := v8.NewIsolate()
@@ -817,3 +1163,232 @@ func TestValueArrayBufferContents(t *testing.T) {
 		t.Fatalf("Expected an error trying call SharedArrayBufferGetContents on value of incorrect type")
 	}
 }
+
+func TestValueRelease(t *testing.T) {
+	t.Parallel()
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+
+	val, err := v8.NewValue(iso, "foo")
+	fatalIf(t, err)
+	val.Release()
+	// A second Release is a no-op rather than a double free.
+	val.Release()
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("expected a panic using a released value")
+		}
+		if msg := fmt.Sprint(r); msg != "v8go: use of released Value" {
+			t.Errorf("unexpected panic message: %q", msg)
+		}
+	}()
+	_ = val.String()
+}
+
+func TestValueReleaseCallSites(t *testing.T) {
+	t.Parallel()
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+	iso := ctx.Isolate()
+
+	run := func(source string) *v8.Value {
+		val, err := ctx.RunScript(source, "release.js")
+		fatalIf(t, err)
+		return val
+	}
+	released := func(source string) *v8.Value {
+		val := run(source)
+		val.Release()
+		return val
+	}
+	tests := []struct {
+		name string
+		use  func()
+	}{
+		{"Function.Call", func() {
+			fn, err := run("(() => 1)").AsFunction()
+			fatalIf(t, err)
+			fn.Release()
+			fn.Call(v8.Undefined(iso))
+		}},
+		{"Function.Call argument", func() {
+			fn, err := run("(x => x)").AsFunction()
+			fatalIf(t, err)
+			fn.Call(v8.Undefined(iso), released("1"))
+		}},
+		{"Promise.State", func() {
+			p, err := run("Promise.resolve(1)").AsPromise()
+			fatalIf(t, err)
+			p.Release()
+			p.State()
+		}},
+		{"Array.Length", func() {
+			a, err := run("[1, 2]").AsArray()
+			fatalIf(t, err)
+			a.Release()
+			a.Length()
+		}},
+		{"Object.Set", func() {
+			ctx.Global().Set("x", released("({})"))
+		}},
+		{"NewProxy", func() {
+			target, err := run("({})").AsObject()
+			fatalIf(t, err)
+			handler, err := run("({})").AsObject()
+			fatalIf(t, err)
+			target.Release()
+			v8.NewProxy(ctx, target, handler)
+		}},
+	}
+	for _, tt := range tests {
+		r := recoverPanic(tt.use)
+		if msg := fmt.Sprint(r); msg != "v8go: use of released Value" {
+			t.Errorf("%s: expected a panic using a released value, got %v", tt.name, r)
+		}
+	}
+
+	// The shared values of the isolate are never released.
+	v8.Undefined(iso).Release()
+	v8.Null(iso).Release()
+	if !v8.Undefined(iso).IsUndefined() || !v8.Null(iso).IsNull() {
+		t.Error("expected Undefined and Null to be usable after releasing them")
+	}
+}
+
+func TestValueObjectProtoToString(t *testing.T) {
+	t.Parallel()
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	tests := [...]struct {
+		source string
+		tag    string
+	}{
+		{"undefined", "[object Undefined]"},
+		{"null", "[object Null]"},
+		{"42", "[object Number]"},
+		{"'str'", "[object String]"},
+		{"[]", "[object Array]"},
+		{"new Map()", "[object Map]"},
+		{"(function() {})", "[object Function]"},
+		{"({[Symbol.toStringTag]: 'Custom'})", "[object Custom]"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.source, func(t *testing.T) {
+			val, err := ctx.RunScript(tt.source, "tag.js")
+			fatalIf(t, err)
+			tag, err := val.ObjectProtoToString(nil)
+			fatalIf(t, err)
+			if tag != tt.tag {
+				t.Errorf("expected %q, got %q", tt.tag, tag)
+			}
+		})
+	}
+
+	val, err := ctx.RunScript(`({get [Symbol.toStringTag]() { throw new Error("no tag") }})`, "tag.js")
+	fatalIf(t, err)
+	if _, err := val.ObjectProtoToString(nil); err == nil {
+		t.Error("expected error from the toStringTag getter, got <nil>")
+	}
+}
+
+var externalSentinel byte
+
+func TestValueExternal(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+	iso := ctx.Isolate()
+
+	type service struct{ name string }
+	h := cgo.NewHandle(&service{name: "users"})
+	defer h.Delete()
+
+	ext := v8.NewExternalHandle(iso, h)
+	if !ext.IsExternal() || ext.IsObject() {
+		t.Fatalf("expected an External value, got %v", ext)
+	}
+	fn, err := ctx.RunScript(`(handle) => () => handle`, "")
+	fatalIf(t, err)
+	wrap, _ := fn.AsFunction()
+	closure, err := wrap.Call(v8.Undefined(iso), ext)
+	fatalIf(t, err)
+	call, _ := closure.AsFunction()
+	got, err := call.Call(v8.Undefined(iso))
+	fatalIf(t, err)
+	gotHandle, err := got.ExternalHandle()
+	fatalIf(t, err)
+	if svc := gotHandle.Value().(*service); svc.name != "users" {
+		t.Errorf("expected the users service, got %q", svc.name)
+	}
+
+	// A package-level variable is never freed or moved, so it can stand in for
+	// C memory here.
+	p := unsafe.Pointer(&externalSentinel)
+	if got := v8.NewExternal(iso, p).External(); got != p {
+		t.Errorf("expected pointer %p, got %p", p, got)
+	}
+
+	str, _ := v8.NewValue(iso, "not external")
+	if str.IsExternal() || str.External() != nil {
+		t.Error("expected a string not to be an External")
+	}
+	if _, err := str.ExternalHandle(); err == nil {
+		t.Error("expected error getting the handle of a string, got <nil>")
+	}
+}
+
+func TestValueInt64Uint64(t *testing.T) {
+	t.Parallel()
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	tests := [...]struct {
+		source string
+		i64    int64
+		i64Err bool
+		u64    uint64
+		u64Err bool
+	}{
+		{"42", 42, false, 42, false},
+		{"-7", -7, false, 0, true},
+		{"2 ** 53", 1 << 53, false, 1 << 53, false},
+		{"1.5", 0, true, 0, true},
+		{"NaN", 0, true, 0, true},
+		{"Infinity", 0, true, 0, true},
+		{"2 ** 64", 0, true, 0, true},
+		{"9223372036854775807n", math.MaxInt64, false, math.MaxInt64, false},
+		{"-9223372036854775808n", math.MinInt64, false, 0, true},
+		{"18446744073709551615n", 0, true, math.MaxUint64, false},
+		{"2n ** 64n", 0, true, 0, true},
+		{"'42'", 0, true, 0, true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.source, func(t *testing.T) {
+			val, err := ctx.RunScript(tt.source, "int64.js")
+			fatalIf(t, err)
+
+			i, err := val.Int64()
+			if tt.i64Err != (err != nil) {
+				t.Errorf("Int64: unexpected error %v", err)
+			} else if i != tt.i64 {
+				t.Errorf("Int64: expected %d, got %d", tt.i64, i)
+			}
+			u, err := val.Uint64()
+			if tt.u64Err != (err != nil) {
+				t.Errorf("Uint64: unexpected error %v", err)
+			} else if u != tt.u64 {
+				t.Errorf("Uint64: expected %d, got %d", tt.u64, u)
+			}
+		})
+	}
+}
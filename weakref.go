@@ -0,0 +1,214 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+// #include "context.h"
+import "C"
+import "errors"
+
+// WeakRef is a JavaScript WeakRef object, which holds a reference to a target
+// object without keeping it alive.
+type WeakRef struct {
+	*Object
+}
+
+// Deref returns the target of the WeakRef, or undefined if the target has
+// been garbage collected. The built-in deref is called even if a script
+// replaced it.
+func (w *WeakRef) Deref() (*Value, error) {
+	return callBuiltin(w.ctx, C.INTRINSIC_WEAK_REF_DEREF, w)
+}
+
+// NewFinalizationRegistry creates a JavaScript FinalizationRegistry whose
+// cleanup callback calls cleanup with the held value of each registered
+// object that has been garbage collected; this is equivalent to
+// `new FinalizationRegistry(cleanup)` in JS.
+//
+// Cleanup callbacks never run during garbage collection or in the middle of
+// script execution. V8 schedules them as a task once a collected target is
+// found, and the task runs during the next [Context.PerformMicrotaskCheckpoint],
+// after the microtask queue has been drained. Objects that were the target of
+// a new WeakRef or a WeakRef.deref call are kept alive until the end of the
+// current checkpoint. The order in which held values are passed to cleanup is
+// unspecified.
+func NewFinalizationRegistry(ctx *Context, cleanup func(heldValue *Value)) (*Object, error) {
+	if ctx == nil {
+		return nil, errors.New("v8go: Context is required")
+	}
+	if cleanup == nil {
+		return nil, errors.New("v8go: cleanup callback is required")
+	}
+
+	cb := NewFunctionTemplate(ctx.iso, func(info *FunctionCallbackInfo) *Value {
+		if args := info.Args(); len(args) > 0 {
+			cleanup(args[0])
+		} else {
+			cleanup(Undefined(ctx.iso))
+		}
+		return nil
+	}).GetFunction(ctx)
+	defer cb.Release()
+	return newBuiltin(ctx, C.INTRINSIC_FINALIZATION_REGISTRY, cb)
+}
+
+// newBuiltin calls the built-in constructor ctor of ctx with args. The
+// constructor is the intrinsic of the context, so scripts can't intercept
+// the call by replacing the global.
+func newBuiltin(ctx *Context, ctor C.IntrinsicIndex, args ...Valuer) (*Object, error) {
+	fn, err := ctx.intrinsic(ctor)
+	if err != nil {
+		return nil, err
+	}
+	return fn.NewInstance(args...)
+}
+
+// callBuiltin calls the built-in method of ctx with recv as `this`, like
+// newBuiltin, rather than looking the method up on recv.
+func callBuiltin(ctx *Context, method C.IntrinsicIndex, recv Valuer, args ...Valuer) (*Value, error) {
+	fn, err := ctx.intrinsic(method)
+	if err != nil {
+		return nil, err
+	}
+	return fn.Call(recv, args...)
+}
+
+// errWeakKey is returned for a primitive key of a WeakMap or value of a
+// WeakSet.
+var errWeakKey = errors.New("v8go: keys of a WeakMap or WeakSet must be objects")
+
+// weakKey returns key as a value, or errWeakKey if it is not an object.
+func weakKey(key Valuer) (*Value, error) {
+	if key == nil || !key.value().IsObject() {
+		return nil, errWeakKey
+	}
+	return key.value(), nil
+}
+
+// WeakMap is a JavaScript WeakMap, which associates values with objects
+// without keeping the objects alive: an entry is removed once its key has
+// been garbage collected, e.g. to attach native metadata to JS objects. Keys
+// must be objects; the methods return an error for primitive keys, where JS
+// would throw a TypeError or return false.
+type WeakMap struct {
+	*Object
+}
+
+// NewWeakMap creates an empty WeakMap; this is equivalent to `new WeakMap()`
+// in JS.
+func NewWeakMap(ctx *Context) (*WeakMap, error) {
+	if ctx == nil {
+		return nil, errors.New("v8go: Context is required")
+	}
+	obj, err := newBuiltin(ctx, C.INTRINSIC_WEAK_MAP)
+	if err != nil {
+		return nil, err
+	}
+	return &WeakMap{obj}, nil
+}
+
+// Get returns the value associated with key, or undefined if there is none.
+func (m *WeakMap) Get(key Valuer) (*Value, error) {
+	k, err := weakKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return callBuiltin(m.ctx, C.INTRINSIC_WEAK_MAP_GET, m, k)
+}
+
+// Set associates value with key, replacing any value it had.
+func (m *WeakMap) Set(key, value Valuer) error {
+	k, err := weakKey(key)
+	if err != nil {
+		return err
+	}
+	rtn, err := callBuiltin(m.ctx, C.INTRINSIC_WEAK_MAP_SET, m, k, value)
+	if err != nil {
+		return err
+	}
+	rtn.Release()
+	return nil
+}
+
+// Has returns whether key has an associated value.
+func (m *WeakMap) Has(key Valuer) (bool, error) {
+	k, err := weakKey(key)
+	if err != nil {
+		return false, err
+	}
+	return weakResult(callBuiltin(m.ctx, C.INTRINSIC_WEAK_MAP_HAS, m, k))
+}
+
+// Delete removes the value associated with key, and returns whether there
+// was one.
+func (m *WeakMap) Delete(key Valuer) (bool, error) {
+	k, err := weakKey(key)
+	if err != nil {
+		return false, err
+	}
+	return weakResult(callBuiltin(m.ctx, C.INTRINSIC_WEAK_MAP_DELETE, m, k))
+}
+
+// WeakSet is a JavaScript WeakSet, which holds objects without keeping them
+// alive: an object is removed once it has been garbage collected, e.g. to mark
+// JS objects as seen by native code. Values must be objects; the methods
+// return an error for primitive values, where JS would throw a TypeError or
+// return false.
+type WeakSet struct {
+	*Object
+}
+
+// NewWeakSet creates an empty WeakSet; this is equivalent to `new WeakSet()`
+// in JS.
+func NewWeakSet(ctx *Context) (*WeakSet, error) {
+	if ctx == nil {
+		return nil, errors.New("v8go: Context is required")
+	}
+	obj, err := newBuiltin(ctx, C.INTRINSIC_WEAK_SET)
+	if err != nil {
+		return nil, err
+	}
+	return &WeakSet{obj}, nil
+}
+
+// Add adds value to the set.
+func (s *WeakSet) Add(value Valuer) error {
+	v, err := weakKey(value)
+	if err != nil {
+		return err
+	}
+	rtn, err := callBuiltin(s.ctx, C.INTRINSIC_WEAK_SET_ADD, s, v)
+	if err != nil {
+		return err
+	}
+	rtn.Release()
+	return nil
+}
+
+// Has returns whether value is in the set.
+func (s *WeakSet) Has(value Valuer) (bool, error) {
+	v, err := weakKey(value)
+	if err != nil {
+		return false, err
+	}
+	return weakResult(callBuiltin(s.ctx, C.INTRINSIC_WEAK_SET_HAS, s, v))
+}
+
+// Delete removes value from the set, and returns whether it was in the set.
+func (s *WeakSet) Delete(value Valuer) (bool, error) {
+	v, err := weakKey(value)
+	if err != nil {
+		return false, err
+	}
+	return weakResult(callBuiltin(s.ctx, C.INTRINSIC_WEAK_SET_DELETE, s, v))
+}
+
+// weakResult converts the boolean result of a method call.
+func weakResult(val *Value, err error) (bool, error) {
+	if err != nil {
+		return false, err
+	}
+	defer val.Release()
+	return val.Boolean(), nil
+}
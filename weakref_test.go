@@ -0,0 +1,190 @@
+// Copyright 2026 the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"testing"
+
+	v8 "github.com/lizc2003/v8go"
+)
+
+func TestWeakRefDeref(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`var target = {name: "target"}; new WeakRef(target)`, "weakref.js")
+	fatalIf(t, err)
+	if !val.IsWeakRef() {
+		t.Fatal("expected value to be a WeakRef")
+	}
+	ref, err := val.AsWeakRef()
+	fatalIf(t, err)
+
+	target, err := ctx.Global().Get("target")
+	fatalIf(t, err)
+	got, err := ref.Deref()
+	fatalIf(t, err)
+	if !got.SameValue(target) {
+		t.Errorf("expected Deref to return the target, got %v", got)
+	}
+
+	_, err = ctx.RunScript(`WeakRef.prototype.deref = () => "intercepted"`, "tamper.js")
+	fatalIf(t, err)
+	if got, err := ref.Deref(); err != nil || !got.SameValue(target) {
+		t.Errorf("expected the built-in deref, got %v, %v", got, err)
+	}
+
+	if _, err := target.AsWeakRef(); err == nil {
+		t.Error("expected error casting a plain object to WeakRef")
+	}
+}
+
+func TestFinalizationRegistry(t *testing.T) {
+	t.Parallel()
+
+	iso := v8.NewIsolate()
+	defer iso.Dispose()
+	ctx := v8.NewContext(iso)
+	defer ctx.Close()
+
+	var held []string
+	registry, err := v8.NewFinalizationRegistry(ctx, func(heldValue *v8.Value) {
+		held = append(held, heldValue.String())
+	})
+	fatalIf(t, err)
+	fatalIf(t, ctx.Global().Set("registry", registry))
+
+	_, err = ctx.RunScript(`(() => { registry.register({}, "collected") })()`, "registry.js")
+	fatalIf(t, err)
+
+	ctx.PerformMicrotaskCheckpoint()
+	iso.LowMemoryNotification()
+	ctx.PerformMicrotaskCheckpoint()
+
+	if len(held) != 1 || held[0] != "collected" {
+		t.Errorf("expected cleanup to run once with the held value, got %v", held)
+	}
+}
+
+func TestWeakMap(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContext()
+	iso := ctx.Isolate()
+	defer iso.Dispose()
+	defer ctx.Close()
+
+	m, err := v8.NewWeakMap(ctx)
+	fatalIf(t, err)
+	if !m.IsWeakMap() {
+		t.Fatal("expected value to be a WeakMap")
+	}
+	key, err := ctx.RunScript(`({})`, "")
+	fatalIf(t, err)
+	meta, err := v8.NewValue(iso, "meta")
+	fatalIf(t, err)
+
+	fatalIf(t, m.Set(key, meta))
+	got, err := m.Get(key)
+	fatalIf(t, err)
+	if got.String() != "meta" {
+		t.Errorf("expected the associated value, got %v", got)
+	}
+	if has, err := m.Has(key); err != nil || !has {
+		t.Errorf("expected Has to be true, got %v, %v", has, err)
+	}
+	fatalIf(t, ctx.Global().Set("m", m))
+	val, err := ctx.RunScript(`m instanceof WeakMap`, "")
+	fatalIf(t, err)
+	if !val.Boolean() {
+		t.Error("expected the WeakMap to be usable from JS")
+	}
+	if deleted, err := m.Delete(key); err != nil || !deleted {
+		t.Errorf("expected Delete to remove the entry, got %v, %v", deleted, err)
+	}
+	got, err = m.Get(key)
+	fatalIf(t, err)
+	if !got.IsUndefined() {
+		t.Errorf("expected undefined after Delete, got %v", got)
+	}
+
+	if err := m.Set(meta, meta); err == nil {
+		t.Error("expected error setting a primitive key")
+	}
+	if _, err := m.Has(meta); err == nil {
+		t.Error("expected error checking a primitive key")
+	}
+}
+
+func TestWeakSet(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContext()
+	iso := ctx.Isolate()
+	defer iso.Dispose()
+	defer ctx.Close()
+
+	s, err := v8.NewWeakSet(ctx)
+	fatalIf(t, err)
+	if !s.IsWeakSet() {
+		t.Fatal("expected value to be a WeakSet")
+	}
+	obj, err := ctx.RunScript(`({})`, "")
+	fatalIf(t, err)
+
+	if has, err := s.Has(obj); err != nil || has {
+		t.Errorf("expected Has to be false before Add, got %v, %v", has, err)
+	}
+	fatalIf(t, s.Add(obj))
+	if has, err := s.Has(obj); err != nil || !has {
+		t.Errorf("expected Has to be true after Add, got %v, %v", has, err)
+	}
+	if deleted, err := s.Delete(obj); err != nil || !deleted {
+		t.Errorf("expected Delete to remove the object, got %v, %v", deleted, err)
+	}
+
+	num, err := v8.NewValue(iso, int32(1))
+	fatalIf(t, err)
+	if err := s.Add(num); err == nil {
+		t.Error("expected error adding a primitive value")
+	}
+}
+
+func TestWeakCollectionsIgnoreReplacedBuiltins(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	// The built-ins are read the first time v8go needs one.
+	m, err := v8.NewWeakMap(ctx)
+	fatalIf(t, err)
+
+	_, err = ctx.RunScript(`
+		WeakMap.prototype.get = () => "intercepted";
+		WeakSet.prototype.has = () => false;
+		globalThis.WeakMap = function () { throw new Error("replaced"); };
+		globalThis.WeakSet = function () { throw new Error("replaced"); };
+	`, "tamper.js")
+	fatalIf(t, err)
+	key, err := ctx.RunScript(`({})`, "")
+	fatalIf(t, err)
+
+	fatalIf(t, m.Set(key, key))
+	if got, err := m.Get(key); err != nil || !got.SameValue(key) {
+		t.Errorf("expected the built-in get, got %v, %v", got, err)
+	}
+
+	s, err := v8.NewWeakSet(ctx)
+	fatalIf(t, err)
+	fatalIf(t, s.Add(key))
+	if has, err := s.Has(key); err != nil || !has {
+		t.Errorf("expected the built-in has, got %v, %v", has, err)
+	}
+}